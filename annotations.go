@@ -0,0 +1,170 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errInvalidAnnotations = errors.New("invalid maintainer annotations")
+
+// annotationsFiles are the scorecard configuration files maintainers annotate the checks in, in order of precedence.
+var annotationsFiles = []string{"scorecard.yml", ".scorecard.yml", ".github/scorecard.yml"}
+
+// annotationReasons explain the reasons maintainers can annotate a check with.
+var annotationReasons = map[string]string{
+	"test-data":      "The files detected are only used for testing.",
+	"remediated":     "The issue detected has been remediated.",
+	"not-applicable": "The check is not applicable to this project.",
+	"not-supported":  "The check does not support the setup of this project.",
+	"not-detected":   "The check does not detect the approach of this project.",
+}
+
+// annotationsConfig is the subset of the scorecard configuration file holding the maintainer annotations.
+type annotationsConfig struct {
+	Annotations []struct {
+		Checks  []string `yaml:"checks"`
+		Reasons []struct {
+			Reason string `yaml:"reason"`
+		} `yaml:"reasons"`
+	} `yaml:"annotations"`
+}
+
+// checkAnnotations are the explanations of the maintainer annotations of each check.
+type checkAnnotations map[string][]string
+
+// readAnnotations is a function to read the maintainer annotations of the first scorecard configuration file
+// of the workspace. It returns nil when there is none.
+func readAnnotations(workspace string) (checkAnnotations, error) {
+	for _, name := range annotationsFiles {
+		file := filepath.Join(workspace, name)
+		data, err := ioutil.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file, err)
+		}
+		return parseAnnotations(file, data)
+	}
+	return nil, nil
+}
+
+// parseAnnotations is a function to parse and validate the maintainer annotations of a scorecard configuration file.
+func parseAnnotations(file string, data []byte) (checkAnnotations, error) {
+	var config annotationsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", file, err)
+	}
+	annotations := checkAnnotations{}
+	for i, annotation := range config.Annotations {
+		if len(annotation.Checks) == 0 || len(annotation.Reasons) == 0 {
+			return nil, fmt.Errorf("%w: %s: annotation %d needs checks and reasons", errInvalidAnnotations, file, i+1)
+		}
+		for _, reason := range annotation.Reasons {
+			explanation, ok := annotationReasons[reason.Reason]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: unknown reason %q in annotation %d", errInvalidAnnotations, file,
+					reason.Reason, i+1)
+			}
+			for _, name := range annotation.Checks {
+				check, err := canonicalCheckName(name)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %v", errInvalidAnnotations, file, err)
+				}
+				annotations[check] = append(annotations[check], explanation)
+			}
+		}
+	}
+	return annotations, nil
+}
+
+// annotateResults is a function to add the maintainer annotations of the checks to their JSON results,
+// in the annotations of the checks, and to the messages of their SARIF results.
+func annotateResults(outputs []resultsOutput, annotations checkAnnotations) error {
+	for _, output := range outputs {
+		var err error
+		switch output.format {
+		case "json":
+			err = annotateResultsJSON(output.file, annotations)
+		case sarif:
+			err = annotateResultsSARIF(output.file, annotations)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotateResultsJSON is a function to set the annotations of the annotated checks of JSON results.
+func annotateResultsJSON(file string, annotations checkAnnotations) error {
+	var results map[string]interface{}
+	if err := readJSONFile(file, &results); err != nil {
+		return err
+	}
+	checks, _ := results["checks"].([]interface{})
+	for _, c := range checks {
+		check, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := check["name"].(string)
+		if explanations, ok := annotations[name]; ok {
+			check["annotations"] = explanations
+		}
+	}
+	return writeJSONFile(file, results)
+}
+
+// annotateResultsSARIF is a function to append the annotations of the checks to the messages of their results,
+// so that the code scanning alerts show them.
+func annotateResultsSARIF(file string, annotations checkAnnotations) error {
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return err
+	}
+	runs, _ := log["runs"].([]interface{})
+	for _, r := range runs {
+		run, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		checkNames := sarifCheckNames(run)
+		results, _ := run["results"].([]interface{})
+		for _, res := range results {
+			result, ok := res.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ruleID, _ := result["ruleId"].(string)
+			explanations, ok := annotations[checkNames[ruleID]]
+			if !ok {
+				continue
+			}
+			if message, ok := result["message"].(map[string]interface{}); ok {
+				text, _ := message["text"].(string)
+				message["text"] = text + "\nMaintainer annotation: " + strings.Join(explanations, " ")
+			}
+		}
+	}
+	return writeJSONFile(file, log)
+}
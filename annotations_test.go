@@ -0,0 +1,146 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseAnnotations(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		content string
+		want    checkAnnotations
+		wantErr error
+	}{
+		{
+			name: "annotations",
+			content: "annotations:\n" +
+				"  - checks: [binary-artifacts, Pinned-Dependencies]\n    reasons:\n      - reason: test-data\n" +
+				"  - checks: [Binary-Artifacts]\n    reasons:\n      - reason: remediated\n",
+			want: checkAnnotations{
+				"Binary-Artifacts":    {annotationReasons["test-data"], annotationReasons["remediated"]},
+				"Pinned-Dependencies": {annotationReasons["test-data"]},
+			},
+		},
+		{
+			name:    "no annotations",
+			content: "version: 1\n",
+			want:    checkAnnotations{},
+		},
+		{
+			name:    "unknown reason",
+			content: "annotations:\n  - checks: [SAST]\n    reasons:\n      - reason: wontfix\n",
+			wantErr: errInvalidAnnotations,
+		},
+		{
+			name:    "unknown check",
+			content: "annotations:\n  - checks: [Unknown]\n    reasons:\n      - reason: test-data\n",
+			wantErr: errInvalidAnnotations,
+		},
+		{
+			name:    "no reasons",
+			content: "annotations:\n  - checks: [SAST]\n",
+			wantErr: errInvalidAnnotations,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseAnnotations("scorecard.yml", []byte(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseAnnotations() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseAnnotations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_readAnnotations(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	if got, err := readAnnotations(workspace); got != nil || err != nil {
+		t.Errorf("readAnnotations() without a configuration = %v, %v, want nil", got, err)
+	}
+	if err := os.Mkdir(filepath.Join(workspace, ".github"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	content := "annotations:\n  - checks: [SAST]\n    reasons:\n      - reason: not-applicable\n"
+	if err := ioutil.WriteFile(filepath.Join(workspace, ".github", "scorecard.yml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readAnnotations(workspace)
+	if err != nil {
+		t.Fatalf("readAnnotations() error = %v", err)
+	}
+	if len(got["SAST"]) != 1 {
+		t.Errorf("readAnnotations() = %v, want the SAST annotation", got)
+	}
+}
+
+func Test_annotateResults(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputs := []resultsOutput{
+		{format: "json", file: filepath.Join(dir, "results.json")},
+		{format: sarif, file: filepath.Join(dir, "results.sarif")},
+	}
+	for i, src := range []string{"testdata/results.json", "testdata/results.sarif"} {
+		data, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(outputs[i].file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	annotations := checkAnnotations{"Pinned-Dependencies": {annotationReasons["test-data"]}}
+	if err := annotateResults(outputs, annotations); err != nil {
+		t.Fatalf("annotateResults() error = %v", err)
+	}
+
+	result, err := readScorecardResult(outputs[0].file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		want := annotations[check.Name]
+		if diff := cmp.Diff(want, check.Annotations); diff != "" {
+			t.Errorf("annotateResults() %s annotations mismatch (-want +got):\n%s", check.Name, diff)
+		}
+	}
+	findings, err := readSARIFFindings(outputs[1].file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		annotated := strings.Contains(f.message, "Maintainer annotation: "+annotationReasons["test-data"])
+		if annotated != (f.check == "Pinned-Dependencies") {
+			t.Errorf("annotateResults() %s SARIF message = %q", f.check, f.message)
+		}
+	}
+}
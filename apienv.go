@@ -0,0 +1,50 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+const defaultAPIEnvironment = "production"
+
+var errInvalidAPIEnvironment = errors.New("invalid API environment")
+
+// scorecardEndpoints are the endpoints of a deployment of the scorecard API and webapp.
+type scorecardEndpoints struct {
+	api string
+	// viewer is empty when the deployment has no viewer.
+	viewer string
+}
+
+// apiEnvironments are the known deployments of the scorecard API, by the api_environment naming them.
+var apiEnvironments = map[string]scorecardEndpoints{
+	defaultAPIEnvironment: {api: "https://api.securityscorecards.dev", viewer: "https://securityscorecards.dev/viewer/"},
+	"staging":             {api: "https://api-staging.securityscorecards.dev"},
+}
+
+// useAPIEnvironment is a function to use the endpoints of the API environment, production when empty,
+// in place of the scorecard API and viewer URLs.
+func useAPIEnvironment(name string) error {
+	if name == "" {
+		name = defaultAPIEnvironment
+	}
+	endpoints, ok := apiEnvironments[name]
+	if !ok {
+		return fmt.Errorf("%w: %q, want production or staging", errInvalidAPIEnvironment, name)
+	}
+	scorecardAPIURL, scorecardViewerURL = endpoints.api, endpoints.viewer
+	return nil
+}
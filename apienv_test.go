@@ -0,0 +1,60 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// not setting t.Parallel() here because we are mutating the API endpoints
+//nolint
+func Test_useAPIEnvironment(t *testing.T) {
+	defer func(api, viewer string) { scorecardAPIURL, scorecardViewerURL = api, viewer }(scorecardAPIURL,
+		scorecardViewerURL)
+	tests := []struct {
+		name       string
+		env        string
+		wantAPI    string
+		wantViewer string
+		wantErr    error
+	}{
+		{
+			name:       "default",
+			wantAPI:    "https://api.securityscorecards.dev",
+			wantViewer: "https://securityscorecards.dev/viewer/",
+		},
+		{
+			name:    "staging",
+			env:     "staging",
+			wantAPI: "https://api-staging.securityscorecards.dev",
+		},
+		{
+			name:    "unknown",
+			env:     "qa",
+			wantAPI: "https://api-staging.securityscorecards.dev",
+			wantErr: errInvalidAPIEnvironment,
+		},
+	}
+	for _, tt := range tests {
+		err := useAPIEnvironment(tt.env)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: useAPIEnvironment() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if scorecardAPIURL != tt.wantAPI || scorecardViewerURL != tt.wantViewer {
+			t.Errorf("%s: useAPIEnvironment() = %q, %q, want %q, %q", tt.name, scorecardAPIURL, scorecardViewerURL,
+				tt.wantAPI, tt.wantViewer)
+		}
+	}
+}
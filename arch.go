@@ -0,0 +1,105 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// runnerArch is set by GitHub Actions to the architecture of the runner, e.g. X64 or ARM64.
+const runnerArch = "RUNNER_ARCH"
+
+var (
+	errScorecardArchMismatch = errors.New("the scorecard binary does not match the runner architecture")
+	errInvalidScorecardBin   = errors.New("scorecard_bin must be an executable file")
+	errScorecardBinConflict  = errors.New("scorecard_bin and scorecard_version are mutually exclusive")
+)
+
+// elfMachines maps the ELF machine of a binary to its GOARCH.
+var elfMachines = map[elf.Machine]string{
+	elf.EM_X86_64:  "amd64",
+	elf.EM_AARCH64: "arm64",
+	elf.EM_386:     "386",
+	elf.EM_ARM:     "arm",
+}
+
+// runnerArchs maps the RUNNER_ARCH of a runner to its GOARCH.
+var runnerArchs = map[string]string{
+	"X64":   "amd64",
+	"ARM64": "arm64",
+	"X86":   "386",
+	"ARM":   "arm",
+}
+
+// runnerGOARCH is a function to get the GOARCH of the runner from RUNNER_ARCH. The action runs in a
+// container built for the runner, so it falls back to the architecture of the action binary when
+// RUNNER_ARCH is not set or unknown, e.g. outside of GitHub Actions.
+func runnerGOARCH(arch string) string {
+	if goarch, ok := runnerArchs[arch]; ok {
+		return goarch
+	}
+	return runtime.GOARCH
+}
+
+// validateScorecardBin is a function to check a pre-provisioned scorecard binary is an executable file.
+func validateScorecardBin(bin string) error {
+	info, err := os.Stat(bin)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidScorecardBin, err)
+	}
+	if !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+		return fmt.Errorf("%w: %s", errInvalidScorecardBin, bin)
+	}
+	return nil
+}
+
+// binaryArch is a function to get the GOARCH of an ELF binary. It returns an empty architecture
+// for binaries that are not ELF, e.g. scripts, or whose machine is unknown.
+func binaryArch(bin string) (string, error) {
+	f, err := elf.Open(bin)
+	if err != nil {
+		var formatErr *elf.FormatError
+		if errors.As(err, &formatErr) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error opening %s: %w", bin, err)
+	}
+	defer f.Close()
+	return elfMachines[f.Machine], nil
+}
+
+// checkScorecardArch is a function to check the scorecard binary runs on the architecture of the runner,
+// to fail with the fix instead of an exec format error, e.g. when the bundled amd64 binary is run
+// on an arm64 self-hosted runner. Missing binaries are left to fail when scorecard is run.
+func checkScorecardArch(writer io.Writer, bin, goarch string) error {
+	arch, err := binaryArch(bin)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "Runner architecture: %s (%s=%s)\n", goarch, runnerArch, os.Getenv(runnerArch))
+	if arch == "" || arch == goarch {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is built for %s, the runner is %s; set scorecard_version to download "+
+		"the release for the runner, or scorecard_bin to a pre-provisioned %s binary",
+		errScorecardArchMismatch, bin, arch, goarch, goarch)
+}
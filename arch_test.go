@@ -0,0 +1,145 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// otherArch is an architecture the test binary is not built for.
+func otherArch() string {
+	if runtime.GOARCH == "arm64" {
+		return "amd64"
+	}
+	return "arm64"
+}
+
+func Test_validateScorecardBin(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "scorecard")
+	if err := ioutil.WriteFile(executable, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "scorecard.txt")
+	if err := ioutil.WriteFile(file, []byte("scorecard"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	//nolint
+	tests := []struct {
+		name    string
+		bin     string
+		wantErr error
+	}{
+		{name: "Executable", bin: executable},
+		{name: "Not executable", bin: file, wantErr: errInvalidScorecardBin},
+		{name: "Directory", bin: dir, wantErr: errInvalidScorecardBin},
+		{name: "Missing", bin: filepath.Join(dir, "missing"), wantErr: errInvalidScorecardBin},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateScorecardBin(tt.bin); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateScorecardBin() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkScorecardArch(t *testing.T) {
+	t.Parallel()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(t.TempDir(), "scorecard")
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("the test binary is not an ELF binary")
+	}
+	//nolint
+	tests := []struct {
+		name    string
+		bin     string
+		goarch  string
+		wantErr error
+	}{
+		{name: "Same architecture", bin: executable, goarch: runtime.GOARCH},
+		{name: "Other architecture", bin: executable, goarch: otherArch(), wantErr: errScorecardArchMismatch},
+		{name: "Script", bin: script, goarch: otherArch()},
+		{name: "Missing", bin: filepath.Join(t.TempDir(), "missing"), goarch: runtime.GOARCH},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := checkScorecardArch(ioutil.Discard, tt.bin, tt.goarch); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkScorecardArch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_diagnoseScorecardBinary(t *testing.T) {
+	t.Parallel()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("the test binary is not an ELF binary")
+	}
+	if d := diagnoseScorecardBinary(executable, runtime.GOARCH); d.Status != diagnosticOK {
+		t.Errorf("diagnoseScorecardBinary() = %+v, want ok", d)
+	}
+	if d := diagnoseScorecardBinary(executable, otherArch()); d.Status != diagnosticError || d.Fix == "" {
+		t.Errorf("diagnoseScorecardBinary() = %+v, want an error with a fix", d)
+	}
+	if d := diagnoseScorecardBinary(filepath.Join(t.TempDir(), "missing"), runtime.GOARCH); d.Status != diagnosticError {
+		t.Errorf("diagnoseScorecardBinary() = %+v, want error", d)
+	}
+}
+
+func Test_runnerGOARCH(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		arch string
+		want string
+	}{
+		{arch: "X64", want: "amd64"},
+		{arch: "ARM64", want: "arm64"},
+		{arch: "X86", want: "386"},
+		{arch: "ARM", want: "arm"},
+		{arch: "", want: runtime.GOARCH},
+		{arch: "RISCV64", want: runtime.GOARCH},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.arch, func(t *testing.T) {
+			t.Parallel()
+			if got := runnerGOARCH(tt.arch); got != tt.want {
+				t.Errorf("runnerGOARCH() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
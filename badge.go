@@ -0,0 +1,179 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	badgeFormatSVG      = "svg"
+	badgeFormatEndpoint = "endpoint"
+	badgeLabel          = "scorecard"
+	// badgeCharWidth and badgePadding approximate the width of the badge text in pixels.
+	badgeCharWidth = 7
+	badgePadding   = 10
+)
+
+var errInvalidBadgeFormat = errors.New("invalid badge format")
+
+// badgeFiles maps each badge format to its default file name.
+var badgeFiles = map[string]string{
+	badgeFormatSVG:      "scorecard-badge.svg",
+	badgeFormatEndpoint: "scorecard-badge.json",
+}
+
+// badgeColor is the color of the badge for scores of at least minScore.
+type badgeColor struct {
+	name     string
+	hex      string
+	minScore float64
+}
+
+// badgeColors are the badge colors of the score ranges, from the highest minimum score.
+var badgeColors = []badgeColor{
+	{name: "brightgreen", hex: "#4c1", minScore: 9},
+	{name: "green", hex: "#97ca00", minScore: 7},
+	{name: "yellow", hex: "#dfb317", minScore: 5},
+	{name: "orange", hex: "#fe7d37", minScore: 3},
+	{name: "red", hex: "#e05d44", minScore: 0},
+}
+
+// badgeEndpoint is a shields.io endpoint badge, see https://shields.io/endpoint.
+type badgeEndpoint struct {
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	SchemaVersion int    `json:"schemaVersion"`
+}
+
+// validateBadgeFormat is a function to check the badge format is one the action can render.
+// An empty format disables the badge.
+func validateBadgeFormat(format string) error {
+	if _, ok := badgeFiles[format]; format != "" && !ok {
+		return fmt.Errorf("%w: %q", errInvalidBadgeFormat, format)
+	}
+	return nil
+}
+
+// generateBadge is a function to render the badge of the aggregate score into the workspace,
+// then commit it to a branch of the repository or a gist, if requested.
+func generateBadge(ctx context.Context, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	badge, err := renderBadge(scorecardBadgeFormat, result.Score)
+	if err != nil {
+		return err
+	}
+
+	file := scorecardBadgeFile
+	if file == "" {
+		file = badgeFiles[scorecardBadgeFormat]
+	}
+	if err := ioutil.WriteFile(filepath.Join(os.Getenv(githubWorkspace), file), badge, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	fmt.Printf("Wrote the score badge to %s.\n", file)
+
+	client := newGitHubClient(scorecardGitHubToken)
+	if scorecardBadgeBranch != "" {
+		message := fmt.Sprintf("Update the scorecard badge to %.1f", result.Score)
+		if err := commitFile(ctx, client, os.Getenv(githubRepository), scorecardBadgeBranch, file, message,
+			badge); err != nil {
+			return err
+		}
+	}
+	if scorecardBadgeGist != "" {
+		if err := updateGist(ctx, client, scorecardBadgeGist, filepath.Base(file), badge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderBadge is a function to render the badge of the score in the given format.
+func renderBadge(format string, score float64) ([]byte, error) {
+	message := fmt.Sprintf("%.1f", score)
+	color := scoreColor(score)
+	switch format {
+	case badgeFormatSVG:
+		return []byte(badgeSVG(message, color.hex)), nil
+	case badgeFormatEndpoint:
+		data, err := json.Marshal(badgeEndpoint{
+			Label:         badgeLabel,
+			Message:       message,
+			Color:         color.name,
+			SchemaVersion: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling badge: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errInvalidBadgeFormat, format)
+	}
+}
+
+// scoreColor is a function to get the color of the score range the score falls into.
+func scoreColor(score float64) badgeColor {
+	for _, color := range badgeColors {
+		if score >= color.minScore {
+			return color
+		}
+	}
+	return badgeColors[len(badgeColors)-1]
+}
+
+// badgeSVG is a function to render a flat badge in the style of shields.io.
+func badgeSVG(message, color string) string {
+	labelWidth := len(badgeLabel)*badgeCharWidth + badgePadding
+	messageWidth := len(message)*badgeCharWidth + badgePadding
+	width := labelWidth + messageWidth
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, badgeLabel, message)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, badgeLabel, message)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, color)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" ` +
+		`font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, badgeLabel)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, message)
+	b.WriteString("</g></svg>\n")
+	return b.String()
+}
+
+// updateGist is a function to write a file of a gist, e.g. to serve the badge from its raw URL.
+func updateGist(ctx context.Context, client *githubClient, gistID, file string, content []byte) error {
+	type gistFile struct {
+		Content string `json:"content"`
+	}
+	gist := struct {
+		Files map[string]gistFile `json:"files"`
+	}{
+		Files: map[string]gistFile{file: {Content: string(content)}},
+	}
+	return client.do(ctx, http.MethodPatch, fmt.Sprintf("/gists/%s", gistID), &gist, nil)
+}
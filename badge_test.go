@@ -0,0 +1,107 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_renderBadge(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		format  string
+		score   float64
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "SVG",
+			format: badgeFormatSVG,
+			score:  6.8,
+			want:   []string{`aria-label="scorecard: 6.8"`, `fill="#dfb317"`, `<text x="88" y="14">6.8</text>`},
+		},
+		{
+			name:   "Endpoint",
+			format: badgeFormatEndpoint,
+			score:  9.5,
+			want:   []string{`{"label":"scorecard","message":"9.5","color":"brightgreen","schemaVersion":1}`},
+		},
+		{
+			name:   "Low score",
+			format: badgeFormatEndpoint,
+			score:  1,
+			want:   []string{`"message":"1.0","color":"red"`},
+		},
+		{
+			name:    "Invalid format",
+			format:  "png",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := renderBadge(tt.format, tt.score)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderBadge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("renderBadge() = %s, want it to contain %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_validateBadgeFormat(t *testing.T) {
+	t.Parallel()
+	for _, format := range []string{"", badgeFormatSVG, badgeFormatEndpoint} {
+		if err := validateBadgeFormat(format); err != nil {
+			t.Errorf("validateBadgeFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := validateBadgeFormat("png"); err == nil {
+		t.Errorf("validateBadgeFormat(%q) error = nil", "png")
+	}
+}
+
+func Test_updateGist(t *testing.T) {
+	t.Parallel()
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/gists/abc123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var gist struct {
+			Files map[string]struct {
+				Content string `json:"content"`
+			} `json:"files"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gist); err != nil ||
+			gist.Files["scorecard-badge.svg"].Content != "<svg/>" {
+			t.Errorf("unexpected gist %+v: %v", gist, err)
+		}
+	}))
+
+	if err := updateGist(context.Background(), client, "abc123", "scorecard-badge.svg", []byte("<svg/>")); err != nil {
+		t.Errorf("updateGist() error = %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// baselineSourceArtifact reads the baseline from the JSON results uploaded as an artifact by a previous run.
+	baselineSourceArtifact = "artifact"
+	// baselineSourceAPI reads the baseline from the results published to the scorecard API.
+	baselineSourceAPI       = "api"
+	defaultBaselineArtifact = "scorecard-results"
+	githubRunID             = "GITHUB_RUN_ID"
+)
+
+// scorecardAPIURL is the scorecard API of the api_environment.
+var scorecardAPIURL = apiEnvironments[defaultAPIEnvironment].api
+
+var (
+	errInvalidBaselineSource = errors.New("invalid baseline source")
+	errBaselineNotFound      = errors.New("no baseline results found")
+)
+
+// artifactList is a page of the GitHub API list of workflow artifacts.
+type artifactList struct {
+	Artifacts []struct {
+		Name               string `json:"name"`
+		ArchiveDownloadURL string `json:"archive_download_url"`
+		WorkflowRun        struct {
+			HeadBranch string `json:"head_branch"`
+			ID         int64  `json:"id"`
+		} `json:"workflow_run"`
+		ID      int64 `json:"id"`
+		Expired bool  `json:"expired"`
+	} `json:"artifacts"`
+}
+
+// validateBaselineSource is a function to check the baseline source is one the action can read from.
+// An empty source disables the baseline comparison.
+func validateBaselineSource(source string) error {
+	switch source {
+	case "", baselineSourceArtifact, baselineSourceAPI:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errInvalidBaselineSource, source)
+	}
+}
+
+// compareBaseline is a function to report the score delta of every check between the baseline
+// and the current JSON results. It returns the baseline and the deltas.
+func compareBaseline(ctx context.Context, writer io.Writer, jsonResultsFile string) (*scorecardResult,
+	[]checkDelta, error) {
+	head, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var base *scorecardResult
+	repository := os.Getenv(githubRepository)
+	switch scorecardBaselineSource {
+	case baselineSourceArtifact:
+		client := newGitHubClient(scorecardGitHubToken)
+		base, err = fetchArtifactBaseline(ctx, client, repository, scorecardBaselineArtifact,
+			strings.TrimPrefix(scorecardDefaultBranch, "refs/heads/"), os.Getenv(githubRunID))
+	case baselineSourceAPI:
+		base, err = fetchAPIBaseline(ctx, scorecardHTTPClient, scorecardAPIURL, repository)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deltas := compareResults(base, head)
+	fmt.Fprintf(writer, "Scorecard results compared to the baseline from %s (score %.1f):\n\n", base.Date, base.Score)
+	writeDeltaTable(writer, deltas, "Baseline", "Current")
+	return base, deltas, nil
+}
+
+// fetchAPIBaseline is a function to get the latest results of the repository published to the scorecard API.
+func fetchAPIBaseline(ctx context.Context, httpClient *http.Client, baseURL,
+	repository string) (*scorecardResult, error) {
+	u := fmt.Sprintf("%s/projects/github.com/%s", baseURL, repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s is not published to the scorecard API", errBaselineNotFound, repository)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting %s: unexpected status %d", u, resp.StatusCode)
+	}
+	var result scorecardResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response body: %w", err)
+	}
+	return &result, nil
+}
+
+// fetchArtifactBaseline is a function to get the JSON results from the most recent unexpired artifact
+// with the given name uploaded by a run of the default branch, ignoring the artifacts of the current
+// workflow run. Pull request runs are ignored too: their results are the pull request's, and a pull
+// request from a fork can run on a branch named like the default branch.
+func fetchArtifactBaseline(ctx context.Context, client *githubClient, repository, name, defaultBranch,
+	runID string) (*scorecardResult, error) {
+	currentRun, _ := strconv.ParseInt(runID, 10, 64)
+	// Artifacts are listed newest first, so the first page holds the previous run.
+	p := fmt.Sprintf("/repos/%s/actions/artifacts?name=%s&per_page=%d", repository, url.QueryEscape(name), githubPageSize)
+	var list artifactList
+	if err := client.do(ctx, http.MethodGet, p, nil, &list); err != nil {
+		return nil, err
+	}
+	for i := range list.Artifacts {
+		artifact := &list.Artifacts[i]
+		if artifact.Name != name || artifact.Expired || (currentRun != 0 && artifact.WorkflowRun.ID == currentRun) ||
+			artifact.WorkflowRun.HeadBranch != defaultBranch {
+			continue
+		}
+		var run struct {
+			Event string `json:"event"`
+		}
+		runPath := fmt.Sprintf("/repos/%s/actions/runs/%d", repository, artifact.WorkflowRun.ID)
+		if err := client.do(ctx, http.MethodGet, runPath, nil, &run); err != nil {
+			return nil, err
+		}
+		if strings.Contains(run.Event, "pull_request") {
+			continue
+		}
+		archive, err := client.download(ctx, artifact.ArchiveDownloadURL)
+		if err != nil {
+			return nil, err
+		}
+		return readArtifactResult(archive)
+	}
+	return nil, fmt.Errorf("%w: no artifact named %q uploaded from the %s branch", errBaselineNotFound, name,
+		defaultBranch)
+}
+
+// readArtifactResult is a function to read the JSON results from the first .json file of an artifact archive.
+func readArtifactResult(archive []byte) (*scorecardResult, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("error opening artifact archive: %w", err)
+	}
+	for _, file := range reader.File {
+		if path.Ext(file.Name) != ".json" {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %w", file.Name, err)
+		}
+		data, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		var result scorecardResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s: %w", file.Name, err)
+		}
+		return &result, nil
+	}
+	return nil, fmt.Errorf("%w: the artifact has no JSON results", errBaselineNotFound)
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestArtifact is a function to create an artifact archive holding the given files.
+func newTestArtifact(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_validateBaselineSource(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{
+			name: "Disabled",
+		},
+		{
+			name:   "Artifact",
+			source: baselineSourceArtifact,
+		},
+		{
+			name:   "API",
+			source: baselineSourceAPI,
+		},
+		{
+			name:    "Invalid",
+			source:  "cache",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateBaselineSource(tt.source); (err != nil) != tt.wantErr {
+				t.Errorf("validateBaselineSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_fetchAPIBaseline(t *testing.T) {
+	t.Parallel()
+	results, err := ioutil.ReadFile("testdata/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/github.com/ossf/scorecard-action" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		//nolint:errcheck
+		w.Write(results)
+	}))
+	t.Cleanup(server.Close)
+
+	got, err := fetchAPIBaseline(context.Background(), server.Client(), server.URL, "ossf/scorecard-action")
+	if err != nil {
+		t.Fatalf("fetchAPIBaseline() error = %v", err)
+	}
+	if got.Score != 6.8 || len(got.Checks) != 4 {
+		t.Errorf("fetchAPIBaseline() = %+v", got)
+	}
+
+	_, err = fetchAPIBaseline(context.Background(), server.Client(), server.URL, "ossf/unknown")
+	if !errors.Is(err, errBaselineNotFound) {
+		t.Errorf("fetchAPIBaseline() error = %v, want %v", err, errBaselineNotFound)
+	}
+}
+
+func Test_fetchArtifactBaseline(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name      string
+		artifacts string
+		files     map[string]string
+		wantScore float64
+		wantErr   error
+	}{
+		{
+			name: "Skips the current run and expired artifacts",
+			artifacts: `[
+				{"id": 3, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/3/zip", "expired": false, "workflow_run": {"id": 100, "head_branch": "main"}},
+				{"id": 2, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/2/zip", "expired": true, "workflow_run": {"id": 99, "head_branch": "main"}},
+				{"id": 1, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/1/zip", "expired": false, "workflow_run": {"id": 98, "head_branch": "main"}}
+			]`,
+			files:     map[string]string{"results.sarif": "{}", "results.json": `{"score": 5.5}`},
+			wantScore: 5.5,
+		},
+		{
+			name: "Skips other branches and pull requests",
+			artifacts: `[
+				{"id": 5, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/5/zip", "expired": false, "workflow_run": {"id": 97, "head_branch": "feature"}},
+				{"id": 4, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/4/zip", "expired": false, "workflow_run": {"id": 96, "head_branch": "main"}},
+				{"id": 1, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/1/zip", "expired": false, "workflow_run": {"id": 98, "head_branch": "main"}}
+			]`,
+			files:     map[string]string{"results.json": `{"score": 5.5}`},
+			wantScore: 5.5,
+		},
+		{
+			name:      "Only pull request artifacts",
+			artifacts: `[{"id": 4, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/4/zip", "expired": false, "workflow_run": {"id": 96, "head_branch": "main"}}]`,
+			wantErr:   errBaselineNotFound,
+		},
+		{
+			name:      "No artifact",
+			artifacts: `[{"id": 3, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/3/zip", "expired": false, "workflow_run": {"id": 100, "head_branch": "main"}}]`,
+			wantErr:   errBaselineNotFound,
+		},
+		{
+			name:      "No JSON results in the artifact",
+			artifacts: `[{"id": 1, "name": "scorecard-results", "archive_download_url": "SERVER/artifacts/1/zip", "expired": false, "workflow_run": {"id": 98, "head_branch": "main"}}]`,
+			files:     map[string]string{"results.sarif": "{}"},
+			wantErr:   errBaselineNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var client *githubClient
+			client = newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/repos/ossf/scorecard-action/actions/artifacts":
+					if got := r.URL.Query().Get("name"); got != "scorecard-results" {
+						t.Errorf("artifact name = %q", got)
+					}
+					fmt.Fprintf(w, `{"artifacts": %s}`, strings.ReplaceAll(tt.artifacts, "SERVER", client.baseURL))
+				case "/repos/ossf/scorecard-action/actions/runs/98":
+					fmt.Fprint(w, `{"id": 98, "event": "push", "head_branch": "main"}`)
+				case "/repos/ossf/scorecard-action/actions/runs/96":
+					// A pull request from a fork's main branch.
+					fmt.Fprint(w, `{"id": 96, "event": "pull_request", "head_branch": "main"}`)
+				case "/artifacts/1/zip":
+					//nolint:errcheck
+					w.Write(newTestArtifact(t, tt.files))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			got, err := fetchArtifactBaseline(context.Background(), client, "ossf/scorecard-action",
+				defaultBaselineArtifact, "main", "100")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("fetchArtifactBaseline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Score != tt.wantScore {
+				t.Errorf("fetchArtifactBaseline() score = %v, want %v", got.Score, tt.wantScore)
+			}
+		})
+	}
+}
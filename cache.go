@@ -0,0 +1,172 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// cacheHTTPDir is the directory of the cache directory holding the GitHub API responses.
+	cacheHTTPDir = "http"
+	// cacheChecksDir is the directory of the cache directory holding the results of the checks.
+	cacheChecksDir = "checks"
+)
+
+// cachingTransport sends conditional GET requests for the responses it cached, and serves them again
+// when the server answers they did not change. Conditional requests answered with
+// "304 Not Modified" do not count against the GitHub API rate limit.
+type cachingTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+// newCachingClient is a function to create a copy of the HTTP client caching GitHub API responses in dir.
+func newCachingClient(client *http.Client, dir string) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	caching := *client
+	caching.Transport = &cachingTransport{base: base, dir: filepath.Join(dir, cacheHTTPDir)}
+	return &caching
+}
+
+// RoundTrip sends the request, conditionally if its response is cached.
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+	file := filepath.Join(t.dir, cacheKey(req.URL.String(), req.Header.Get("Accept")))
+	cached := readCachedResponse(file, req)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.Header.Get("ETag"))
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		// The rate limit headers of the conditional request are the current ones.
+		for _, header := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"} {
+			if value := resp.Header.Get(header); value != "" {
+				cached.Header.Set(header, value)
+			}
+		}
+		return cached, nil
+	}
+	if resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "" {
+		writeCachedResponse(file, resp)
+	}
+	return resp, nil
+}
+
+// readCachedResponse is a function to read a cached response, or nil if none is cached.
+func readCachedResponse(file string, req *http.Request) *http.Response {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil || resp.Header.Get("ETag") == "" {
+		return nil
+	}
+	return resp
+}
+
+// writeCachedResponse is a function to cache a response, keeping its body readable.
+// Caching is best effort: a response that cannot be cached is still returned.
+func writeCachedResponse(file string, resp *http.Response) {
+	data, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return
+	}
+	//nolint:errcheck
+	ioutil.WriteFile(file, data, 0o600)
+}
+
+// cacheKey is a function to get the file name of a cache entry from the parts identifying it.
+func cacheKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkCacheFile is a function to get the cache file of the results of a check run by the scorecard command
+// on a commit, or false if the results of the check are not cacheable. Only the checks scorecard can run on
+// a local folder are cached, since their results only depend on the content of the commit; the key also covers
+// the scorecard binary, its arguments and the policy file, which change the results.
+func checkCacheFile(cmd *exec.Cmd, check, format, commit string) (string, bool) {
+	if scorecardCacheDir == "" || commit == "" || !isLocalCheck(check) {
+		return "", false
+	}
+	info, err := os.Stat(cmd.Path)
+	if err != nil {
+		return "", false
+	}
+	parts := []string{os.Getenv(githubRepository), commit, check, format,
+		fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())}
+	args := withCheck(cmd.Args[1:], check)
+	parts = append(parts, args...)
+	for i := range args {
+		if args[i] == "--policy" && i+1 < len(args) {
+			policy, err := ioutil.ReadFile(args[i+1])
+			if err != nil {
+				return "", false
+			}
+			parts = append(parts, string(policy))
+		}
+	}
+	return filepath.Join(scorecardCacheDir, cacheChecksDir, cacheKey(parts...)+"."+format), true
+}
+
+// isLocalCheck is a function to check if scorecard can run the check on a local folder.
+func isLocalCheck(check string) bool {
+	for _, c := range localChecks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
+// copyFile is a function to copy a file, creating the directory of the destination.
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", src, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Dir(dst), err)
+	}
+	if err := ioutil.WriteFile(dst, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func Test_cachingTransport(t *testing.T) {
+	t.Parallel()
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", "4998")
+		fmt.Fprint(w, `{"default_branch": "main"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newCachingClient(server.Client(), t.TempDir())
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL + "/repos/owner/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != `{"default_branch": "main"}` {
+			t.Errorf("request %d: got %d %q", i, resp.StatusCode, body)
+		}
+		if i > 0 && resp.Header.Get("X-RateLimit-Remaining") != "4999" {
+			t.Errorf("request %d: rate limit = %q, want the one of the conditional request", i,
+				resp.Header.Get("X-RateLimit-Remaining"))
+		}
+	}
+	if requests != 3 || notModified != 2 {
+		t.Errorf("server got %d requests, %d not modified, want 3, 2", requests, notModified)
+	}
+}
+
+func Test_checkCacheFile(t *testing.T) {
+	// not setting t.Parallel() here because we are mutating the env variables
+	//nolint
+	dir := scorecardCacheDir
+	defer func() { scorecardCacheDir = dir }()
+	bin := filepath.Join(t.TempDir(), "scorecard")
+	if err := ioutil.WriteFile(bin, []byte("#!/bin/sh\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(bin, "--repo=github.com/owner/repo", "--format", "json")
+
+	scorecardCacheDir = ""
+	if _, ok := checkCacheFile(cmd, "Pinned-Dependencies", "json", "abc"); ok {
+		t.Errorf("checkCacheFile() is cacheable without a cache directory")
+	}
+	scorecardCacheDir = "cache"
+	file, ok := checkCacheFile(cmd, "Pinned-Dependencies", "json", "abc")
+	if !ok || filepath.Dir(file) != filepath.Join("cache", cacheChecksDir) || filepath.Ext(file) != ".json" {
+		t.Errorf("checkCacheFile() = %q, %t", file, ok)
+	}
+	if again, _ := checkCacheFile(cmd, "Pinned-Dependencies", "json", "abc"); again != file {
+		t.Errorf("checkCacheFile() is not stable: %q != %q", again, file)
+	}
+	for _, other := range []struct{ check, format, commit string }{
+		{"Pinned-Dependencies", "json", "def"},
+		{"Token-Permissions", "json", "abc"},
+		{"Pinned-Dependencies", sarif, "abc"},
+	} {
+		if got, _ := checkCacheFile(cmd, other.check, other.format, other.commit); got == file {
+			t.Errorf("checkCacheFile(%+v) = %q, the file of another check", other, got)
+		}
+	}
+	// The results of these checks depend on more than the commit.
+	for _, check := range []string{"Branch-Protection", "Maintained", "Code-Review"} {
+		if _, ok := checkCacheFile(cmd, check, "json", "abc"); ok {
+			t.Errorf("checkCacheFile() caches %s", check)
+		}
+	}
+	if _, ok := checkCacheFile(cmd, "Pinned-Dependencies", "json", ""); ok {
+		t.Errorf("checkCacheFile() is cacheable without a commit")
+	}
+}
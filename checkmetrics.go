@@ -0,0 +1,89 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// checkMetrics are the metrics of a check run in its own scorecard process.
+type checkMetrics struct {
+	// Duration is the wall-clock duration of the check, in seconds.
+	Duration float64 `json:"duration_seconds"`
+	// APICalls is the GitHub API quota the check used, or unknownAPICalls when checks run concurrently.
+	APICalls int `json:"api_calls"`
+}
+
+// checkMeter measures the checks run in separate scorecard processes. The client is nil when the checks
+// make no GitHub API request, e.g. offline.
+type checkMeter struct {
+	client *githubClient
+	// countsAPICalls is whether the quota each check used can be told apart, i.e. checks run one at a time.
+	countsAPICalls bool
+}
+
+// measure is a function to run the check and get its metrics.
+func (m checkMeter) measure(ctx context.Context, run func()) *checkMetrics {
+	var start rateLimit
+	hasQuota := false
+	if m.client != nil && m.countsAPICalls {
+		quota, err := fetchRateLimit(ctx, m.client)
+		start, hasQuota = quota, err == nil
+	}
+	began := time.Now()
+	run()
+	metrics := &checkMetrics{
+		Duration: math.Round(time.Since(began).Seconds()*10) / 10,
+		APICalls: unknownAPICalls,
+	}
+	if hasQuota {
+		if end, err := fetchRateLimit(ctx, m.client); err == nil {
+			metrics.APICalls = apiCallsUsed(start, end)
+		}
+	}
+	return metrics
+}
+
+// writeCheckMetrics is a function to render the metrics of the checks as a markdown table, slowest first,
+// so that the checks worth tuning or skipping stand out. Checks without metrics, e.g. cached, are left out.
+func writeCheckMetrics(writer io.Writer, checks []checkResult) {
+	var measured []checkResult
+	for i := range checks {
+		if checks[i].Metrics != nil {
+			measured = append(measured, checks[i])
+		}
+	}
+	if len(measured) == 0 {
+		return
+	}
+	sort.SliceStable(measured, func(i, j int) bool {
+		return measured[i].Metrics.Duration > measured[j].Metrics.Duration
+	})
+	fmt.Fprintf(writer, "### Check metrics\n\n")
+	fmt.Fprintf(writer, "| Check | Duration | API calls |\n")
+	fmt.Fprintf(writer, "| ----- | -------- | --------- |\n")
+	for i := range measured {
+		apiCalls := "?"
+		if measured[i].Metrics.APICalls != unknownAPICalls {
+			apiCalls = fmt.Sprint(measured[i].Metrics.APICalls)
+		}
+		fmt.Fprintf(writer, "| %s | %.1fs | %s |\n", measured[i].Name, measured[i].Metrics.Duration, apiCalls)
+	}
+	fmt.Fprintln(writer)
+}
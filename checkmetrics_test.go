@@ -0,0 +1,76 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func Test_checkMeter_measure(t *testing.T) {
+	t.Parallel()
+	remaining := 5000
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"resources": {"core": {"limit": 5000, "remaining": %d, "reset": 1700000000}}}`, remaining)
+	}))
+	run := func() { remaining -= 42 }
+
+	//nolint
+	tests := []struct {
+		name  string
+		meter checkMeter
+		want  int
+	}{
+		{name: "sequential", meter: checkMeter{client: client, countsAPICalls: true}, want: 42},
+		{name: "concurrent", meter: checkMeter{client: client}, want: unknownAPICalls},
+		{name: "offline", meter: checkMeter{countsAPICalls: true}, want: unknownAPICalls},
+	}
+	for _, tt := range tests {
+		metrics := tt.meter.measure(context.Background(), run)
+		if metrics.APICalls != tt.want {
+			t.Errorf("%s: measure() API calls = %d, want %d", tt.name, metrics.APICalls, tt.want)
+		}
+		if metrics.Duration < 0 {
+			t.Errorf("%s: measure() duration = %v", tt.name, metrics.Duration)
+		}
+	}
+}
+
+func Test_writeCheckMetrics(t *testing.T) {
+	t.Parallel()
+	checks := []checkResult{
+		{Name: "Binary-Artifacts", Metrics: &checkMetrics{Duration: 1.5, APICalls: 3}},
+		{Name: "License"},
+		{Name: "CI-Tests", Metrics: &checkMetrics{Duration: 120, APICalls: unknownAPICalls}},
+	}
+	want := "### Check metrics\n\n" +
+		"| Check | Duration | API calls |\n" +
+		"| ----- | -------- | --------- |\n" +
+		"| CI-Tests | 120.0s | ? |\n" +
+		"| Binary-Artifacts | 1.5s | 3 |\n\n"
+	var out bytes.Buffer
+	writeCheckMetrics(&out, checks)
+	if out.String() != want {
+		t.Errorf("writeCheckMetrics() = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	writeCheckMetrics(&out, checks[1:2])
+	if out.Len() != 0 {
+		t.Errorf("writeCheckMetrics() without metrics = %q, want nothing", out.String())
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const (
+	checkRunName = "Scorecard"
+	// maxCheckScore is the score of a check that fully passes.
+	maxCheckScore = 10
+	// maxAnnotationsPerRequest is the number of annotations the Checks API accepts per request.
+	maxAnnotationsPerRequest = 50
+)
+
+// checkRunAnnotationLevels maps SARIF levels to Checks API annotation levels.
+var checkRunAnnotationLevels = map[string]string{
+	"error":   "failure",
+	"warning": "warning",
+	"note":    "notice",
+}
+
+// checkRun is a GitHub Checks API check run.
+type checkRun struct {
+	Name       string         `json:"name,omitempty"`
+	HeadSHA    string         `json:"head_sha,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	Conclusion string         `json:"conclusion,omitempty"`
+	Output     checkRunOutput `json:"output"`
+	ID         int64          `json:"id,omitempty"`
+}
+
+// checkRunOutput is the output of a check run.
+type checkRunOutput struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary"`
+	Annotations []checkRunAnnotation `json:"annotations,omitempty"`
+}
+
+// checkRunAnnotation is a check run annotation on a line range of a file.
+type checkRunAnnotation struct {
+	Path            string `json:"path"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+}
+
+// postCheckRun is a function to create a check run for the scorecard results,
+// annotated with the file locations of failing checks found in the SARIF results.
+func postCheckRun(ctx context.Context, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	var findings []sarifFinding
+	if sarifFile, ok := sarifResultsFile(scorecardResultsOutputs); ok {
+		if findings, err = readSARIFFindings(sarifFile); err != nil {
+			return err
+		}
+	}
+	eventData, err := ioutil.ReadFile(os.Getenv(githubEventPath))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", githubEventPath, err)
+	}
+
+	run := newCheckRun(result, headSHA(string(eventData)))
+	annotations := checkRunAnnotations(findings, failingChecks(result), os.Getenv(githubWorkspace))
+	client := newGitHubClient(scorecardGitHubToken)
+	return createCheckRun(ctx, client, os.Getenv(githubRepository), &run, annotations)
+}
+
+// headSHA is a function to get the analyzed commit: the head of the pull request
+// for pull request events, GITHUB_SHA otherwise.
+func headSHA(eventData string) string {
+	var event struct {
+		PullRequest struct {
+			Head struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal([]byte(eventData), &event); err == nil && event.PullRequest.Head.SHA != "" {
+		return event.PullRequest.Head.SHA
+	}
+	return os.Getenv(githubSHA)
+}
+
+// failingChecks is a function to get the names of the checks that did not get the maximum score.
+// Inconclusive checks are not considered failing.
+func failingChecks(result *scorecardResult) map[string]bool {
+	failing := make(map[string]bool)
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Score != inconclusiveScore && check.Score < maxCheckScore {
+			failing[check.Name] = true
+		}
+	}
+	return failing
+}
+
+// newCheckRun is a function to create a completed check run summarizing the scorecard results.
+func newCheckRun(result *scorecardResult, sha string) checkRun {
+	conclusion := "success"
+	if len(failingChecks(result)) > 0 {
+		conclusion = "neutral"
+	}
+	var summary bytes.Buffer
+	writeStepSummary(&summary, result)
+	return checkRun{
+		Name:       checkRunName,
+		HeadSHA:    sha,
+		Status:     "completed",
+		Conclusion: conclusion,
+		Output: checkRunOutput{
+			Title:   fmt.Sprintf("Aggregate score: %.1f / 10", result.Score),
+			Summary: summary.String(),
+		},
+	}
+}
+
+// checkRunAnnotations is a function to convert the SARIF findings of failing checks into annotations.
+// Findings that do not point to a file in the workspace cannot be annotated and are skipped.
+func checkRunAnnotations(findings []sarifFinding, failing map[string]bool, workspace string) []checkRunAnnotation {
+	var annotations []checkRunAnnotation
+	for i := range findings {
+		finding := &findings[i]
+		if !failing[finding.check] || finding.path == "" {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(workspace, finding.path)); err != nil || info.IsDir() {
+			continue
+		}
+		level, ok := checkRunAnnotationLevels[finding.level]
+		if !ok {
+			level = "warning"
+		}
+		startLine := finding.startLine
+		if startLine < 1 {
+			startLine = 1
+		}
+		endLine := finding.endLine
+		if endLine < startLine {
+			endLine = startLine
+		}
+		annotations = append(annotations, checkRunAnnotation{
+			Path:            finding.path,
+			AnnotationLevel: level,
+			Title:           finding.check,
+			Message:         finding.message,
+			StartLine:       startLine,
+			EndLine:         endLine,
+		})
+	}
+	return annotations
+}
+
+// createCheckRun is a function to create the check run and attach the annotations to it,
+// in as many requests as the Checks API annotation limit requires.
+func createCheckRun(ctx context.Context, client *githubClient, repository string, run *checkRun,
+	annotations []checkRunAnnotation) error {
+	batch := annotations
+	if len(batch) > maxAnnotationsPerRequest {
+		batch = batch[:maxAnnotationsPerRequest]
+	}
+	run.Output.Annotations = batch
+	var created checkRun
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/check-runs", repository), run, &created); err != nil {
+		return err
+	}
+
+	for start := len(batch); start < len(annotations); start += maxAnnotationsPerRequest {
+		end := start + maxAnnotationsPerRequest
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		update := checkRun{
+			Output: checkRunOutput{
+				Title:       run.Output.Title,
+				Summary:     run.Output.Summary,
+				Annotations: annotations[start:end],
+			},
+		}
+		path := fmt.Sprintf("/repos/%s/check-runs/%d", repository, created.ID)
+		if err := client.do(ctx, http.MethodPatch, path, &update, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_checkRunAnnotations(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("./testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	findings, err := readSARIFFindings("./testdata/results.sarif")
+	if err != nil {
+		t.Fatalf("readSARIFFindings() error = %v", err)
+	}
+	findings = append(findings, sarifFinding{check: "Binary-Artifacts", path: "main.go", level: "warning"})
+
+	// Branch-Protection has no file location and Binary-Artifacts is not failing.
+	want := []checkRunAnnotation{
+		{
+			Path:            ".github/workflows/tests.yaml",
+			AnnotationLevel: "failure",
+			Title:           "Pinned-Dependencies",
+			Message:         "score is 7: dependency not pinned by hash detected -- score normalized to 7:\nWarn: non-pinned dependency",
+			StartLine:       12,
+			EndLine:         12,
+		},
+	}
+	got := checkRunAnnotations(findings, failingChecks(result), ".")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("checkRunAnnotations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_newCheckRun(t *testing.T) {
+	t.Parallel()
+	failing := newTestResult(6.8, checkResult{Name: "Branch-Protection", Score: 3})
+	if got := newCheckRun(failing, "sha").Conclusion; got != "neutral" {
+		t.Errorf("newCheckRun() conclusion = %v, want neutral", got)
+	}
+	passing := newTestResult(10,
+		checkResult{Name: "Branch-Protection", Score: 10},
+		checkResult{Name: "Fuzzing", Score: inconclusiveScore},
+	)
+	run := newCheckRun(passing, "sha")
+	if run.Conclusion != "success" || run.HeadSHA != "sha" || run.Output.Title != "Aggregate score: 10.0 / 10" {
+		t.Errorf("newCheckRun() = %+v", run)
+	}
+}
+
+//not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_headSHA(t *testing.T) {
+	t.Setenv(githubSHA, "merge-sha")
+	data, err := ioutil.ReadFile("./testdata/pull_request.json")
+	if err != nil {
+		t.Fatalf("Failed to open test data: %v", err)
+	}
+	if got := headSHA(string(data)); got != "aa0496aa6ed5102642f352a5c4ad3cf090017c76" {
+		t.Errorf("headSHA() = %v, want the pull request head", got)
+	}
+	if got := headSHA(`{"ref": "refs/heads/main"}`); got != "merge-sha" {
+		t.Errorf("headSHA() = %v, want %v", got, "merge-sha")
+	}
+}
+
+func Test_createCheckRun(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name        string
+		annotations int
+		wantBatches []int
+	}{
+		{
+			name:        "No annotations",
+			annotations: 0,
+			wantBatches: []int{0},
+		},
+		{
+			name:        "Single request",
+			annotations: 50,
+			wantBatches: []int{50},
+		},
+		{
+			name:        "Several requests",
+			annotations: 120,
+			wantBatches: []int{50, 50, 20},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var gotBatches []int
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var run checkRun
+				if err := json.NewDecoder(r.Body).Decode(&run); err != nil {
+					t.Errorf("failed to decode check run: %v", err)
+				}
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/check-runs":
+					if run.Name != checkRunName || run.Status != "completed" {
+						t.Errorf("unexpected check run %+v", run)
+					}
+				case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/check-runs/5":
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				mu.Lock()
+				gotBatches = append(gotBatches, len(run.Output.Annotations))
+				mu.Unlock()
+				fmt.Fprint(w, `{"id": 5}`)
+			}))
+
+			annotations := make([]checkRunAnnotation, tt.annotations)
+			run := newCheckRun(newTestResult(5, checkResult{Name: "Code-Review", Score: 5}), "sha")
+			if err := createCheckRun(context.Background(), client, "owner/repo", &run, annotations); err != nil {
+				t.Fatalf("createCheckRun() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantBatches, gotBatches); diff != "" {
+				t.Errorf("createCheckRun() batches mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
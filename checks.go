@@ -0,0 +1,95 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errUnknownCheck      = errors.New("unknown check")
+	errNoCheckToRun      = errors.New("all checks are skipped")
+	errConflictingChecks = errors.New("checks and skip_checks cannot be used together")
+)
+
+// knownChecks are the checks of the bundled scorecard release.
+var knownChecks = []string{
+	"Binary-Artifacts",
+	"Branch-Protection",
+	"CI-Tests",
+	"CII-Best-Practices",
+	"Code-Review",
+	"Contributors",
+	"Dangerous-Workflow",
+	"Dependency-Update-Tool",
+	"Fuzzing",
+	"License",
+	"Maintained",
+	"Packaging",
+	"Pinned-Dependencies",
+	"SAST",
+	"Security-Policy",
+	"Signed-Releases",
+	"Token-Permissions",
+	"Vulnerabilities",
+}
+
+// canonicalCheckName is a function to get the name of a known check, matched case-insensitively.
+func canonicalCheckName(name string) (string, error) {
+	for _, check := range knownChecks {
+		if strings.EqualFold(check, name) {
+			return check, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", errUnknownCheck, name)
+}
+
+// selectChecks is a function to get the checks scorecard runs, either the checks to run or all the known
+// checks but the skipped ones. It returns no checks, i.e. all of them, when neither is set.
+func selectChecks(checks, skipChecks []string) ([]string, error) {
+	if len(checks) > 0 && len(skipChecks) > 0 {
+		return nil, errConflictingChecks
+	}
+	var selected []string
+	for _, name := range checks {
+		check, err := canonicalCheckName(name)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, check)
+	}
+	if len(skipChecks) == 0 {
+		return selected, nil
+	}
+
+	skipped := make(map[string]bool, len(skipChecks))
+	for _, name := range skipChecks {
+		check, err := canonicalCheckName(name)
+		if err != nil {
+			return nil, err
+		}
+		skipped[check] = true
+	}
+	for _, check := range knownChecks {
+		if !skipped[check] {
+			selected = append(selected, check)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errNoCheckToRun
+	}
+	return selected, nil
+}
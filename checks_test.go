@@ -0,0 +1,85 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_selectChecks(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name       string
+		checks     []string
+		skipChecks []string
+		want       []string
+		wantErr    error
+	}{
+		{
+			name: "All checks",
+		},
+		{
+			name:   "Selected checks are canonicalized",
+			checks: []string{"sast", "Pinned-Dependencies"},
+			want:   []string{"SAST", "Pinned-Dependencies"},
+		},
+		{
+			name:       "Skipped checks",
+			skipChecks: []string{"CI-Tests", "fuzzing", "License", "Packaging"},
+			want: []string{
+				"Binary-Artifacts", "Branch-Protection", "CII-Best-Practices", "Code-Review", "Contributors",
+				"Dangerous-Workflow", "Dependency-Update-Tool", "Maintained", "Pinned-Dependencies", "SAST",
+				"Security-Policy", "Signed-Releases", "Token-Permissions", "Vulnerabilities",
+			},
+		},
+		{
+			name:    "Unknown check",
+			checks:  []string{"Webhooks"},
+			wantErr: errUnknownCheck,
+		},
+		{
+			name:       "Unknown skipped check",
+			skipChecks: []string{"Typo"},
+			wantErr:    errUnknownCheck,
+		},
+		{
+			name:       "Both inputs",
+			checks:     []string{"SAST"},
+			skipChecks: []string{"Fuzzing"},
+			wantErr:    errConflictingChecks,
+		},
+		{
+			name:       "Every check skipped",
+			skipChecks: knownChecks,
+			wantErr:    errNoCheckToRun,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := selectChecks(tt.checks, tt.skipChecks)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("selectChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("selectChecks() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// sarifProcessingPolls is how many times the processing status of an upload is polled.
+	sarifProcessingPolls    = 30
+	sarifProcessingComplete = "complete"
+	sarifProcessingFailed   = "failed"
+)
+
+var (
+	errUploadNeedsSARIF    = errors.New("upload_sarif requires sarif to be one of the results_format")
+	errSARIFProcessing     = errors.New("code scanning failed to process the SARIF results")
+	errSARIFProcessingWait = errors.New("code scanning did not process the SARIF results in time")
+	// sarifPollInterval is the delay between two polls of the processing status of an upload.
+	sarifPollInterval = 5 * time.Second
+)
+
+// sarifUpload is an upload of SARIF results to the code scanning API.
+type sarifUpload struct {
+	CommitSHA string `json:"commit_sha"`
+	Ref       string `json:"ref"`
+	// SARIF is the gzip compressed, base64 encoded SARIF log.
+	SARIF    string `json:"sarif"`
+	ToolName string `json:"tool_name"`
+}
+
+// sarifUploadStatus is the processing status of a SARIF upload.
+type sarifUploadStatus struct {
+	ID               string   `json:"id"`
+	ProcessingStatus string   `json:"processing_status"`
+	Errors           []string `json:"errors"`
+}
+
+// uploadSARIF is a function to upload the SARIF results, and those of the sub-paths, to code scanning
+// for the analyzed commit. The category of each upload is the automationDetails.id of its runs.
+func uploadSARIF(ctx context.Context, writer io.Writer) error {
+	sarifFile, _ := sarifResultsFile(scorecardResultsOutputs)
+	files := []string{sarifFile}
+	for _, subPath := range scorecardSubPaths {
+		files = append(files, subPathResultsFile(sarifFile, subPath))
+	}
+	client := newGitHubClient(scorecardGitHubToken)
+	for _, file := range files {
+		if err := uploadSARIFFile(ctx, client, os.Getenv(githubRepository), file,
+			os.Getenv(githubSHA), os.Getenv(githubRef)); err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Uploaded %s to code scanning.\n", file)
+	}
+	return nil
+}
+
+// uploadSARIFFile is a function to upload a SARIF results file to code scanning and wait until it is processed.
+func uploadSARIFFile(ctx context.Context, client *githubClient, repository, file, sha, ref string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", file, err)
+	}
+	encoded, err := gzipBase64(data)
+	if err != nil {
+		return err
+	}
+	upload := sarifUpload{CommitSHA: sha, Ref: ref, SARIF: encoded, ToolName: "scorecard"}
+	var status sarifUploadStatus
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/code-scanning/sarifs", repository),
+		&upload, &status); err != nil {
+		return err
+	}
+
+	for i := 0; i < sarifProcessingPolls; i++ {
+		path := fmt.Sprintf("/repos/%s/code-scanning/sarifs/%s", repository, status.ID)
+		if err := client.do(ctx, http.MethodGet, path, nil, &status); err != nil {
+			return err
+		}
+		switch status.ProcessingStatus {
+		case sarifProcessingComplete:
+			return nil
+		case sarifProcessingFailed:
+			return fmt.Errorf("%w: %s: %v", errSARIFProcessing, file, status.Errors)
+		}
+		if err := sleepContext(ctx, sarifPollInterval); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s", errSARIFProcessingWait, file)
+}
+
+// gzipBase64 is a function to compress data with gzip and encode it in base64, as code scanning expects.
+func gzipBase64(data []byte) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return "", fmt.Errorf("error compressing SARIF results: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("error compressing SARIF results: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
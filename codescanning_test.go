@@ -0,0 +1,92 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_uploadSARIFFile(t *testing.T) {
+	// not setting t.Parallel() here because we are mutating the env variables
+	//nolint
+	interval := sarifPollInterval
+	defer func() { sarifPollInterval = interval }()
+	sarifPollInterval = time.Millisecond
+
+	file := filepath.Join(t.TempDir(), "results.sarif")
+	if err := ioutil.WriteFile(file, []byte(`{"version":"2.1.0"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	//nolint
+	tests := []struct {
+		name     string
+		statuses []string
+		wantErr  error
+	}{
+		{name: "Processed", statuses: []string{"pending", sarifProcessingComplete}},
+		{name: "Failed", statuses: []string{sarifProcessingFailed}, wantErr: errSARIFProcessing},
+	}
+	for _, tt := range tests {
+		polls := 0
+		var upload sarifUpload
+		client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/code-scanning/sarifs":
+				if err := json.NewDecoder(r.Body).Decode(&upload); err != nil {
+					t.Errorf("error decoding upload: %v", err)
+				}
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, `{"id": "47177e22", "url": "https://api.github.com/repos/owner/repo/code-scanning/sarifs/47177e22"}`)
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/code-scanning/sarifs/47177e22":
+				fmt.Fprintf(w, `{"processing_status": %q, "errors": ["invalid SARIF"]}`, tt.statuses[polls])
+				polls++
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+
+		err := uploadSARIFFile(context.Background(), client, "owner/repo", file, "sha", "refs/heads/main")
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: uploadSARIFFile() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if polls != len(tt.statuses) {
+			t.Errorf("%s: uploadSARIFFile() polled %d times, want %d", tt.name, polls, len(tt.statuses))
+		}
+		if upload.CommitSHA != "sha" || upload.Ref != "refs/heads/main" {
+			t.Errorf("%s: uploadSARIFFile() upload = %+v", tt.name, upload)
+		}
+		compressed, err := base64.StdEncoding.DecodeString(upload.SARIF)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := ioutil.ReadAll(zr); string(data) != `{"version":"2.1.0"}` {
+			t.Errorf("%s: uploadSARIFFile() uploaded %q", tt.name, data)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	// The action's metadata is embedded to know the inputs and their defaults.
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the configuration file used when it exists and config_file is not set.
+const defaultConfigFile = ".scorecard.yml"
+
+var (
+	errInvalidConfigFile = errors.New("invalid configuration file")
+	// configInputs are the inputs the configuration file can set. The file is repository content,
+	// which pull requests can change, so it is limited to the inputs that neither hold secrets, run code,
+	// choose binaries, send the results elsewhere nor decide whether the workflow fails: a pull request
+	// must not be able to skip the run, e.g. with dry_run, or make checks inconclusive with timeouts.
+	configInputs = map[string]bool{
+		"results_file":        true,
+		"results_format":      true,
+		"sarif_category":      true,
+		"check_parallelism":   true,
+		"check_metrics":       true,
+		"max_retries":         true,
+		"retry_backoff":       true,
+		"wait_on_rate_limit":  true,
+		"max_rate_limit_wait": true,
+	}
+	// configExecutableInputs are the inputs that run code. Read from the configuration file, they would let
+	// a pull request run commands with the workflow's secrets.
+	configExecutableInputs = map[string]bool{
+		"custom_checks":    true,
+		"pre_run_command":  true,
+		"post_run_command": true,
+	}
+	// configSections are the sections of scorecard's configuration file read by other features,
+	// e.g. the maintainer annotations, rather than inputs.
+	configSections = map[string]bool{"annotations": true}
+)
+
+//go:embed action.yaml
+var actionMetadata []byte
+
+// actionInput is an input of the action's metadata.
+type actionInput struct {
+	Default *string `yaml:"default"`
+}
+
+// inputDefaults is a function to get the defaults of the action's inputs, by name. Inputs without a default
+// map to an empty string.
+func inputDefaults() (map[string]string, error) {
+	var metadata struct {
+		Inputs map[string]actionInput `yaml:"inputs"`
+	}
+	if err := yaml.Unmarshal(actionMetadata, &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling action.yaml: %w", err)
+	}
+	inputs := make(map[string]string, len(metadata.Inputs))
+	for name, input := range metadata.Inputs {
+		inputs[name] = ""
+		if input.Default != nil {
+			inputs[name] = *input.Default
+		}
+	}
+	return inputs, nil
+}
+
+// loadConfigFile is a function to apply the configuration file, if any, to the inputs. The default
+// configuration file is optional, while a configuration file set by the user must exist.
+func loadConfigFile() error {
+	path := os.Getenv(inputconfigfile)
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			return nil
+		}
+		path = defaultConfigFile
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	config, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errInvalidConfigFile, path, err)
+	}
+	inputs, err := inputDefaults()
+	if err != nil {
+		return err
+	}
+	applied, err := applyConfig(config, inputs)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errInvalidConfigFile, path, err)
+	}
+	if len(applied) > 0 {
+		fmt.Printf("Applied %s: %s\n", path, strings.Join(applied, ", "))
+	}
+	return nil
+}
+
+// parseConfig is a function to parse a configuration file: a map of input names to their values.
+// Lists are joined with commas, as in the comma-separated inputs.
+func parseConfig(data []byte) (map[string]string, error) {
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshalling: %w", err)
+	}
+	config := make(map[string]string, len(raw))
+	for name := range raw {
+		if configSections[name] {
+			continue
+		}
+		node := raw[name]
+		switch node.Kind {
+		case yaml.ScalarNode:
+			config[name] = node.Value
+		case yaml.SequenceNode:
+			values := make([]string, 0, len(node.Content))
+			for _, value := range node.Content {
+				if value.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("%s: list values must be scalars", name)
+				}
+				values = append(values, value.Value)
+			}
+			config[name] = strings.Join(values, ",")
+		default:
+			return nil, fmt.Errorf("%s: value must be a scalar or a list", name)
+		}
+	}
+	return config, nil
+}
+
+// applyConfig is a function to set the inputs of the configuration that the workflow does not set.
+// Inputs set by the workflow take precedence, but the runner always sets the inputs with a default,
+// so an input that has its default value is considered not set. It returns the applied inputs.
+func applyConfig(config, inputs map[string]string) ([]string, error) {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var applied []string
+	for _, name := range names {
+		defaultValue, ok := inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not an input of the action", name)
+		}
+		if configExecutableInputs[name] {
+			return nil, fmt.Errorf("%s runs code with the workflow's secrets, set it in the workflow instead", name)
+		}
+		if !configInputs[name] {
+			return nil, fmt.Errorf("%s cannot be set in the configuration file, set it in the workflow instead", name)
+		}
+		env := "INPUT_" + strings.ToUpper(name)
+		if value := os.Getenv(env); value != "" && value != defaultValue {
+			continue
+		}
+		if err := os.Setenv(env, config[name]); err != nil {
+			return nil, fmt.Errorf("error setting %s: %w", env, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
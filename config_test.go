@@ -0,0 +1,217 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseConfig(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "Scalars and lists",
+			data: "results_format: [sarif, json]\npublish_results: true\nfail_on_score: 7.5\nsub_paths:\n  - a\n  - b\n",
+			want: map[string]string{
+				"results_format":  "sarif,json",
+				"publish_results": "true",
+				"fail_on_score":   "7.5",
+				"sub_paths":       "a,b",
+			},
+		},
+		{
+			name: "Annotations",
+			data: "fail_on_score: 5\nannotations:\n  - checks: [binary-artifacts]\n    reasons:\n      - reason: test-data\n",
+			want: map[string]string{"fail_on_score": "5"},
+		},
+		{
+			name: "Empty file",
+			data: "",
+			want: map[string]string{},
+		},
+		{
+			name:    "Map value",
+			data:    "rego_data:\n  key: value\n",
+			wantErr: true,
+		},
+		{
+			name:    "List of lists",
+			data:    "sub_paths: [[a]]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Not a map",
+			data:    "- results_format\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseConfig([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("parseConfig() mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_inputDefaults(t *testing.T) {
+	t.Parallel()
+	inputs, err := inputDefaults()
+	if err != nil {
+		t.Fatalf("inputDefaults() error = %v", err)
+	}
+	for name, want := range map[string]string{"results_format": "", "publish_results": "false", "config_file": ""} {
+		if got, ok := inputs[name]; !ok || got != want {
+			t.Errorf("inputDefaults()[%s] = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_applyConfig(t *testing.T) {
+	t.Setenv(inputresultsformat, "sarif")
+	t.Setenv(inputpublishresults, "false")
+	t.Setenv(inputmaxretries, "")
+	t.Setenv(inputsarifcategory, "")
+	t.Setenv(inputdryrun, "")
+	inputs := map[string]string{
+		"results_format":  "",
+		"publish_results": "false",
+		"dry_run":         "false",
+		"max_retries":     "",
+		"sarif_category":  "",
+		"fail_on_score":   "",
+		"scorecard_bin":   "",
+		"exporters":       "",
+		"repo_token":      "",
+	}
+
+	config := map[string]string{
+		"results_format": "json",
+		"sarif_category": "scorecard",
+		"max_retries":    "5",
+	}
+	applied, err := applyConfig(config, inputs)
+	if err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"max_retries", "sarif_category"}, applied); diff != "" {
+		t.Errorf("applyConfig() applied mismatch (-want +got):\n%s", diff)
+	}
+	want := map[string]string{inputresultsformat: "sarif", inputsarifcategory: "scorecard", inputmaxretries: "5"}
+	for env, value := range want {
+		if got := os.Getenv(env); got != value {
+			t.Errorf("applyConfig() %s = %q, want %q", env, got, value)
+		}
+	}
+
+	//nolint
+	for _, config := range []map[string]string{
+		{"unknown_input": "x"},
+		{"repo_token": "ghp_x"},
+		{"fail_on_score": "0"},
+		{"scorecard_bin": "./scorecard"},
+		{"exporters": "https://example.com/results"},
+		{"dry_run": "true"},
+		{"publish_results": "false"},
+	} {
+		if _, err := applyConfig(config, inputs); err == nil {
+			t.Errorf("applyConfig(%v) error = nil, want an error", config)
+		}
+	}
+	// A pull request cannot skip the run or change what is published.
+	if got := os.Getenv(inputdryrun); got != "" {
+		t.Errorf("applyConfig() %s = %q, want it unset", inputdryrun, got)
+	}
+	if got := os.Getenv(inputpublishresults); got != "false" {
+		t.Errorf("applyConfig() %s = %q, want the workflow's value", inputpublishresults, got)
+	}
+}
+
+func Test_applyConfig_executableInputs(t *testing.T) {
+	t.Parallel()
+	inputs, err := inputDefaults()
+	if err != nil {
+		t.Fatalf("inputDefaults() error = %v", err)
+	}
+	for name := range configExecutableInputs {
+		if _, ok := inputs[name]; !ok {
+			t.Errorf("%s is not an input of the action", name)
+		}
+		if configInputs[name] {
+			t.Errorf("configInputs allows %s, which runs code", name)
+		}
+		_, err := applyConfig(map[string]string{name: "./check.sh"}, inputs)
+		if err == nil || !strings.Contains(err.Error(), "runs code") {
+			t.Errorf("applyConfig(%s) error = %v, want an error", name, err)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_loadConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scorecard.yml")
+	if err := ioutil.WriteFile(file, []byte("max_retries: 6\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv(inputconfigfile, file)
+	t.Setenv(inputmaxretries, "")
+	if err := loadConfigFile(); err != nil {
+		t.Fatalf("loadConfigFile() error = %v", err)
+	}
+	if got := os.Getenv(inputmaxretries); got != "6" {
+		t.Errorf("loadConfigFile() %s = %q, want 6", inputmaxretries, got)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("max_retrie: 6\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := loadConfigFile(); !errors.Is(err, errInvalidConfigFile) {
+		t.Errorf("loadConfigFile() error = %v, want %v", err, errInvalidConfigFile)
+	}
+
+	t.Setenv(inputconfigfile, filepath.Join(t.TempDir(), "missing.yml"))
+	if err := loadConfigFile(); err == nil {
+		t.Errorf("loadConfigFile() error = nil, want an error for a missing file")
+	}
+
+	// The default configuration file is optional.
+	t.Setenv(inputconfigfile, "")
+	if err := loadConfigFile(); err != nil {
+		t.Errorf("loadConfigFile() error = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// sarifNoFileURI is the location scorecard reports for findings that are not about a file.
+const sarifNoFileURI = "no file associated with this alert"
+
+var errInvalidCustomCheck = errors.New("invalid custom check result")
+
+// runCustomChecks is a function to run the custom check executables in the workspace and collect
+// the check results they print on stdout, in scorecard's JSON format.
+func runCustomChecks(ctx context.Context, executables []string, workspace string) ([]checkResult, error) {
+	var checks []checkResult
+	for _, executable := range executables {
+		//nolint:gosec
+		cmd := exec.CommandContext(ctx, executable)
+		cmd.Dir = workspace
+		cmd.Stderr = os.Stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("error running custom check %s: %w", executable, err)
+		}
+		results, err := parseCustomChecks(output)
+		if err != nil {
+			return nil, fmt.Errorf("error running custom check %s: %w", executable, err)
+		}
+		checks = append(checks, results...)
+	}
+	return checks, nil
+}
+
+// parseCustomChecks is a function to parse the output of a custom check: either a single check result,
+// a list of check results, or an object with a list of checks like scorecard's JSON results.
+func parseCustomChecks(output []byte) ([]checkResult, error) {
+	output = bytes.TrimSpace(output)
+	var checks []checkResult
+	switch {
+	case bytes.HasPrefix(output, []byte("[")):
+		if err := json.Unmarshal(output, &checks); err != nil {
+			return nil, fmt.Errorf("error unmarshalling custom check results: %w", err)
+		}
+	default:
+		var result struct {
+			checkResult
+			Checks []checkResult `json:"checks"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("error unmarshalling custom check results: %w", err)
+		}
+		checks = result.Checks
+		if result.Name != "" {
+			checks = append(checks, result.checkResult)
+		}
+	}
+	for i := range checks {
+		if checks[i].Name == "" {
+			return nil, fmt.Errorf("%w: a check has no name", errInvalidCustomCheck)
+		}
+		if checks[i].Score < inconclusiveScore || checks[i].Score > maxCheckScore {
+			return nil, fmt.Errorf("%w: %s has score %d", errInvalidCustomCheck, checks[i].Name, checks[i].Score)
+		}
+	}
+	return checks, nil
+}
+
+// mergeCustomChecks is a function to add the custom checks to the JSON and SARIF results files.
+// The aggregate score is left as computed by scorecard.
+func mergeCustomChecks(outputs []resultsOutput, checks []checkResult) error {
+	for _, output := range outputs {
+		var err error
+		switch output.format {
+		case "json":
+			err = mergeCustomChecksJSON(output.file, checks)
+		case sarif:
+			err = mergeCustomChecksSARIF(output.file, checks)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCustomChecksJSON is a function to append the checks to the checks of a JSON results file,
+// keeping the fields the action does not know about.
+func mergeCustomChecksJSON(file string, checks []checkResult) error {
+	var results map[string]interface{}
+	if err := readJSONFile(file, &results); err != nil {
+		return err
+	}
+	existing, _ := results["checks"].([]interface{})
+	for i := range checks {
+		existing = append(existing, checks[i])
+	}
+	results["checks"] = existing
+	return writeJSONFile(file, results)
+}
+
+// mergeCustomChecksSARIF is a function to add a rule per check, and a result per check that does not
+// get the maximum score, to the first run of a SARIF results file.
+func mergeCustomChecksSARIF(file string, checks []checkResult) error {
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return err
+	}
+	runs, _ := log["runs"].([]interface{})
+	if len(runs) == 0 {
+		return fmt.Errorf("%w: %s has no runs", errInvalidCustomCheck, file)
+	}
+	run, _ := runs[0].(map[string]interface{})
+	tool, _ := run["tool"].(map[string]interface{})
+	driver, _ := tool["driver"].(map[string]interface{})
+	if driver == nil {
+		return fmt.Errorf("%w: %s has no tool driver", errInvalidCustomCheck, file)
+	}
+	rules, _ := driver["rules"].([]interface{})
+	results, _ := run["results"].([]interface{})
+
+	for i := range checks {
+		check := &checks[i]
+		ruleID := strings.ReplaceAll(check.Name, "-", "") + "ID"
+		rule := map[string]interface{}{
+			"id":                   ruleID,
+			"name":                 check.Name,
+			"shortDescription":     map[string]string{"text": check.Name},
+			"fullDescription":      map[string]string{"text": check.Documentation.Short},
+			"defaultConfiguration": map[string]string{"level": "error"},
+		}
+		if check.Documentation.URL != "" {
+			rule["helpUri"] = check.Documentation.URL
+		}
+		rules = append(rules, rule)
+		if check.Score == inconclusiveScore || check.Score == maxCheckScore {
+			continue
+		}
+		message := fmt.Sprintf("score is %d: %s", check.Score, check.Reason)
+		if len(check.Details) > 0 {
+			message += ":\n" + strings.Join(check.Details, "\n")
+		}
+		results = append(results, map[string]interface{}{
+			"ruleId":    ruleID,
+			"ruleIndex": len(rules) - 1,
+			"level":     "error",
+			"message":   map[string]string{"text": message},
+			"locations": []interface{}{
+				map[string]interface{}{
+					"physicalLocation": map[string]interface{}{
+						"artifactLocation": map[string]string{"uri": sarifNoFileURI},
+						"region":           map[string]int{"startLine": 1},
+					},
+				},
+			},
+		})
+	}
+	driver["rules"] = rules
+	run["results"] = results
+	return writeJSONFile(file, log)
+}
+
+// readJSONFile is a function to unmarshal a JSON file.
+func readJSONFile(file string, v interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", file, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error unmarshalling %s: %w", file, err)
+	}
+	return nil
+}
+
+// writeJSONFile is a function to marshal a value into a JSON file.
+func writeJSONFile(file string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %w", file, err)
+	}
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeCustomCheck reports a single failing check.
+const fakeCustomCheck = `#!/bin/sh
+echo '{"name": "Approved-Runners", "score": 4, "reason": "unapproved runner images", "details": ["Warn: ubuntu-18.04"]}'
+`
+
+func Test_parseCustomChecks(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		output  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Single check",
+			output: `{"name": "A", "score": 10}`,
+			want:   []string{"A"},
+		},
+		{
+			name:   "List of checks",
+			output: `[{"name": "A", "score": 10}, {"name": "B", "score": -1}]`,
+			want:   []string{"A", "B"},
+		},
+		{
+			name:   "Results object",
+			output: `{"checks": [{"name": "A", "score": 3}]}`,
+			want:   []string{"A"},
+		},
+		{
+			name:    "Missing name",
+			output:  `[{"score": 3}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid score",
+			output:  `{"name": "A", "score": 11}`,
+			wantErr: true,
+		},
+		{
+			name:    "Not JSON",
+			output:  `ok`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			checks, err := parseCustomChecks([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCustomChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for i := range checks {
+				got = append(got, checks[i].Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseCustomChecks() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_runCustomChecks(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	//nolint:gosec
+	if err := ioutil.WriteFile(filepath.Join(dir, "check.sh"), []byte(fakeCustomCheck), 0o700); err != nil {
+		t.Fatalf("failed to write the custom check: %v", err)
+	}
+
+	checks, err := runCustomChecks(context.Background(), []string{"./check.sh"}, dir)
+	if err != nil {
+		t.Fatalf("runCustomChecks() error = %v", err)
+	}
+	if len(checks) != 1 || checks[0].Name != "Approved-Runners" || checks[0].Score != 4 {
+		t.Errorf("runCustomChecks() = %+v", checks)
+	}
+
+	_, err = runCustomChecks(context.Background(), []string{"./missing.sh"}, dir)
+	if err == nil {
+		t.Errorf("runCustomChecks() error = nil for a missing executable")
+	}
+}
+
+func Test_mergeCustomChecks(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputs := []resultsOutput{
+		{format: "json", file: filepath.Join(dir, "results.json")},
+		{format: sarif, file: filepath.Join(dir, "results.sarif")},
+	}
+	for _, name := range []string{"results.json", "results.sarif"} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checks := []checkResult{
+		{Name: "Approved-Runners", Score: 4, Reason: "unapproved runner images", Details: []string{"Warn: ubuntu-18.04"}},
+		{Name: "Internal-Review", Score: 10},
+	}
+
+	if err := mergeCustomChecks(outputs, checks); err != nil {
+		t.Fatalf("mergeCustomChecks() error = %v", err)
+	}
+
+	result, err := readScorecardResult(outputs[0].file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Checks) != 6 || result.Checks[4].Name != "Approved-Runners" || result.Score != 6.8 {
+		t.Errorf("mergeCustomChecks() JSON results = %+v", result)
+	}
+
+	findings, err := readSARIFFindings(outputs[1].file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the failing custom check is reported as a finding.
+	last := findings[len(findings)-1]
+	if len(findings) != 3 || last.check != "Approved-Runners" || last.path != sarifNoFileURI ||
+		last.message != "score is 4: unapproved runner images:\nWarn: ubuntu-18.04" {
+		t.Errorf("mergeCustomChecks() SARIF findings = %+v", findings)
+	}
+
+	err = mergeCustomChecks([]resultsOutput{{format: sarif, file: outputs[0].file}}, checks)
+	if !errors.Is(err, errInvalidCustomCheck) {
+		t.Errorf("mergeCustomChecks() error = %v, want %v", err, errInvalidCustomCheck)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const defectDojoFormat = "defectdojo"
+
+// defectDojoFindings is the root of a report in DefectDojo's Generic Findings Import format.
+type defectDojoFindings struct {
+	Findings []defectDojoFinding `json:"findings"`
+}
+
+// defectDojoFinding is the finding of a failing check.
+type defectDojoFinding struct {
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	Severity         string   `json:"severity"`
+	Mitigation       string   `json:"mitigation,omitempty"`
+	References       string   `json:"references,omitempty"`
+	Date             string   `json:"date,omitempty"`
+	ComponentName    string   `json:"component_name"`
+	ComponentVersion string   `json:"component_version,omitempty"`
+	UniqueIDFromTool string   `json:"unique_id_from_tool"`
+	VulnIDFromTool   string   `json:"vuln_id_from_tool"`
+	Tags             []string `json:"tags"`
+	StaticFinding    bool     `json:"static_finding"`
+	DynamicFinding   bool     `json:"dynamic_finding"`
+}
+
+// writeDefectDojoFindings is a function to render the results in DefectDojo's Generic Findings Import format,
+// with a finding per check scoring below the maximum. Inconclusive checks are left out.
+func writeDefectDojoFindings(writer io.Writer, results []*scorecardResult) error {
+	data, err := json.MarshalIndent(newDefectDojoFindings(results), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the DefectDojo findings: %w", err)
+	}
+	if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
+		return fmt.Errorf("error writing the DefectDojo findings: %w", err)
+	}
+	return nil
+}
+
+// newDefectDojoFindings is a function to convert the results to DefectDojo findings. The unique ID of
+// a finding is the repository and check, so that DefectDojo closes the finding once its check passes
+// when a later report is reimported into the same test.
+func newDefectDojoFindings(results []*scorecardResult) defectDojoFindings {
+	report := defectDojoFindings{Findings: []defectDojoFinding{}}
+	for _, result := range results {
+		for i := range result.Checks {
+			check := &result.Checks[i]
+			if check.Score == inconclusiveScore || check.Score == maxCheckScore {
+				continue
+			}
+			description := []string{fmt.Sprintf("Score %d/%d: %s", check.Score, maxCheckScore, check.Reason)}
+			description = append(description, check.Details...)
+			for _, annotation := range check.Annotations {
+				description = append(description, "Maintainer annotation: "+annotation)
+			}
+			finding := defectDojoFinding{
+				Title:            fmt.Sprintf("%s: %s", check.Name, check.Reason),
+				Description:      strings.Join(description, "\n"),
+				Severity:         defectDojoSeverity(check.Score),
+				References:       check.Documentation.URL,
+				ComponentName:    result.Repo.Name,
+				ComponentVersion: result.Repo.Commit,
+				UniqueIDFromTool: result.Repo.Name + "/" + check.Name,
+				VulnIDFromTool:   check.Name,
+				Tags:             []string{"scorecard", strings.ToLower(check.Name)},
+				StaticFinding:    true,
+			}
+			if check.Documentation.Short != "" {
+				finding.Mitigation = fmt.Sprintf("%s See %s", check.Documentation.Short, check.Documentation.URL)
+			}
+			if len(result.Date) >= len("2006-01-02") {
+				finding.Date = result.Date[:len("2006-01-02")]
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	return report
+}
+
+// defectDojoSeverity is a function to map the score of a failing check to a DefectDojo severity:
+// the lower the score, the higher the severity.
+func defectDojoSeverity(score int) string {
+	switch {
+	case score < 3:
+		return "High"
+	case score < 6:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
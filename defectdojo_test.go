@@ -0,0 +1,94 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newDefectDojoFindings(t *testing.T) {
+	t.Parallel()
+	failing := checkResult{
+		Name:        "Code-Review",
+		Reason:      "found 2 unreviewed changesets",
+		Details:     []string{"Warn: no reviews found"},
+		Annotations: []string{"The check does not apply to this repository."},
+		Score:       3,
+	}
+	failing.Documentation.Short = "Determines if the project requires code review before pull requests are merged."
+	failing.Documentation.URL = "https://example.com/docs/checks.md#code-review"
+	result := newTestResult(6,
+		checkResult{Name: "Binary-Artifacts", Reason: "no binaries found", Score: 10},
+		failing,
+		checkResult{Name: "Fuzzing", Reason: "internal error", Score: inconclusiveScore},
+		checkResult{Name: "License", Reason: "license file not detected", Score: 0},
+	)
+	result.Repo.Commit = "aa0496aa"
+	result.Date = "2022-08-01T10:00:00Z"
+
+	got := newDefectDojoFindings([]*scorecardResult{result})
+	name := "github.com/ossf/scorecard-action"
+	want := defectDojoFindings{Findings: []defectDojoFinding{
+		{
+			Title: "Code-Review: found 2 unreviewed changesets",
+			Description: "Score 3/10: found 2 unreviewed changesets\nWarn: no reviews found\n" +
+				"Maintainer annotation: The check does not apply to this repository.",
+			Severity: "Medium",
+			Mitigation: "Determines if the project requires code review before pull requests are merged. " +
+				"See https://example.com/docs/checks.md#code-review",
+			References:       "https://example.com/docs/checks.md#code-review",
+			Date:             "2022-08-01",
+			ComponentName:    name,
+			ComponentVersion: "aa0496aa",
+			UniqueIDFromTool: name + "/Code-Review",
+			VulnIDFromTool:   "Code-Review",
+			Tags:             []string{"scorecard", "code-review"},
+			StaticFinding:    true,
+		},
+		{
+			Title:            "License: license file not detected",
+			Description:      "Score 0/10: license file not detected",
+			Severity:         "High",
+			Date:             "2022-08-01",
+			ComponentName:    name,
+			ComponentVersion: "aa0496aa",
+			UniqueIDFromTool: name + "/License",
+			VulnIDFromTool:   "License",
+			Tags:             []string{"scorecard", "license"},
+			StaticFinding:    true,
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newDefectDojoFindings() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_writeDefectDojoFindings(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := writeDefectDojoFindings(&buf, []*scorecardResult{newTestResult(10)}); err != nil {
+		t.Fatalf("writeDefectDojoFindings() error = %v", err)
+	}
+	var report map[string][]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("writeDefectDojoFindings() wrote invalid JSON: %v", err)
+	}
+	if findings, ok := report["findings"]; !ok || len(findings) != 0 {
+		t.Errorf("writeDefectDojoFindings() = %s, want an empty findings list", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// checkDelta is the change of a check's score between a base and a head scorecard run.
+type checkDelta struct {
+	name string
+	base int
+	head int
+}
+
+// regressed is a function to check if the check's score dropped from base to head.
+// Inconclusive scores are never considered a regression.
+func (d checkDelta) regressed() bool {
+	return d.base != inconclusiveScore && d.head != inconclusiveScore && d.head < d.base
+}
+
+// compareResults is a function to compute the score delta of every check present in both results,
+// in the order the checks appear in the head results.
+func compareResults(base, head *scorecardResult) []checkDelta {
+	baseScores := make(map[string]int, len(base.Checks))
+	for i := range base.Checks {
+		baseScores[base.Checks[i].Name] = base.Checks[i].Score
+	}
+
+	var deltas []checkDelta
+	for i := range head.Checks {
+		check := &head.Checks[i]
+		baseScore, ok := baseScores[check.Name]
+		if !ok {
+			continue
+		}
+		deltas = append(deltas, checkDelta{name: check.Name, base: baseScore, head: check.Score})
+	}
+	return deltas
+}
+
+// regressions is a function to filter the deltas down to the checks that regressed.
+func regressions(deltas []checkDelta) []checkDelta {
+	var result []checkDelta
+	for _, d := range deltas {
+		if d.regressed() {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// writeDeltaTable is a function to render the score deltas as a markdown table, followed by the list
+// of checks that regressed.
+func writeDeltaTable(writer io.Writer, deltas []checkDelta, baseLabel, headLabel string) {
+	fmt.Fprintf(writer, "| Check | %s | %s | Delta |\n", baseLabel, headLabel)
+	fmt.Fprintf(writer, "| ----- | %s | %s | ----- |\n",
+		strings.Repeat("-", len(baseLabel)), strings.Repeat("-", len(headLabel)))
+	for _, d := range deltas {
+		delta := formatDelta(d)
+		if d.regressed() {
+			delta += " :warning:"
+		}
+		fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", d.name, formatCheckScore(d.base), formatCheckScore(d.head), delta)
+	}
+	fmt.Fprintln(writer)
+
+	regressed := regressions(deltas)
+	if len(regressed) == 0 {
+		fmt.Fprintf(writer, "No check regressed.\n")
+		return
+	}
+	names := make([]string, 0, len(regressed))
+	for _, d := range regressed {
+		names = append(names, d.name)
+	}
+	fmt.Fprintf(writer, "**%d check(s) regressed:** %s\n", len(regressed), strings.Join(names, ", "))
+}
+
+// formatDelta is a function to format the score change of a check, leaving it empty
+// when either score is inconclusive.
+func formatDelta(d checkDelta) string {
+	if d.base == inconclusiveScore || d.head == inconclusiveScore {
+		return ""
+	}
+	if d.head == d.base {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", d.head-d.base)
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestResult is a function to build scorecard results with the given check scores.
+func newTestResult(score float64, checks ...checkResult) *scorecardResult {
+	r := &scorecardResult{Score: score, Checks: checks}
+	r.Repo.Name = "github.com/ossf/scorecard-action"
+	return r
+}
+
+func Test_compareResults(t *testing.T) {
+	t.Parallel()
+	base := newTestResult(7,
+		checkResult{Name: "Binary-Artifacts", Score: 10},
+		checkResult{Name: "Branch-Protection", Score: 5},
+		checkResult{Name: "Code-Review", Score: 8},
+		checkResult{Name: "Fuzzing", Score: inconclusiveScore},
+	)
+	head := newTestResult(6,
+		checkResult{Name: "Fuzzing", Score: 0},
+		checkResult{Name: "Branch-Protection", Score: 3},
+		checkResult{Name: "Binary-Artifacts", Score: 10},
+		checkResult{Name: "Pinned-Dependencies", Score: 7},
+	)
+	want := []checkDelta{
+		{name: "Fuzzing", base: inconclusiveScore, head: 0},
+		{name: "Branch-Protection", base: 5, head: 3},
+		{name: "Binary-Artifacts", base: 10, head: 10},
+	}
+	got := compareResults(base, head)
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(checkDelta{})); diff != "" {
+		t.Errorf("compareResults() mismatch (-want +got):\n%s", diff)
+	}
+	wantRegressions := []checkDelta{{name: "Branch-Protection", base: 5, head: 3}}
+	if diff := cmp.Diff(wantRegressions, regressions(got), cmp.AllowUnexported(checkDelta{})); diff != "" {
+		t.Errorf("regressions() mismatch (-want +got):\n%s", diff)
+	}
+}
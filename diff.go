@@ -0,0 +1,225 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	diffCommand = "diff"
+	// findingPrefix prefixes the details of a check that are findings, as opposed to information.
+	findingPrefix = "Warn: "
+)
+
+var errDiffUsage = errors.New("usage: diff [--json] [--refs [--repo owner/name]] BASE HEAD")
+
+// resultsDiff is the structured comparison of two scorecard results.
+type resultsDiff struct {
+	Base   diffSide    `json:"base"`
+	Head   diffSide    `json:"head"`
+	Checks []checkDiff `json:"checks"`
+}
+
+// diffSide describes one of the compared results.
+type diffSide struct {
+	Commit string  `json:"commit"`
+	Date   string  `json:"date"`
+	Score  float64 `json:"score"`
+}
+
+// checkDiff is the comparison of a check between two results.
+type checkDiff struct {
+	Name      string   `json:"name"`
+	New       []string `json:"new_findings,omitempty"`
+	Resolved  []string `json:"resolved_findings,omitempty"`
+	BaseScore int      `json:"base_score"`
+	HeadScore int      `json:"head_score"`
+}
+
+// runDiff is a function to run the diff subcommand, which compares two JSON results files, or the results
+// of two refs of a repository with --refs, and prints the score deltas and the new and resolved findings,
+// or a JSON comparison with --json. It returns the exit code of the subcommand.
+func runDiff(ctx context.Context, writer io.Writer, args []string) int {
+	flags := flag.NewFlagSet(diffCommand, flag.ContinueOnError)
+	flags.SetOutput(writer)
+	asJSON := flags.Bool("json", false, "print a machine-readable JSON comparison")
+	refs := flags.Bool("refs", false, "compare two refs of the repository instead of two results files")
+	repository := flags.String("repo", os.Getenv(githubRepository), "repository of the refs, as owner/name")
+	if err := flags.Parse(args); err != nil {
+		return exitCodeConfig
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(writer, errDiffUsage)
+		return exitCodeConfig
+	}
+
+	var base, head *scorecardResult
+	var err error
+	if *refs {
+		base, head, err = scoreRefs(ctx, *repository, flags.Arg(0), flags.Arg(1))
+	} else {
+		if base, err = readScorecardResult(flags.Arg(0)); err == nil {
+			head, err = readScorecardResult(flags.Arg(1))
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(writer, err)
+		return exitCodeInternal
+	}
+
+	diff := diffResults(base, head)
+	if *asJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diff); err != nil {
+			fmt.Fprintf(writer, "error encoding the comparison: %v\n", err)
+			return exitCodeInternal
+		}
+		return 0
+	}
+	writeResultsDiff(writer, diff)
+	return 0
+}
+
+// scoreRefs is a function to run scorecard on two refs of the repository.
+func scoreRefs(ctx context.Context, repository, baseRef, headRef string) (*scorecardResult, *scorecardResult, error) {
+	if err := validateRepository(repository, ""); err != nil {
+		return nil, nil, err
+	}
+	dir, err := ioutil.TempDir("", "scorecard-diff-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := newGitHubClient(os.Getenv(githubAuthToken))
+	bin := scorecardBin
+	if result := os.Getenv(inputscorecardbin); result != "" {
+		bin = result
+	}
+	defer func(commit string) { scorecardCommit = commit }(scorecardCommit)
+	results := make([]*scorecardResult, 0, 2)
+	for i, ref := range []string{baseRef, headRef} {
+		sha, err := resolveActionRef(ctx, client, repository, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error resolving %s in %s: %w", ref, repository, err)
+		}
+		scorecardCommit = sha
+		cmd, err := runScorecardSettings("", "", "json", bin, repository, "", scorecardChecks)
+		if err != nil {
+			return nil, nil, err
+		}
+		file := filepath.Join(dir, fmt.Sprintf("%d.json", i))
+		if err := runScorecard(cmd, file); err != nil {
+			return nil, nil, err
+		}
+		result, err := readScorecardResult(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, result)
+	}
+	return results[0], results[1], nil
+}
+
+// diffResults is a function to compare the checks present in both results, in the order of the head results.
+// Findings are compared without their line numbers, so that findings moved by unrelated changes
+// are neither new nor resolved.
+func diffResults(base, head *scorecardResult) resultsDiff {
+	diff := resultsDiff{
+		Base: diffSide{Commit: base.Repo.Commit, Date: base.Date, Score: base.Score},
+		Head: diffSide{Commit: head.Repo.Commit, Date: head.Date, Score: head.Score},
+	}
+	baseChecks := make(map[string]*checkResult, len(base.Checks))
+	for i := range base.Checks {
+		baseChecks[base.Checks[i].Name] = &base.Checks[i]
+	}
+	for i := range head.Checks {
+		check := &head.Checks[i]
+		baseCheck, ok := baseChecks[check.Name]
+		if !ok {
+			continue
+		}
+		diff.Checks = append(diff.Checks, checkDiff{
+			Name:      check.Name,
+			BaseScore: baseCheck.Score,
+			HeadScore: check.Score,
+			New:       subtractFindings(check.Details, baseCheck.Details),
+			Resolved:  subtractFindings(baseCheck.Details, check.Details),
+		})
+	}
+	return diff
+}
+
+// subtractFindings is a function to get the findings of details that are not findings of other.
+func subtractFindings(details, other []string) []string {
+	seen := make(map[string]int)
+	for _, detail := range other {
+		if strings.HasPrefix(detail, findingPrefix) {
+			seen[findingKey(detail)]++
+		}
+	}
+	var findings []string
+	for _, detail := range details {
+		if !strings.HasPrefix(detail, findingPrefix) {
+			continue
+		}
+		key := findingKey(detail)
+		if seen[key] > 0 {
+			seen[key]--
+			continue
+		}
+		findings = append(findings, strings.TrimPrefix(detail, findingPrefix))
+	}
+	return findings
+}
+
+// findingKey is a function to identify a finding regardless of its line numbers.
+func findingKey(detail string) string {
+	return sarifLineNumbers.ReplaceAllString(detail, "$1")
+}
+
+// writeResultsDiff is a function to print the comparison as markdown: the score deltas,
+// then the new and resolved findings of each check.
+func writeResultsDiff(writer io.Writer, diff resultsDiff) {
+	fmt.Fprintf(writer, "Aggregate score: %.1f -> %.1f\n\n", diff.Base.Score, diff.Head.Score)
+	deltas := make([]checkDelta, 0, len(diff.Checks))
+	for _, c := range diff.Checks {
+		deltas = append(deltas, checkDelta{name: c.Name, base: c.BaseScore, head: c.HeadScore})
+	}
+	writeDeltaTable(writer, deltas, "Base", "Head")
+
+	for _, c := range diff.Checks {
+		if len(c.New) == 0 && len(c.Resolved) == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "\n### %s\n", c.Name)
+		for _, finding := range c.New {
+			fmt.Fprintf(writer, "- New: %s\n", finding)
+		}
+		for _, finding := range c.Resolved {
+			fmt.Fprintf(writer, "- Resolved: %s\n", finding)
+		}
+	}
+}
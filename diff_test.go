@@ -0,0 +1,122 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_diffResults(t *testing.T) {
+	t.Parallel()
+	base := newTestResult(7,
+		checkResult{Name: "Pinned-Dependencies", Score: 5, Details: []string{
+			"Warn: GitHub-owned GitHubAction not pinned by hash: .github/workflows/ci.yml:12",
+			"Warn: third-party GitHubAction not pinned by hash: .github/workflows/ci.yml:20",
+			"Info: Dockerfile dependencies are pinned",
+		}},
+		checkResult{Name: "Fuzzing", Score: 0},
+	)
+	head := newTestResult(6,
+		checkResult{Name: "Pinned-Dependencies", Score: 4, Details: []string{
+			"Warn: GitHub-owned GitHubAction not pinned by hash: .github/workflows/ci.yml:15",
+			"Warn: pipCommand not pinned by hash: Dockerfile:3",
+		}},
+		checkResult{Name: "Token-Permissions", Score: 10},
+	)
+	head.Repo.Commit = "head"
+	want := resultsDiff{
+		Base: diffSide{Score: 7},
+		Head: diffSide{Commit: "head", Score: 6},
+		Checks: []checkDiff{{
+			Name:      "Pinned-Dependencies",
+			BaseScore: 5,
+			HeadScore: 4,
+			New:       []string{"pipCommand not pinned by hash: Dockerfile:3"},
+			Resolved:  []string{"third-party GitHubAction not pinned by hash: .github/workflows/ci.yml:20"},
+		}},
+	}
+	if diff := cmp.Diff(want, diffResults(base, head)); diff != "" {
+		t.Errorf("diffResults() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_runDiff(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name string, result *scorecardResult) string {
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("base.json", newTestResult(7, checkResult{Name: "Fuzzing", Score: 0}))
+	head := write("head.json", newTestResult(8, checkResult{Name: "Fuzzing", Score: 10,
+		Details: []string{"Warn: project is not fuzzed"}}))
+
+	//nolint
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name: "markdown",
+			args: []string{base, head},
+			want: "- New: project is not fuzzed",
+		},
+		{
+			name: "json",
+			args: []string{"--json", base, head},
+			want: `"new_findings": [`,
+		},
+		{
+			name:     "missing file",
+			args:     []string{base, filepath.Join(dir, "missing.json")},
+			wantCode: exitCodeInternal,
+		},
+		{
+			name:     "one file",
+			args:     []string{base},
+			wantCode: exitCodeConfig,
+			want:     errDiffUsage.Error(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if code := runDiff(context.Background(), &out, tt.args); code != tt.wantCode {
+				t.Errorf("runDiff() = %d, want %d: %s", code, tt.wantCode, out.String())
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("runDiff() output = %q, want it to contain %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	doctorCommand = "doctor"
+	fulcioURL     = "https://fulcio.sigstore.dev"
+	rekorURL      = "https://rekor.sigstore.dev"
+	// actionsIDTokenRequestURL is set by GitHub Actions when the workflow may request an OIDC token.
+	actionsIDTokenRequestURL = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	// reachabilityTimeout bounds how long the doctor waits for an endpoint to respond.
+	reachabilityTimeout = 10 * time.Second
+
+	diagnosticOK      = "ok"
+	diagnosticWarning = "warning"
+	diagnosticError   = "error"
+)
+
+// diagnostic is the outcome of a single doctor check of the runner environment.
+type diagnostic struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorReport is the machine-readable report of the doctor subcommand.
+type doctorReport struct {
+	Diagnostics []diagnostic `json:"diagnostics"`
+	OK          bool         `json:"ok"`
+}
+
+// requiredEnvFixes are the environment variables the action needs, with how to set them.
+var requiredEnvFixes = []struct {
+	name string
+	fix  string
+}{
+	{githubRepository, "Run the action in a GitHub Actions workflow, or set GITHUB_REPOSITORY to owner/repo."},
+	{githubAuthToken, "Set the repo_token input to a read-only PAT, e.g. ${{ secrets.SCORECARD_READ_TOKEN }}."},
+	{inputresultsfile, "Set the results_file input, e.g. results.sarif."},
+	{inputresultsformat, "Set the results_format input to default, json or sarif."},
+	{inputpublishresults, "Set the publish_results input to true or false."},
+}
+
+// runDoctor is a function to run the doctor subcommand, which validates the runner environment and
+// prints actionable fixes, or a JSON report with --json. It returns the exit code of the subcommand.
+func runDoctor(ctx context.Context, writer io.Writer, args []string) int {
+	flags := flag.NewFlagSet(doctorCommand, flag.ContinueOnError)
+	flags.SetOutput(writer)
+	asJSON := flags.Bool("json", false, "print a machine-readable JSON report")
+	if err := flags.Parse(args); err != nil {
+		return policyFailureExitCode
+	}
+
+	if err := useAPIEnvironment(os.Getenv(inputapienvironment)); err != nil {
+		fmt.Fprintf(writer, "%v\n", err)
+		return policyFailureExitCode
+	}
+	if caBundle := os.Getenv(inputcabundle); caBundle != "" {
+		if err := useCABundle(caBundle); err != nil {
+			fmt.Fprintf(writer, "%v\n", err)
+			return policyFailureExitCode
+		}
+	}
+	httpClient := &http.Client{Transport: scorecardHTTPClient.Transport, Timeout: reachabilityTimeout}
+	report := diagnose(ctx, httpClient, newGitHubClient(os.Getenv(githubAuthToken)))
+	if *asJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(writer, "error encoding the report: %v\n", err)
+			return policyFailureExitCode
+		}
+	} else {
+		writeDoctorReport(writer, report)
+	}
+	if !report.OK {
+		return policyFailureExitCode
+	}
+	return 0
+}
+
+// diagnose is a function to run every doctor check of the runner environment.
+func diagnose(ctx context.Context, httpClient *http.Client, client *githubClient) doctorReport {
+	var diagnostics []diagnostic
+	for _, env := range requiredEnvFixes {
+		diagnostics = append(diagnostics, diagnoseEnv(env.name, env.fix))
+	}
+	diagnostics = append(diagnostics,
+		diagnoseToken(ctx, client, os.Getenv(githubRepository)),
+		diagnoseEndpoint(ctx, httpClient, "Fulcio", fulcioURL),
+		diagnoseEndpoint(ctx, httpClient, "Rekor", rekorURL),
+		diagnoseEndpoint(ctx, httpClient, "scorecard API", scorecardAPIURL),
+		diagnoseOIDC(),
+		diagnoseResultsPath(os.Getenv(inputresultsfile), os.Getenv(githubWorkspace)),
+		diagnoseScorecardBinary(doctorScorecardBin(), runnerGOARCH(os.Getenv(runnerArch))),
+	)
+
+	report := doctorReport{Diagnostics: diagnostics, OK: true}
+	for _, d := range diagnostics {
+		if d.Status == diagnosticError {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+// diagnoseEnv is a function to check a required environment variable is set.
+func diagnoseEnv(name, fix string) diagnostic {
+	if os.Getenv(name) == "" {
+		return diagnostic{Name: name, Status: diagnosticError, Detail: "not set", Fix: fix}
+	}
+	return diagnostic{Name: name, Status: diagnosticOK, Detail: "set"}
+}
+
+// diagnoseToken is a function to check the token can read the repository.
+func diagnoseToken(ctx context.Context, client *githubClient, repository string) diagnostic {
+	d := diagnostic{Name: "token"}
+	if client.token == "" && client.tokens == nil {
+		d.Status, d.Detail = diagnosticError, "no token to validate"
+		d.Fix = "Set the repo_token input, or app_id and app_private_key to authenticate as a GitHub App."
+		return d
+	}
+	if repository == "" {
+		d.Status, d.Detail = diagnosticWarning, "no repository to validate the token against"
+		return d
+	}
+	err := client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s", repository), nil, nil)
+	var apiErr *githubAPIError
+	switch {
+	case err == nil:
+		d.Status, d.Detail = diagnosticOK, fmt.Sprintf("can read %s", repository)
+	case errors.As(err, &apiErr) && apiErr.statusCode == http.StatusUnauthorized:
+		d.Status, d.Detail = diagnosticError, "the token is invalid or expired"
+		d.Fix = "Create a new token and update the secret passed to repo_token."
+	case errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound:
+		d.Status, d.Detail = diagnosticError, fmt.Sprintf("the token cannot read %s", repository)
+		d.Fix = "Give the token read access to the repository; for a private repository, use the repo scope."
+	default:
+		d.Status, d.Detail = diagnosticError, err.Error()
+		d.Fix = "Check the runner can reach the GitHub API."
+	}
+	return d
+}
+
+// diagnoseEndpoint is a function to check an external service is reachable.
+// Any HTTP response, whatever its status, means the service is reachable.
+func diagnoseEndpoint(ctx context.Context, httpClient *http.Client, name, url string) diagnostic {
+	d := diagnostic{Name: name}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		d.Status, d.Detail = diagnosticError, err.Error()
+		return d
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		d.Status, d.Detail = diagnosticError, fmt.Sprintf("%s is unreachable: %v", url, err)
+		d.Fix = fmt.Sprintf("Allow outbound HTTPS traffic from the runner to %s.", url)
+		return d
+	}
+	resp.Body.Close()
+	d.Status, d.Detail = diagnosticOK, fmt.Sprintf("%s is reachable", url)
+	return d
+}
+
+// diagnoseOIDC is a function to check the workflow can request an OIDC token, which publishing
+// results needs.
+func diagnoseOIDC() diagnostic {
+	d := diagnostic{Name: "OIDC"}
+	if os.Getenv(actionsIDTokenRequestURL) == "" {
+		d.Status, d.Detail = diagnosticWarning, "the workflow cannot request an OIDC token"
+		d.Fix = "Add `id-token: write` to the job permissions to publish results."
+		return d
+	}
+	d.Status, d.Detail = diagnosticOK, "the workflow can request an OIDC token"
+	return d
+}
+
+// diagnoseResultsPath is a function to check the directory of the results file is writable.
+func diagnoseResultsPath(resultsFile, workspace string) diagnostic {
+	d := diagnostic{Name: "results path"}
+	if resultsFile == "" {
+		d.Status, d.Detail = diagnosticWarning, "no results file to check"
+		return d
+	}
+	// The results file may list a file per format; they usually share a directory.
+	file := strings.TrimSpace(strings.Split(resultsFile, ",")[0])
+	dir := filepath.Dir(file)
+	if !filepath.IsAbs(dir) && workspace != "" {
+		dir = filepath.Join(workspace, dir)
+	}
+	f, err := ioutil.TempFile(dir, ".scorecard-doctor-")
+	if err != nil {
+		d.Status, d.Detail = diagnosticError, fmt.Sprintf("%s is not writable: %v", dir, err)
+		d.Fix = "Point results_file to a writable path in the workspace."
+		return d
+	}
+	f.Close()
+	os.Remove(f.Name())
+	d.Status, d.Detail = diagnosticOK, fmt.Sprintf("%s is writable", dir)
+	return d
+}
+
+// doctorScorecardBin is a function to get the scorecard binary the action would run.
+func doctorScorecardBin() string {
+	if bin := os.Getenv(inputscorecardbin); bin != "" {
+		return bin
+	}
+	return scorecardBin
+}
+
+// diagnoseScorecardBinary is a function to check the scorecard binary exists and matches
+// the architecture of the runner.
+func diagnoseScorecardBinary(bin, goarch string) diagnostic {
+	d := diagnostic{Name: "scorecard binary"}
+	if err := validateScorecardBin(bin); err != nil {
+		d.Status, d.Detail = diagnosticError, err.Error()
+		d.Fix = "Set scorecard_bin to the path of an executable scorecard binary."
+		return d
+	}
+	arch, err := binaryArch(bin)
+	switch {
+	case err != nil:
+		d.Status, d.Detail = diagnosticError, err.Error()
+	case arch != "" && arch != goarch:
+		d.Status, d.Detail = diagnosticError, fmt.Sprintf("%s is built for %s, the runner is %s", bin, arch, goarch)
+		d.Fix = fmt.Sprintf("Set scorecard_version to download the release for %s, or scorecard_bin to a %s binary.",
+			goarch, goarch)
+	default:
+		d.Status, d.Detail = diagnosticOK, fmt.Sprintf("%s runs on %s", bin, goarch)
+	}
+	return d
+}
+
+// writeDoctorReport is a function to print the diagnostics with the fix of every failing one.
+func writeDoctorReport(writer io.Writer, report doctorReport) {
+	for _, d := range report.Diagnostics {
+		fmt.Fprintf(writer, "[%s] %s: %s\n", d.Status, d.Name, d.Detail)
+		if d.Fix != "" {
+			fmt.Fprintf(writer, "    fix: %s\n", d.Fix)
+		}
+	}
+	if report.OK {
+		fmt.Fprintf(writer, "\nThe environment is ready to run scorecard.\n")
+	} else {
+		fmt.Fprintf(writer, "\nThe environment has problems that will make the action fail.\n")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func Test_diagnoseToken(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name       string
+		statusCode int
+		wantStatus string
+	}{
+		{name: "Valid token", statusCode: http.StatusOK, wantStatus: diagnosticOK},
+		{name: "Invalid token", statusCode: http.StatusUnauthorized, wantStatus: diagnosticError},
+		{name: "Repository not readable", statusCode: http.StatusNotFound, wantStatus: diagnosticError},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(`{}`))
+			}))
+			got := diagnoseToken(context.Background(), client, "owner/repo")
+			if got.Status != tt.wantStatus {
+				t.Errorf("diagnoseToken() = %+v, want status %s", got, tt.wantStatus)
+			}
+			if got.Status == diagnosticError && got.Fix == "" {
+				t.Errorf("diagnoseToken() = %+v, want a fix", got)
+			}
+		})
+	}
+}
+
+func Test_diagnoseEndpoint(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	if got := diagnoseEndpoint(context.Background(), server.Client(), "test", server.URL); got.Status != diagnosticOK {
+		t.Errorf("diagnoseEndpoint() = %+v, want reachable", got)
+	}
+	server.Close()
+	if got := diagnoseEndpoint(context.Background(), server.Client(), "test", server.URL); got.Status != diagnosticError {
+		t.Errorf("diagnoseEndpoint() = %+v, want unreachable", got)
+	}
+}
+
+func Test_diagnoseResultsPath(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	if got := diagnoseResultsPath("results.sarif,results.json", workspace); got.Status != diagnosticOK {
+		t.Errorf("diagnoseResultsPath() = %+v, want writable", got)
+	}
+	missing := filepath.Join("missing", "results.sarif")
+	if got := diagnoseResultsPath(missing, workspace); got.Status != diagnosticError {
+		t.Errorf("diagnoseResultsPath() = %+v, want not writable", got)
+	}
+}
+
+func Test_writeDoctorReport(t *testing.T) {
+	t.Parallel()
+	report := doctorReport{
+		Diagnostics: []diagnostic{
+			{Name: "GITHUB_REPOSITORY", Status: diagnosticOK, Detail: "set"},
+			{Name: "GITHUB_AUTH_TOKEN", Status: diagnosticError, Detail: "not set", Fix: "Set the repo_token input."},
+		},
+	}
+	var buf bytes.Buffer
+	writeDoctorReport(&buf, report)
+	want := `[ok] GITHUB_REPOSITORY: set
+[error] GITHUB_AUTH_TOKEN: not set
+    fix: Set the repo_token input.
+
+The environment has problems that will make the action fail.
+`
+	if buf.String() != want {
+		t.Errorf("writeDoctorReport() = %q, want %q", buf.String(), want)
+	}
+}
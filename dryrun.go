@@ -0,0 +1,186 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// dryRun is a function to validate the inputs and print the plan of the run: the exact scorecard
+// commands and what would be done with the results. Neither scorecard nor any external service is called.
+func dryRun(writer io.Writer) error {
+	printEnvVariables(writer)
+	if err := validate(writer); err != nil {
+		return err
+	}
+	reportTokens(writer)
+	fmt.Fprintf(writer, "\nDry run: scorecard is not run and no external service is called.\n\n")
+
+	repository := os.Getenv(githubRepository)
+	if scorecardAppID != 0 {
+		fmt.Fprintf(writer, "Would authenticate as the GitHub App %d.\n", scorecardAppID)
+	}
+	fmt.Fprintf(writer, "Would get the repository information of %s.\n", repository)
+	if downloadsScorecard() {
+		fmt.Fprintf(writer, "Would download scorecard %s and verify its SHA-256 checksum is %s.\n",
+			scorecardVersion, scorecardSHA256)
+	}
+
+	switch {
+	case scorecardOrganization != "":
+		fmt.Fprintf(writer, "Would scan the repositories of the %s organization, writing the results to %s.\n",
+			scorecardOrganization, scorecardResultsDir)
+		return nil
+	case scorecardReposFile != "":
+		data, err := ioutil.ReadFile(scorecardReposFile)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", scorecardReposFile, err)
+		}
+		repos, err := parseReposFile(scorecardReposFile, data)
+		if err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			cmd, err := runScorecardSettings("", "", "json", scorecardBin, repo, "", scorecardChecks)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(writer, "Would run: %s\n", commandLine(cmd.Args))
+		}
+		return nil
+	}
+
+	features := enabledFeatures(os.Getenv(githubEventName))
+	if scorecardPreRunCommand != "" {
+		writeHookPlan(writer, inputprerun, scorecardPreRunCommand)
+	}
+	outputs := scorecardResultsOutputs
+	if features.needJSON() {
+		outputs, _ = ensureJSONOutput(outputs)
+	}
+	for _, output := range outputs {
+		if _, ok := resultsRenderers[output.format]; ok {
+			fmt.Fprintf(writer, "Would render the %s results to %s.\n", output.format, output.file)
+			continue
+		}
+		policyFile := ""
+		if output.format == sarif {
+			policyFile = scorecardPolicyFile
+		}
+		cmd, err := runScorecardSettings(os.Getenv(githubEventName), policyFile, output.format, scorecardBin,
+			repository, scorecardLocalPath, scorecardChecks)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Would run: %s > %s\n", commandLine(cmd.Args), output.file)
+		if runsPerCheck() {
+			fmt.Fprintf(writer, "  with each of the checks in its own scorecard process\n")
+		}
+		if scorecardCacheDir != "" {
+			fmt.Fprintf(writer, "  reusing the results of the checks cached in %s for the commit\n", scorecardCacheDir)
+		}
+	}
+	for _, check := range scorecardCustomChecks {
+		fmt.Fprintf(writer, "Would run the custom check %s.\n", check)
+	}
+	writeRunPlan(writer, features)
+	return nil
+}
+
+// writeRunPlan is a function to print what the enabled features would do with the results.
+func writeRunPlan(writer io.Writer, features runFeatures) {
+	if features.subPaths {
+		fmt.Fprintf(writer, "Would scan the sub-paths %s.\n", strings.Join(scorecardSubPaths, ", "))
+	}
+	if features.prComment {
+		fmt.Fprintf(writer, "Would comment the score changes on the pull request.\n")
+	}
+	if features.checkRun {
+		fmt.Fprintf(writer, "Would create a check run with the results.\n")
+	}
+	if features.skipUnchanged {
+		fmt.Fprintf(writer, "Would skip the run if nothing changed since the last analyzed run.\n")
+	}
+	if features.commitStatus {
+		fmt.Fprintf(writer, "Would set the %s commit status to the aggregate score.\n", commitStatusContext)
+	}
+	if features.newFindings {
+		fmt.Fprintf(writer, "Would remove the findings of the default branch from the SARIF results.\n")
+	}
+	if features.uploadSARIF {
+		fmt.Fprintf(writer, "Would upload the SARIF results to code scanning.\n")
+	}
+	if features.history {
+		fmt.Fprintf(writer, "Would save the results to the %s branch.\n", scorecardHistoryBranch)
+	}
+	if features.badge {
+		fmt.Fprintf(writer, "Would generate the %s badge.\n", scorecardBadgeFormat)
+	}
+	if features.issues {
+		fmt.Fprintf(writer, "Would open issues for the checks scoring below %g.\n", scorecardIssueThreshold)
+	}
+	if features.remediate {
+		fmt.Fprintf(writer, "Would propose the %s remediation(s) in pull requests.\n",
+			strings.Join(scorecardRemediations, ", "))
+	}
+	if features.baseline {
+		fmt.Fprintf(writer, "Would compare the results with the %s baseline.\n", scorecardBaselineSource)
+	}
+	if features.export {
+		fmt.Fprintf(writer, "Would export the results to %d destination(s).\n", len(scorecardExporters))
+	}
+	if features.metrics {
+		fmt.Fprintf(writer, "Would push the metrics to the Pushgateway as job %s.\n", scorecardPushgatewayJob)
+	}
+	if scorecardPostRunCommand != "" {
+		writeHookPlan(writer, inputpostrun, scorecardPostRunCommand)
+	}
+	if features.datadog {
+		fmt.Fprintf(writer, "Would send the scores to Datadog at api.%s.\n", scorecardDatadogSite)
+	}
+	if features.evaluatePolicy {
+		fmt.Fprintf(writer, "Would evaluate the policies against the results.\n")
+	}
+	if features.notify {
+		fmt.Fprintf(writer, "Would send %s notifications on %s.\n", scorecardNotifier, scorecardNotifyOn)
+	}
+	if scorecardPublishResults == "true" {
+		fmt.Fprintf(writer, "Would publish the results, unless the repository is private.\n")
+	} else {
+		fmt.Fprintf(writer, "Would not publish the results.\n")
+	}
+}
+
+// writeHookPlan is a function to print the shell command a hook input would run, as runHook runs it.
+func writeHookPlan(writer io.Writer, input, command string) {
+	fmt.Fprintf(writer, "Would run the %s in the workspace: %s\n", inputName(input),
+		commandLine([]string{hookShell, "-c", command}))
+}
+
+// commandLine is a function to format the arguments of a command as a shell command line.
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_commandLine(t *testing.T) {
+	t.Parallel()
+	got := commandLine([]string{"/scorecard", "--repo", "owner/repo", "--policy", "./my policy.yml", ""})
+	want := `/scorecard --repo owner/repo --policy "./my policy.yml" ""`
+	if got != want {
+		t.Errorf("commandLine() = %q, want %q", got, want)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_dryRun(t *testing.T) {
+	t.Setenv(githubAuthToken, "token")
+	t.Setenv(githubRepository, "owner/repo")
+	t.Setenv(githubEventName, "push")
+	t.Setenv(githubEventPath, "")
+	outputs, checkRun, token, publish := scorecardResultsOutputs, scorecardCheckRun, scorecardGitHubToken,
+		scorecardPublishResults
+	datadogAPIKey, datadogSite := scorecardDatadogAPIKey, scorecardDatadogSite
+	preRun, postRun := scorecardPreRunCommand, scorecardPostRunCommand
+	defer func() {
+		scorecardResultsOutputs, scorecardCheckRun, scorecardGitHubToken, scorecardPublishResults = outputs,
+			checkRun, token, publish
+		scorecardDatadogAPIKey, scorecardDatadogSite = datadogAPIKey, datadogSite
+		scorecardPreRunCommand, scorecardPostRunCommand = preRun, postRun
+	}()
+	scorecardResultsOutputs = []resultsOutput{{format: sarif, file: "results.sarif"}}
+	scorecardCheckRun = "true"
+	scorecardGitHubToken = "github-token"
+	scorecardPublishResults = "false"
+	scorecardDatadogAPIKey = "dd-api-key"
+	scorecardDatadogSite = "datadoghq.eu"
+	scorecardPreRunCommand = "npm ci"
+	scorecardPostRunCommand = "./upload.sh results.sarif"
+
+	var buf bytes.Buffer
+	if err := dryRun(&buf); err != nil {
+		t.Fatalf("dryRun() error = %v", err)
+	}
+	for _, want := range []string{
+		"Would run: /scorecard --repo owner/repo --format sarif --show-details --policy /policy.yml > results.sarif\n",
+		"Would run: /scorecard --repo owner/repo --format json --show-details > " + filepath.Join(os.TempDir(), "scorecard-results.json") + "\n",
+		"Would create a check run with the results.\n",
+		"Would send the scores to Datadog at api.datadoghq.eu.\n",
+		"Would run the pre_run_command in the workspace: sh -c \"npm ci\"\n",
+		"Would run the post_run_command in the workspace: sh -c \"./upload.sh results.sarif\"\n",
+		"Would not publish the results.\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("dryRun() = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
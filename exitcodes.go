@@ -0,0 +1,145 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// The exit codes of the action, by error category, so that automation can tell a bad token
+// from a service outage.
+const (
+	exitCodeInternal  = 2
+	exitCodeConfig    = 3
+	exitCodeAuth      = 4
+	exitCodeTransient = 5
+	// errorReportFile is the file, in the workspace, describing the error the action failed with.
+	errorReportFile = "error.json"
+)
+
+const (
+	errorCategoryPolicy    = "policy_failure"
+	errorCategoryInternal  = "internal"
+	errorCategoryConfig    = "config"
+	errorCategoryAuth      = "auth"
+	errorCategoryTransient = "transient"
+)
+
+var errPolicyFailed = errors.New("results do not meet the configured policies")
+
+// errorExitCodes maps each error category to the exit code of the action.
+var errorExitCodes = map[string]int{
+	errorCategoryPolicy:    policyFailureExitCode,
+	errorCategoryInternal:  exitCodeInternal,
+	errorCategoryConfig:    exitCodeConfig,
+	errorCategoryAuth:      exitCodeAuth,
+	errorCategoryTransient: exitCodeTransient,
+}
+
+// categorizedError is an error whose category is known where it happens.
+type categorizedError struct {
+	err      error
+	category string
+}
+
+func (e *categorizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.err
+}
+
+// configError is a function to mark err as an error in the inputs or the environment of the action.
+func configError(err error) error {
+	return &categorizedError{err: err, category: errorCategoryConfig}
+}
+
+// authError is a function to mark err as an authentication failure.
+func authError(err error) error {
+	return &categorizedError{err: err, category: errorCategoryAuth}
+}
+
+// errorReport is the content of the error report file.
+type errorReport struct {
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// errorCategory is a function to classify an error the action failed with.
+func errorCategory(err error) string {
+	var categorized *categorizedError
+	if errors.As(err, &categorized) {
+		return categorized.category
+	}
+	if errors.Is(err, errPolicyFailed) {
+		return errorCategoryPolicy
+	}
+	if errors.Is(err, errRateLimited) || errors.Is(err, context.DeadlineExceeded) {
+		return errorCategoryTransient
+	}
+	var apiErr *githubAPIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.statusCode == http.StatusUnauthorized || apiErr.statusCode == http.StatusForbidden:
+			return errorCategoryAuth
+		case isRetryableStatus(apiErr.statusCode):
+			return errorCategoryTransient
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return errorCategoryTransient
+	}
+	return errorCategoryInternal
+}
+
+// newErrorReport is a function to describe the error the action failed with, with its secrets redacted.
+func newErrorReport(err error) errorReport {
+	category := errorCategory(err)
+	return errorReport{Category: category, Message: redactSecrets(err.Error()), ExitCode: errorExitCodes[category]}
+}
+
+// exitWithError is a function to report the error, write the error report to the workspace
+// and exit with the exit code of the error category.
+func exitWithError(err error) {
+	report := newErrorReport(err)
+	fmt.Fprintf(os.Stderr, "Error (%s): %s\n", report.Category, report.Message)
+	if err := writeErrorReport(filepath.Join(os.Getenv(githubWorkspace), errorReportFile), report); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	flushOutput()
+	os.Exit(report.ExitCode)
+}
+
+// writeErrorReport is a function to write the error report as JSON.
+func writeErrorReport(file string, report errorReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the error report: %w", err)
+	}
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_errorCategory(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Config error",
+			err:  configError(errInputResultFileEmpty),
+			want: errorCategoryConfig,
+		},
+		{
+			name: "Policy failure",
+			err:  errPolicyFailed,
+			want: errorCategoryPolicy,
+		},
+		{
+			name: "Bad token",
+			err:  fmt.Errorf("error getting the repository information: %w", &githubAPIError{statusCode: http.StatusUnauthorized}),
+			want: errorCategoryAuth,
+		},
+		{
+			name: "Rate limited",
+			err:  &githubAPIError{statusCode: http.StatusForbidden, rateLimited: true},
+			want: errorCategoryTransient,
+		},
+		{
+			name: "Server error",
+			err:  &githubAPIError{statusCode: http.StatusBadGateway},
+			want: errorCategoryTransient,
+		},
+		{
+			name: "Network error",
+			err:  fmt.Errorf("error sending request: %w", &net.DNSError{Err: "no such host", Name: "api.github.com"}),
+			want: errorCategoryTransient,
+		},
+		{
+			name: "Unknown error",
+			err:  errors.New("error running scorecard: exit status 1"),
+			want: errorCategoryInternal,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := errorCategory(tt.err); got != tt.want {
+				t.Errorf("errorCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeErrorReport(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), errorReportFile)
+	report := newErrorReport(authError(errEmptyGitHubAuthToken))
+	if err := writeErrorReport(file, report); err != nil {
+		t.Fatalf("writeErrorReport() error = %v", err)
+	}
+	got, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := `{
+  "category": "auth",
+  "message": "repo_token variable is empty",
+  "exit_code": 4
+}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("writeErrorReport() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ossf/scorecard-action/pkg/exporter"
+)
+
+// validateExporters is a function to check an exporter exists for every destination.
+func validateExporters(destinations []string) error {
+	for _, destination := range destinations {
+		if _, err := exporter.New(destination, exporterOptions()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exporterOptions is a function to get the exporter settings from the inputs.
+func exporterOptions() exporter.Options {
+	return exporter.Options{
+		HTTPClient:    scorecardHTTPClient,
+		WebhookSecret: scorecardWebhookSecret,
+		SplunkToken:   scorecardSplunkToken,
+		DatadogAPIKey: scorecardDatadogAPIKey,
+	}
+}
+
+// datadogDestination is a function to get the destination of the Datadog exporter from the inputs.
+func datadogDestination() string {
+	return "datadog://" + scorecardDatadogSite
+}
+
+// validateDatadog is a function to check the Datadog site, when the scores are sent to Datadog.
+func validateDatadog() error {
+	if scorecardDatadogAPIKey == "" {
+		return nil
+	}
+	_, err := exporter.New(datadogDestination(), exporterOptions())
+	return err
+}
+
+// exportScheme is a function to get the scheme of a destination, to log it without its secrets,
+// e.g. a token in a webhook URL.
+func exportScheme(destination string) string {
+	if i := strings.Index(destination, "://"); i >= 0 {
+		return destination[:i] + "://"
+	}
+	return destination
+}
+
+// exportResults is a function to send the JSON results, the requested results files and the checks that
+// regressed from the baseline to every destination.
+func exportResults(ctx context.Context, destinations []string, jsonResultsFile string, requested []resultsOutput,
+	deltas []checkDelta) error {
+	data, err := ioutil.ReadFile(jsonResultsFile)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", jsonResultsFile, err)
+	}
+	results := exporter.Results{
+		JSON:       data,
+		Files:      make(map[string]string, len(requested)),
+		Repository: os.Getenv(githubRepository),
+		RunURL:     workflowRunURL(),
+	}
+	for _, output := range requested {
+		results.Files[output.format] = output.file
+	}
+	for _, d := range regressions(deltas) {
+		results.Regressions = append(results.Regressions, exporter.Regression{Check: d.name, Base: d.base, Head: d.head})
+	}
+
+	for _, destination := range destinations {
+		e, err := exporter.New(destination, exporterOptions())
+		if err != nil {
+			return err
+		}
+		if err := e.Export(ctx, results); err != nil {
+			return fmt.Errorf("error exporting the results to %s: %w", exportScheme(destination), err)
+		}
+		fmt.Printf("Exported the results to %s.\n", exportScheme(destination))
+	}
+	return nil
+}
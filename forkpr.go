@@ -0,0 +1,99 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// scorecardForkPullRequest is true for pull_request events from forks, which run with a read-only token
+// and without secrets.
+var scorecardForkPullRequest = false
+
+// isForkPullRequest is a function to check if the event is a pull request from a fork.
+// A pull request whose head repository was deleted is handled as coming from a fork.
+// pull_request_target events run in the context of the base repository, with its secrets.
+func isForkPullRequest(eventName string, event []byte) (bool, error) {
+	if eventName != "pull_request" {
+		return false, nil
+	}
+	var e struct {
+		PullRequest struct {
+			Head struct {
+				Repo *struct {
+					FullName string `json:"full_name"`
+				} `json:"repo"`
+			} `json:"head"`
+			Base struct {
+				Repo struct {
+					FullName string `json:"full_name"`
+				} `json:"repo"`
+			} `json:"base"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(event, &e); err != nil {
+		return false, fmt.Errorf("error unmarshalling the event: %w", err)
+	}
+	head, base := e.PullRequest.Head.Repo, e.PullRequest.Base.Repo.FullName
+	if base == "" {
+		return false, nil
+	}
+	return head == nil || !strings.EqualFold(head.FullName, base), nil
+}
+
+// forkDisabledFeatures are the features that need secrets or a token with write permissions,
+// which pull requests from forks do not get.
+//nolint
+var forkDisabledFeatures = []struct {
+	input   string
+	enabled func() bool
+	disable func()
+}{
+	{inputpublishresults, func() bool { return scorecardPublishResults == "true" }, func() { scorecardPublishResults = "false" }},
+	{inputprcomment, func() bool { return scorecardPRComment == "true" }, func() { scorecardPRComment = "" }},
+	{inputcheckrun, func() bool { return scorecardCheckRun == "true" }, func() { scorecardCheckRun = "" }},
+	{inputcommitstatus, func() bool { return scorecardCommitStatus == "true" }, func() { scorecardCommitStatus = "" }},
+	{inputuploadsarif, func() bool { return scorecardUploadSARIF == "true" }, func() { scorecardUploadSARIF = "" }},
+	{inputexporters, func() bool { return len(scorecardExporters) > 0 }, func() { scorecardExporters = nil }},
+	{inputpushgatewayurl, func() bool { return scorecardPushgatewayURL != "" }, func() { scorecardPushgatewayURL = "" }},
+	{inputnotifier, func() bool { return scorecardNotifyURL != "" }, func() { scorecardNotifyURL = "" }},
+}
+
+// degradeForkPullRequest is a function to run pull requests from forks with what they are allowed to do,
+// rather than failing: scorecard analyzes the checkout with the read-only workflow token when repo_token
+// is not available, and the features that need secrets or write permissions are disabled with a notice.
+func degradeForkPullRequest(writer io.Writer) error {
+	if os.Getenv(githubAuthToken) == "" && scorecardGitHubToken != "" {
+		if err := os.Setenv(githubAuthToken, scorecardGitHubToken); err != nil {
+			return fmt.Errorf("error setting %s: %w", githubAuthToken, err)
+		}
+	}
+	var disabled []string
+	for _, feature := range forkDisabledFeatures {
+		if feature.enabled() {
+			feature.disable()
+			disabled = append(disabled, inputName(feature.input))
+		}
+	}
+	if len(disabled) == 0 {
+		return nil
+	}
+	fmt.Fprintf(writer, "::notice::Pull request from a fork: the token is read-only and secrets are not available, "+
+		"so %s are disabled for this run.\n", strings.Join(disabled, ", "))
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_isForkPullRequest(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name      string
+		eventName string
+		event     string
+		want      bool
+	}{
+		{
+			name:      "Fork",
+			eventName: "pull_request",
+			event:     `{"pull_request": {"head": {"repo": {"full_name": "fork/repo"}}, "base": {"repo": {"full_name": "owner/repo"}}}}`,
+			want:      true,
+		},
+		{
+			name:      "Same repository",
+			eventName: "pull_request",
+			event:     `{"pull_request": {"head": {"repo": {"full_name": "owner/repo"}}, "base": {"repo": {"full_name": "owner/repo"}}}}`,
+		},
+		{
+			name:      "Deleted head repository",
+			eventName: "pull_request",
+			event:     `{"pull_request": {"head": {"repo": null}, "base": {"repo": {"full_name": "owner/repo"}}}}`,
+			want:      true,
+		},
+		{
+			name:      "pull_request_target",
+			eventName: "pull_request_target",
+			event:     `{"pull_request": {"head": {"repo": {"full_name": "fork/repo"}}, "base": {"repo": {"full_name": "owner/repo"}}}}`,
+		},
+		{
+			name:      "Push",
+			eventName: "push",
+			event:     `{"repository": {"full_name": "owner/repo"}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := isForkPullRequest(tt.eventName, []byte(tt.event))
+			if err != nil {
+				t.Fatalf("isForkPullRequest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isForkPullRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_degradeForkPullRequest(t *testing.T) {
+	t.Setenv(githubAuthToken, "")
+	publish, comment, checkRun, token := scorecardPublishResults, scorecardPRComment, scorecardCheckRun,
+		scorecardGitHubToken
+	defer func() {
+		scorecardPublishResults, scorecardPRComment, scorecardCheckRun = publish, comment, checkRun
+		scorecardGitHubToken = token
+	}()
+	scorecardPublishResults, scorecardPRComment, scorecardCheckRun = "true", "true", ""
+	scorecardGitHubToken = "read-only-token"
+
+	var out bytes.Buffer
+	if err := degradeForkPullRequest(&out); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(githubAuthToken); got != "read-only-token" {
+		t.Errorf("degradeForkPullRequest() %s = %q, want the workflow token", githubAuthToken, got)
+	}
+	if scorecardPublishResults != "false" || scorecardPRComment != "" {
+		t.Errorf("degradeForkPullRequest() left publish_results=%s pr_comment=%s", scorecardPublishResults,
+			scorecardPRComment)
+	}
+	if want := "so publish_results, pr_comment are disabled"; !strings.Contains(out.String(), want) {
+		t.Errorf("degradeForkPullRequest() notice = %q, want %q", out.String(), want)
+	}
+}
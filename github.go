@@ -0,0 +1,280 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	githubAPIURL        = "GITHUB_API_URL"
+	defaultGitHubAPIURL = "https://api.github.com"
+	// githubPageSize is the largest page size the GitHub REST API accepts.
+	githubPageSize = 100
+	// maxErrorMessageSize caps how much of an error response is kept in the error message.
+	maxErrorMessageSize = 1024
+)
+
+var errGitHubAPI = errors.New("GitHub API request failed")
+
+// githubClient is a minimal GitHub REST API client.
+// Like getRepositoryInformation, it deliberately avoids the go-github library
+// to keep the action's dependencies small.
+type githubClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	retry      retryPolicy
+	// tokens, if set, mints the token of every request in place of token.
+	tokens tokenSource
+	// lowQuota logs the remaining rate limit quota the first time it runs low.
+	lowQuota sync.Once
+}
+
+// tokenSource mints short-lived tokens, e.g. GitHub App installation tokens.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// githubAPIError is an unsuccessful response from the GitHub API.
+type githubAPIError struct {
+	method     string
+	path       string
+	message    string
+	statusCode int
+	// rateLimitWait is how long to wait before retrying a request that hit a rate limit.
+	rateLimitWait time.Duration
+	rateLimited   bool
+}
+
+func (e *githubAPIError) Error() string {
+	if e.rateLimited {
+		return fmt.Sprintf("%v: %s %s: %d %s (retry in %s)", errRateLimited, e.method, e.path, e.statusCode,
+			e.message, e.rateLimitWait)
+	}
+	return fmt.Sprintf("%v: %s %s: %d %s", errGitHubAPI, e.method, e.path, e.statusCode, e.message)
+}
+
+func (e *githubAPIError) Unwrap() error {
+	return errGitHubAPI
+}
+
+func (e *githubAPIError) Is(target error) bool {
+	return e.rateLimited && target == errRateLimited
+}
+
+// isGitHubNotFound is a function to check if err is a GitHub API "404 Not Found" response.
+func isGitHubNotFound(err error) bool {
+	var apiErr *githubAPIError
+	return errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound
+}
+
+// isGitHubUnprocessable is a function to check if err is a GitHub API "422 Unprocessable Entity" response,
+// e.g. when updating a reference that does not exist.
+func isGitHubUnprocessable(err error) bool {
+	var apiErr *githubAPIError
+	return errors.As(err, &apiErr) && apiErr.statusCode == http.StatusUnprocessableEntity
+}
+
+// newGitHubClient is a function to create a GitHub client for the API of the current GitHub instance.
+// Without a token, the client authenticates as the GitHub App, if any.
+func newGitHubClient(token string) *githubClient {
+	client := &githubClient{
+		httpClient: scorecardHTTPClient,
+		baseURL:    githubAPIBaseURL(),
+		token:      token,
+		retry:      scorecardRetryPolicy,
+	}
+	if token == "" && scorecardAppTokens != nil {
+		client.tokens = scorecardAppTokens
+	}
+	if scorecardCacheDir != "" {
+		client.httpClient = newCachingClient(client.httpClient, scorecardCacheDir)
+	}
+	return client
+}
+
+// githubAPIBaseURL is a function to get the URL of the API of the current GitHub instance.
+func githubAPIBaseURL() string {
+	baseURL := os.Getenv(githubAPIURL)
+	if baseURL == "" {
+		baseURL = defaultGitHubAPIURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// do is a function to send a request to the GitHub API.
+// The body, if any, is sent as JSON and a JSON response is decoded into out, if any.
+func (c *githubClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.send(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	return nil
+}
+
+// download is a function to get the content at a URL returned by the GitHub API, e.g. an artifact archive.
+func (c *githubClient) download(ctx context.Context, url string) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	return data, nil
+}
+
+// send is a function to send an authenticated request and return its successful response,
+// retrying transient failures with exponential backoff. Requests hitting a rate limit are retried
+// once it resets, as long as the total wait stays within the policy's cap.
+// The caller must close the response body.
+func (c *githubClient) send(ctx context.Context, method, url string, body interface{}) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("error marshalling request body: %w", err)
+		}
+	}
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendOnce(ctx, method, url, data)
+		var apiErr *githubAPIError
+		if errors.As(err, &apiErr) && apiErr.rateLimited {
+			if waited+apiErr.rateLimitWait > c.retry.maxRateLimitWait || ctx.Err() != nil {
+				return nil, err
+			}
+			fmt.Fprintf(os.Stderr, "%s %s hit the GitHub API rate limit, waiting %s for it to reset\n", method,
+				strings.TrimPrefix(url, c.baseURL), apiErr.rateLimitWait)
+			if err := sleepContext(ctx, apiErr.rateLimitWait); err != nil {
+				return nil, err
+			}
+			waited += apiErr.rateLimitWait
+			// Waiting for the rate limit is not a failed attempt.
+			attempt--
+			continue
+		}
+		retryable := err != nil && (!errors.As(err, &apiErr) || isRetryableStatus(apiErr.statusCode))
+		if !retryable || attempt >= c.retry.maxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+		delay := c.retry.delay(attempt)
+		fmt.Fprintf(os.Stderr, "%s %s failed, retrying in %s: %v\n", method, strings.TrimPrefix(url, c.baseURL),
+			delay, err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce is a function to send an authenticated request with the JSON body, if any.
+func (c *githubClient) sendOnce(ctx context.Context, method, url string, data []byte) (*http.Response, error) {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token := c.token
+	if c.tokens != nil {
+		if token, err = c.tokens.Token(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	if quota, ok := parseRateLimit(resp.Header); ok && quota.low() {
+		c.lowQuota.Do(func() { logRateLimit(os.Stderr, quota) })
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+		message := strings.TrimSpace(string(msg))
+		wait, rateLimited := rateLimitWait(resp.StatusCode, resp.Header, message, time.Now())
+		return nil, &githubAPIError{
+			method:        method,
+			path:          strings.TrimPrefix(url, c.baseURL),
+			message:       message,
+			statusCode:    resp.StatusCode,
+			rateLimitWait: wait,
+			rateLimited:   rateLimited,
+		}
+	}
+	return resp, nil
+}
+
+// fileContent is a file committed with the GitHub contents API.
+type fileContent struct {
+	Message string `json:"message,omitempty"`
+	Content string `json:"content,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+}
+
+// commitFile is a function to commit a file to the branch, replacing the file if it exists,
+// e.g. when a workflow run is re-run.
+func commitFile(ctx context.Context, client *githubClient, repository, branch, file, message string,
+	content []byte) error {
+	contentsPath := fmt.Sprintf("/repos/%s/contents/%s", repository, file)
+	var existing fileContent
+	err := client.do(ctx, http.MethodGet, contentsPath+"?ref="+url.QueryEscape(branch), nil, &existing)
+	if err != nil && !isGitHubNotFound(err) {
+		return err
+	}
+
+	update := fileContent{
+		Message: message,
+		Content: base64.StdEncoding.EncodeToString(content),
+		Branch:  branch,
+		SHA:     existing.SHA,
+	}
+	return client.do(ctx, http.MethodPut, contentsPath, &update, nil)
+}
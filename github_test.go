@@ -0,0 +1,201 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// newTestGitHubClient is a function to create a GitHub client that sends requests to a test server.
+func newTestGitHubClient(t *testing.T, handler http.Handler) *githubClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &githubClient{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		token:      "token",
+	}
+}
+
+func Test_githubClient_do(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name         string
+		status       int
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name:   "Success",
+			status: http.StatusOK,
+		},
+		{
+			name:         "Failure - not found",
+			status:       http.StatusNotFound,
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:    "Failure - server error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer token" {
+					t.Errorf("Authorization header = %q", got)
+				}
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["in"] != "value" {
+					t.Errorf("unexpected request body %v: %v", body, err)
+				}
+				w.WriteHeader(tt.status)
+				//nolint:errcheck
+				w.Write([]byte(`{"out": "value"}`))
+			}))
+
+			var out map[string]string
+			err := client.do(context.Background(), http.MethodPost, "/path", map[string]string{"in": "value"}, &out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("do() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errGitHubAPI) {
+					t.Errorf("do() error = %v, want %v", err, errGitHubAPI)
+				}
+				if isGitHubNotFound(err) != tt.wantNotFound {
+					t.Errorf("isGitHubNotFound() = %v, want %v", isGitHubNotFound(err), tt.wantNotFound)
+				}
+				return
+			}
+			if out["out"] != "value" {
+				t.Errorf("do() decoded %v", out)
+			}
+		})
+	}
+}
+
+func Test_githubClient_download(t *testing.T) {
+	t.Parallel()
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/archive" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		//nolint:errcheck
+		w.Write([]byte("content"))
+	}))
+
+	data, err := client.download(context.Background(), client.baseURL+"/archive")
+	if err != nil || string(data) != "content" {
+		t.Errorf("download() = %q, %v", data, err)
+	}
+	if _, err := client.download(context.Background(), client.baseURL+"/missing"); !isGitHubNotFound(err) {
+		t.Errorf("download() error = %v, want not found", err)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_newGitHubClient(t *testing.T) {
+	os.Unsetenv(githubAPIURL)
+	if got := newGitHubClient("").baseURL; got != defaultGitHubAPIURL {
+		t.Errorf("newGitHubClient() baseURL = %v, want %v", got, defaultGitHubAPIURL)
+	}
+	os.Setenv(githubAPIURL, "https://github.example.com/api/v3/")
+	defer os.Unsetenv(githubAPIURL)
+	if got := newGitHubClient("").baseURL; got != "https://github.example.com/api/v3" {
+		t.Errorf("newGitHubClient() baseURL = %v", got)
+	}
+}
+
+func Test_commitFile(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		exists  bool
+		wantSHA string
+	}{
+		{
+			name: "New file",
+		},
+		{
+			name:    "Replaces an existing file",
+			exists:  true,
+			wantSHA: "existing",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			committed := false
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/owner/repo/contents/results/2022-03-01-aa0496e.json" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				switch r.Method {
+				case http.MethodGet:
+					if got := r.URL.Query().Get("ref"); got != "scorecard-history" {
+						t.Errorf("ref = %q", got)
+					}
+					if !tt.exists {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					//nolint:errcheck
+					w.Write([]byte(`{"sha": "existing"}`))
+				case http.MethodPut:
+					committed = true
+					var file fileContent
+					if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+						t.Errorf("error decoding request body: %v", err)
+						return
+					}
+					content, err := base64.StdEncoding.DecodeString(file.Content)
+					if err != nil || string(content) != `{"score": 6.8}` {
+						t.Errorf("content = %q, %v", content, err)
+					}
+					if file.Branch != "scorecard-history" || file.SHA != tt.wantSHA || file.Message != "Add scorecard results" {
+						t.Errorf("unexpected file %+v", file)
+					}
+				}
+			}))
+
+			err := commitFile(context.Background(), client, "owner/repo", defaultHistoryBranch,
+				"results/2022-03-01-aa0496e.json", "Add scorecard results", []byte(`{"score": 6.8}`))
+			if err != nil {
+				t.Fatalf("commitFile() error = %v", err)
+			}
+			if !committed {
+				t.Errorf("commitFile() did not commit the file")
+			}
+		})
+	}
+}
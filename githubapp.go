@@ -0,0 +1,73 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ossf/scorecard-action/pkg/auth"
+)
+
+var (
+	errIncompleteGitHubApp = errors.New("app_id and app_private_key must be set together")
+	errInvalidGitHubAppID  = errors.New("GitHub App IDs must be positive integers")
+)
+
+// parseGitHubAppID is a function to parse a GitHub App or installation ID.
+func parseGitHubAppID(value string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidGitHubAppID, value)
+	}
+	return id, nil
+}
+
+// authenticateGitHubApp is a function to mint an installation token of the GitHub App set by the
+// app_id and app_private_key inputs. The installation token is used in place of the repo_token
+// and github_token inputs when they are empty.
+func authenticateGitHubApp(ctx context.Context) error {
+	app, err := auth.NewApp(scorecardAppID, []byte(scorecardAppPrivateKey), githubAPIBaseURL())
+	if err != nil {
+		return err
+	}
+	app.HTTPClient = scorecardHTTPClient
+	installationID := scorecardAppInstallationID
+	if installationID == 0 {
+		if installationID, err = app.InstallationID(ctx, os.Getenv(githubRepository)); err != nil {
+			return err
+		}
+	}
+	tokens := auth.NewTokenSource(app, installationID)
+	token, err := tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	registerSecrets(token)
+	// scorecard reads the token from the environment, so it gets the token minted now,
+	// which is valid for an hour.
+	if os.Getenv(githubAuthToken) == "" {
+		if err := os.Setenv(githubAuthToken, token); err != nil {
+			return fmt.Errorf("error setting %s: %w", githubAuthToken, err)
+		}
+	}
+	if scorecardGitHubToken == "" {
+		scorecardAppTokens = tokens
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+func Test_parseGitHubAppID(t *testing.T) {
+	t.Parallel()
+	if got, err := parseGitHubAppID(" 1234 "); err != nil || got != 1234 {
+		t.Errorf("parseGitHubAppID() = %v, %v", got, err)
+	}
+	for _, value := range []string{"0", "-1", "app"} {
+		if _, err := parseGitHubAppID(value); err == nil {
+			t.Errorf("parseGitHubAppID(%q) error = nil", value)
+		}
+	}
+}
+
+func Test_githubClient_tokens(t *testing.T) {
+	t.Parallel()
+	var authorization string
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	client.tokens = staticTokenSource("installation-token")
+
+	if err := client.do(context.Background(), http.MethodGet, "/path", nil, nil); err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+	if want := "Bearer installation-token"; authorization != want {
+		t.Errorf("do() Authorization = %q, want %q", authorization, want)
+	}
+}
@@ -2,4 +2,7 @@ module github.com/ossf/scorecard-action
 
 go 1.17
 
-require github.com/google/go-cmp v0.5.7
+require (
+	github.com/google/go-cmp v0.5.7
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,127 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	defaultHistoryBranch = "scorecard-history"
+	historyReadme        = "# Scorecard history\n\n" +
+		"The JSON results of each scorecard run, committed by the scorecard action.\n"
+	// shortSHALength is the length of the abbreviated commit hash in history file names.
+	shortSHALength = 7
+)
+
+// gitObject is a git tree or commit created with the GitHub git database API.
+type gitObject struct {
+	SHA string `json:"sha"`
+}
+
+// gitTreeEntry is a file of a git tree created with the GitHub git database API.
+type gitTreeEntry struct {
+	Path    string `json:"path"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// saveHistory is a function to commit the JSON results to the history branch,
+// creating the branch if it does not exist yet.
+func saveHistory(ctx context.Context, jsonResultsFile string) error {
+	content, err := ioutil.ReadFile(jsonResultsFile)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", jsonResultsFile, err)
+	}
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+
+	repository := os.Getenv(githubRepository)
+	client := newGitHubClient(scorecardGitHubToken)
+	if err := ensureHistoryBranch(ctx, client, repository, scorecardHistoryBranch); err != nil {
+		return err
+	}
+	file := historyFilePath(scorecardHistoryPath, result, os.Getenv(githubSHA))
+	message := fmt.Sprintf("Add scorecard results %s", path.Base(file))
+	if err := commitFile(ctx, client, repository, scorecardHistoryBranch, file, message, content); err != nil {
+		return err
+	}
+	fmt.Printf("Saved the results to %s on the %s branch.\n", file, scorecardHistoryBranch)
+	return nil
+}
+
+// historyFilePath is a function to get the path of the results in the history branch,
+// named after the date and commit of the run so that the files sort chronologically.
+func historyFilePath(dir string, result *scorecardResult, sha string) string {
+	if result.Repo.Commit != "" {
+		sha = result.Repo.Commit
+	}
+	if len(sha) > shortSHALength {
+		sha = sha[:shortSHALength]
+	}
+	return path.Join(strings.Trim(dir, "/"), fmt.Sprintf("%s-%s.json", result.Date, sha))
+}
+
+// ensureHistoryBranch is a function to create the history branch as an orphan branch,
+// holding only a README, if it does not exist.
+func ensureHistoryBranch(ctx context.Context, client *githubClient, repository, branch string) error {
+	err := client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/git/ref/heads/%s", repository, branch), nil, nil)
+	if err == nil || !isGitHubNotFound(err) {
+		return err
+	}
+
+	tree := struct {
+		Tree []gitTreeEntry `json:"tree"`
+	}{
+		Tree: []gitTreeEntry{{Path: "README.md", Mode: "100644", Type: "blob", Content: historyReadme}},
+	}
+	var createdTree gitObject
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/trees", repository),
+		&tree, &createdTree); err != nil {
+		return err
+	}
+
+	commit := struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}{
+		Message: "Create the scorecard history branch",
+		Tree:    createdTree.SHA,
+		Parents: []string{},
+	}
+	var createdCommit gitObject
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/commits", repository),
+		&commit, &createdCommit); err != nil {
+		return err
+	}
+
+	ref := struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}{
+		Ref: "refs/heads/" + branch,
+		SHA: createdCommit.SHA,
+	}
+	return client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/refs", repository), &ref, nil)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func Test_historyFilePath(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name   string
+		dir    string
+		commit string
+		sha    string
+		want   string
+	}{
+		{
+			name:   "Results commit",
+			commit: "aa0496ef6c7ea1bc6c9e8df1ad57e2dbe1a4c7c9",
+			sha:    "ffffffffffffffffffffffffffffffffffffffff",
+			want:   "2022-03-01-aa0496e.json",
+		},
+		{
+			name: "Falls back to GITHUB_SHA",
+			dir:  "/results/",
+			sha:  "ffffffffffffffffffffffffffffffffffffffff",
+			want: "results/2022-03-01-fffffff.json",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := &scorecardResult{Date: "2022-03-01"}
+			result.Repo.Commit = tt.commit
+			if got := historyFilePath(tt.dir, result, tt.sha); got != tt.want {
+				t.Errorf("historyFilePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ensureHistoryBranch(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name         string
+		branchExists bool
+		wantRequests []string
+	}{
+		{
+			name:         "Branch exists",
+			branchExists: true,
+			wantRequests: []string{"GET /repos/owner/repo/git/ref/heads/scorecard-history"},
+		},
+		{
+			name: "Creates an orphan branch",
+			wantRequests: []string{
+				"GET /repos/owner/repo/git/ref/heads/scorecard-history",
+				"POST /repos/owner/repo/git/trees",
+				"POST /repos/owner/repo/git/commits",
+				"POST /repos/owner/repo/git/refs",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var requests []string
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch r.URL.Path {
+				case "/repos/owner/repo/git/ref/heads/scorecard-history":
+					if !tt.branchExists {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					//nolint:errcheck
+					w.Write([]byte(`{}`))
+				case "/repos/owner/repo/git/trees":
+					//nolint:errcheck
+					w.Write([]byte(`{"sha": "tree"}`))
+				case "/repos/owner/repo/git/commits":
+					var commit struct {
+						Tree    string   `json:"tree"`
+						Parents []string `json:"parents"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&commit); err != nil || commit.Tree != "tree" ||
+						commit.Parents == nil || len(commit.Parents) != 0 {
+						t.Errorf("unexpected commit %+v: %v", commit, err)
+					}
+					//nolint:errcheck
+					w.Write([]byte(`{"sha": "commit"}`))
+				case "/repos/owner/repo/git/refs":
+					var ref map[string]string
+					if err := json.NewDecoder(r.Body).Decode(&ref); err != nil ||
+						ref["ref"] != "refs/heads/scorecard-history" || ref["sha"] != "commit" {
+						t.Errorf("unexpected ref %v: %v", ref, err)
+					}
+					w.WriteHeader(http.StatusCreated)
+				}
+			}))
+
+			if err := ensureHistoryBranch(context.Background(), client, "owner/repo", defaultHistoryBranch); err != nil {
+				t.Fatalf("ensureHistoryBranch() error = %v", err)
+			}
+			if len(requests) != len(tt.wantRequests) {
+				t.Fatalf("ensureHistoryBranch() requests = %v, want %v", requests, tt.wantRequests)
+			}
+			for i := range requests {
+				if requests[i] != tt.wantRequests[i] {
+					t.Errorf("ensureHistoryBranch() requests = %v, want %v", requests, tt.wantRequests)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// hookShell runs the hook commands, like the run steps of a workflow.
+	hookShell = "sh"
+	// The environment variables hooks get the results and metadata of the run from.
+	hookResultsFiles   = "SCORECARD_RESULTS_FILES"
+	hookResultsFormats = "SCORECARD_RESULTS_FORMATS"
+	hookRepository     = "SCORECARD_REPOSITORY"
+	hookCommit         = "SCORECARD_COMMIT"
+	hookScore          = "SCORECARD_SCORE"
+)
+
+// hookEnv is a function to get the environment of the hooks: the environment of the action, the results files
+// and formats, the analyzed repository and commit and, once the JSON results exist, the aggregate score.
+func hookEnv(outputs []resultsOutput) []string {
+	files := make([]string, 0, len(outputs))
+	formats := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		files = append(files, output.file)
+		formats = append(formats, output.format)
+	}
+	env := append(os.Environ(),
+		hookResultsFiles+"="+strings.Join(files, ","),
+		hookResultsFormats+"="+strings.Join(formats, ","),
+		hookRepository+"="+os.Getenv(githubRepository),
+		hookCommit+"="+os.Getenv(githubSHA),
+	)
+	if file, ok := jsonResultsFile(outputs); ok {
+		if result, err := readScorecardResult(file); err == nil {
+			env = append(env, fmt.Sprintf("%s=%g", hookScore, result.Score))
+		}
+	}
+	return env
+}
+
+// runHook is a function to run the command of a hook input with the shell in the workspace,
+// logging its output in a group named after the input.
+func runHook(ctx context.Context, input, command string, env []string) error {
+	name := inputName(input)
+	fmt.Printf("::group::%s\n", name)
+	defer fmt.Println("::endgroup::")
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, hookShell, "-c", command)
+	cmd.Dir = os.Getenv(githubWorkspace)
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running %s: %w", name, err)
+	}
+	return nil
+}
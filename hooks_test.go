@@ -0,0 +1,59 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_runHook(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(githubWorkspace, dir)
+	t.Setenv(githubRepository, "owner/repo")
+	t.Setenv(githubSHA, "abc123")
+	results, err := ioutil.ReadFile("testdata/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonFile := filepath.Join(dir, "results.json")
+	if err := ioutil.WriteFile(jsonFile, results, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outputs := []resultsOutput{{format: sarif, file: "results.sarif"}, {format: "json", file: jsonFile}}
+
+	command := `printf '%s|%s|%s|%s|%s' "$SCORECARD_RESULTS_FILES" "$SCORECARD_RESULTS_FORMATS" ` +
+		`"$SCORECARD_REPOSITORY" "$SCORECARD_COMMIT" "$SCORECARD_SCORE" > hook.out`
+	if err := runHook(context.Background(), inputpostrun, command, hookEnv(outputs)); err != nil {
+		t.Fatalf("runHook() error = %v", err)
+	}
+	got, err := ioutil.ReadFile(filepath.Join(dir, "hook.out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "results.sarif," + jsonFile + "|sarif,json|owner/repo|abc123|"
+	if !strings.HasPrefix(string(got), want) || strings.HasSuffix(string(got), "|") {
+		t.Errorf("runHook() environment = %q, want %q followed by the score", got, want)
+	}
+
+	err = runHook(context.Background(), inputprerun, "exit 3", hookEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "error running pre_run_command") {
+		t.Errorf("runHook() error = %v, want the failure of pre_run_command", err)
+	}
+}
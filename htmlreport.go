@@ -0,0 +1,94 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+const htmlFormat = "html"
+
+// htmlReportTemplate renders the results as a single HTML page, without external resources,
+// so that it can be opened from an artifact or published to GitHub Pages as is.
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"score":      formatCheckScore,
+	"scoreClass": scoreClass,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Scorecard report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 64em; padding: 0 1em; color: #24292f; }
+h1, h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { border: 1px solid #d0d7de; padding: .4em .8em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.score { font-weight: bold; text-align: center; white-space: nowrap; }
+.high { background: #dafbe1; }
+.medium { background: #fff8c5; }
+.low { background: #ffebe9; }
+.inconclusive { background: #eaeef2; }
+details ul { margin: .5em 0; padding-left: 1.5em; }
+details li { white-space: pre-wrap; font-family: monospace; font-size: .9em; }
+.meta { color: #57606a; }
+</style>
+</head>
+<body>
+<h1>Scorecard report</h1>
+{{range .}}
+<h2>{{.Repo.Name}}</h2>
+<p class="meta">Commit {{.Repo.Commit}}, scored on {{.Date}} by scorecard {{.Scorecard.Version}}.</p>
+<p>Aggregate score: <strong>{{printf "%.1f" .Score}}</strong> / 10</p>
+<table>
+<thead><tr><th>Check</th><th>Score</th><th>Reason</th><th>Remediation</th></tr></thead>
+<tbody>
+{{range .Checks}}<tr>
+<td>{{.Name}}</td>
+<td class="score {{scoreClass .Score}}">{{score .Score}}</td>
+<td>{{.Reason}}{{if .Details}}
+<details><summary>Details</summary><ul>{{range .Details}}<li>{{.}}</li>{{end}}</ul></details>{{end}}</td>
+<td>{{if .Documentation.URL}}<a href="{{.Documentation.URL}}">{{if .Documentation.Short}}{{.Documentation.Short}}{{else}}Documentation{{end}}</a>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// scoreClass is a function to get the CSS class highlighting a check score.
+func scoreClass(score int) string {
+	switch {
+	case score == inconclusiveScore:
+		return "inconclusive"
+	case score >= 8:
+		return "high"
+	case score >= 5:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// writeHTMLReport is a function to render the results of one or more repositories as an HTML page.
+func writeHTMLReport(writer io.Writer, results []*scorecardResult) error {
+	if err := htmlReportTemplate.Execute(writer, results); err != nil {
+		return fmt.Errorf("error rendering the HTML report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_writeHTMLReport(t *testing.T) {
+	t.Parallel()
+	check := checkResult{
+		Name:    "Token-Permissions",
+		Reason:  "non read-only tokens detected in <workflows>",
+		Details: []string{"Warn: contents permission set to write: .github/workflows/release.yml:12"},
+		Score:   4,
+	}
+	check.Documentation.URL = "https://example.com/docs/checks.md#token-permissions"
+	check.Documentation.Short = "Determines if the project's workflows follow the principle of least privilege."
+	result := newTestResult(6.5, check, checkResult{Name: "Fuzzing", Score: inconclusiveScore})
+
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf, []*scorecardResult{result}); err != nil {
+		t.Fatalf("writeHTMLReport() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"<h2>github.com/ossf/scorecard-action</h2>",
+		"<strong>6.5</strong>",
+		`<td class="score low">4</td>`,
+		`<td class="score inconclusive">?</td>`,
+		"non read-only tokens detected in &lt;workflows&gt;",
+		"<li>Warn: contents permission set to write: .github/workflows/release.yml:12</li>",
+		`<a href="https://example.com/docs/checks.md#token-permissions">Determines if the project&#39;s workflows`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeHTMLReport() does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func Test_renderResults(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputs := []resultsOutput{
+		{format: "json", file: "./testdata/results.json"},
+		{format: htmlFormat, file: filepath.Join(dir, "results.html")},
+	}
+	if !renderedOutputs(outputs) {
+		t.Errorf("renderedOutputs() = false, want true")
+	}
+	if err := renderResults(outputs, "./testdata/results.json"); err != nil {
+		t.Fatalf("renderResults() error = %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "results.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") {
+		t.Errorf("renderResults() wrote %q", data)
+	}
+	if renderedOutputs(outputs[:1]) {
+		t.Errorf("renderedOutputs() = true, want false")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// inputErrors are the errors of every invalid input, reported together so that a workflow
+// can be fixed in a single run.
+type inputErrors []error
+
+func (e inputErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, "  - "+err.Error())
+	}
+	return fmt.Sprintf("%d invalid inputs:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// Is is a function to check if any of the input errors is target.
+func (e inputErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// add is a function to record the error, if any, of an input, prefixed by the name of the input
+// as it is set in the workflow, e.g. fail_on_score. Errors of the environment have no input.
+func (e *inputErrors) add(input string, err error) {
+	if err == nil {
+		return
+	}
+	if input != "" {
+		err = fmt.Errorf("%s: %w", inputName(input), err)
+	}
+	*e = append(*e, err)
+}
+
+// check is a function to record the error, if any, of an input and report whether the input is valid.
+func (e *inputErrors) check(input string, err error) bool {
+	e.add(input, err)
+	return err == nil
+}
+
+// err is a function to get the error of the invalid inputs, if any.
+func (e inputErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// inputName is a function to get the name of the input read from an environment variable,
+// e.g. fail_on_score for INPUT_FAIL_ON_SCORE.
+func inputName(env string) string {
+	return strings.ToLower(strings.TrimPrefix(env, "INPUT_"))
+}
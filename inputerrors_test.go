@@ -0,0 +1,77 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errTest = errors.New("test error")
+
+func Test_inputErrors(t *testing.T) {
+	t.Parallel()
+	var errs inputErrors
+	if errs.err() != nil {
+		t.Fatalf("err() = %v, want nil", errs.err())
+	}
+	if !errs.check(inputfailonscore, nil) {
+		t.Errorf("check() = false for a valid input")
+	}
+	if errs.check(inputfailonscore, errInvalidScoreThreshold) {
+		t.Errorf("check() = true for an invalid input")
+	}
+	if got, want := errs.err().Error(), "fail_on_score: "+errInvalidScoreThreshold.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	errs.add("", errTest)
+	err := errs.err()
+	if !errors.Is(err, errInvalidScoreThreshold) || !errors.Is(err, errTest) {
+		t.Errorf("err() = %v, want both errors", err)
+	}
+	if errors.Is(err, errInvalidBadgeFormat) {
+		t.Errorf("err() = %v, want no %v", err, errInvalidBadgeFormat)
+	}
+	if want := "2 invalid inputs:\n  - fail_on_score: "; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Error() = %q, want prefix %q", err.Error(), want)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_initalizeENVVariables_reportsEveryInvalidInput(t *testing.T) {
+	t.Setenv(inputresultsfile, "results.json")
+	t.Setenv(inputresultsformat, "json")
+	t.Setenv(inputpublishresults, "false")
+	t.Setenv(githubEventPath, "./testdata/fork.json")
+	t.Setenv(inputfailonscore, "eleven")
+	t.Setenv(inputbadge, "gif")
+	file, format, publish, outputs := scorecardResultsFile, scorecardResultsFormat, scorecardPublishResults,
+		scorecardResultsOutputs
+	defer func() {
+		scorecardResultsFile, scorecardResultsFormat, scorecardPublishResults = file, format, publish
+		scorecardResultsOutputs = outputs
+	}()
+
+	err := initalizeENVVariables()
+	if !errors.Is(err, errInvalidScoreThreshold) || !errors.Is(err, errInvalidBadgeFormat) {
+		t.Fatalf("initalizeENVVariables() error = %v, want both invalid inputs", err)
+	}
+	for _, input := range []string{"fail_on_score: ", "badge: "} {
+		if !strings.Contains(err.Error(), input) {
+			t.Errorf("initalizeENVVariables() error = %q, want it to name %s", err.Error(), input)
+		}
+	}
+}
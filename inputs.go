@@ -0,0 +1,63 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// legacyInputs are the environment variables inputs were read from before the action read them
+// as INPUT_<NAME>, the way GitHub Actions sets them. The INPUT_<NAME> variable takes precedence;
+// a legacy variable is only used, with a deprecation warning, when the input is not set.
+//nolint
+var legacyInputs = []struct {
+	input  string
+	legacy string
+}{
+	{inputrepotoken, githubAuthToken},
+	{inputresultsfile, "SCORECARD_RESULTS_FILE"},
+	{inputresultsformat, "SCORECARD_RESULTS_FORMAT"},
+	{inputpublishresults, "SCORECARD_PUBLISH_RESULTS"},
+	{inputscorecardbin, "SCORECARD_BIN"},
+}
+
+// resolveInputs is a function to resolve every input from its INPUT_<NAME> variable, falling back to
+// its legacy variable, and to warn about the legacy variables to migrate.
+// The repo_token input is then exported as GITHUB_AUTH_TOKEN, which scorecard reads.
+func resolveInputs(writer io.Writer) error {
+	for _, l := range legacyInputs {
+		value, inputSet := os.LookupEnv(l.input)
+		legacy, legacySet := os.LookupEnv(l.legacy)
+		switch {
+		case !legacySet:
+		case inputSet && legacy != value:
+			fmt.Fprintf(writer, "::warning::%s is ignored: the %s input (%s) takes precedence. Unset %s.\n",
+				l.legacy, inputName(l.input), l.input, l.legacy)
+		case !inputSet:
+			fmt.Fprintf(writer, "::warning::%s is deprecated: set the %s input (%s) instead.\n",
+				l.legacy, inputName(l.input), l.input)
+			if err := os.Setenv(l.input, legacy); err != nil {
+				return fmt.Errorf("error setting %s: %w", l.input, err)
+			}
+		}
+	}
+	if token, ok := os.LookupEnv(inputrepotoken); ok {
+		if err := os.Setenv(githubAuthToken, token); err != nil {
+			return fmt.Errorf("error setting %s: %w", githubAuthToken, err)
+		}
+	}
+	return nil
+}
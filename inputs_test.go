@@ -0,0 +1,96 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_resolveInputs(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		want        map[string]string
+		wantWarning string
+	}{
+		{
+			name: "Input",
+			env:  map[string]string{inputresultsfile: "results.sarif"},
+			want: map[string]string{inputresultsfile: "results.sarif"},
+		},
+		{
+			name:        "Legacy variable",
+			env:         map[string]string{"SCORECARD_RESULTS_FILE": "legacy.sarif"},
+			want:        map[string]string{inputresultsfile: "legacy.sarif"},
+			wantWarning: "::warning::SCORECARD_RESULTS_FILE is deprecated: set the results_file input (INPUT_RESULTS_FILE)",
+		},
+		{
+			name:        "Input takes precedence",
+			env:         map[string]string{inputresultsfile: "results.sarif", "SCORECARD_RESULTS_FILE": "legacy.sarif"},
+			want:        map[string]string{inputresultsfile: "results.sarif"},
+			wantWarning: "::warning::SCORECARD_RESULTS_FILE is ignored: the results_file input",
+		},
+		{
+			name: "Same value",
+			env:  map[string]string{inputresultsfile: "results.sarif", "SCORECARD_RESULTS_FILE": "results.sarif"},
+			want: map[string]string{inputresultsfile: "results.sarif"},
+		},
+		{
+			name: "Repo token exported",
+			env:  map[string]string{inputrepotoken: "token"},
+			want: map[string]string{inputrepotoken: "token", githubAuthToken: "token"},
+		},
+		{
+			name:        "Legacy token",
+			env:         map[string]string{githubAuthToken: "token"},
+			want:        map[string]string{inputrepotoken: "token", githubAuthToken: "token"},
+			wantWarning: "::warning::GITHUB_AUTH_TOKEN is deprecated: set the repo_token input",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for _, l := range legacyInputs {
+				t.Setenv(l.input, "")
+				os.Unsetenv(l.input)
+				t.Setenv(l.legacy, "")
+				os.Unsetenv(l.legacy)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var out bytes.Buffer
+			if err := resolveInputs(&out); err != nil {
+				t.Fatalf("resolveInputs() error = %v", err)
+			}
+			for k, v := range tt.want {
+				if got := os.Getenv(k); got != v {
+					t.Errorf("resolveInputs() %s = %q, want %q", k, got, v)
+				}
+			}
+			if tt.wantWarning == "" && out.Len() > 0 {
+				t.Errorf("resolveInputs() warned %q", out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantWarning) {
+				t.Errorf("resolveInputs() warned %q, want %q", out.String(), tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// issueLabel marks the issues the action opens.
+	issueLabel = "scorecard"
+	// issueCheckLabelPrefix followed by the check name identifies the issue of a check,
+	// so that later runs update or close it instead of opening another.
+	issueCheckLabelPrefix = "scorecard/"
+	defaultIssueThreshold = 5
+)
+
+// issue is a GitHub issue as listed by the issues API.
+type issue struct {
+	Title  string `json:"title"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Number int `json:"number"`
+}
+
+// issueRequest creates or updates an issue.
+type issueRequest struct {
+	Title       string   `json:"title,omitempty"`
+	Body        string   `json:"body,omitempty"`
+	State       string   `json:"state,omitempty"`
+	StateReason string   `json:"state_reason,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// issueAction is what a run does with the issue of a check.
+type issueAction int
+
+const (
+	issueNone issueAction = iota
+	issueOpen
+	issueUpdate
+	issueClose
+)
+
+// syncIssues is a function to open an issue for every check scoring below the threshold, keep the
+// open issues up to date, and close the issues of the checks that recovered.
+func syncIssues(ctx context.Context, writer io.Writer, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	client := newGitHubClient(scorecardGitHubToken)
+	return updateIssues(ctx, writer, client, os.Getenv(githubRepository), result, scorecardIssueThreshold,
+		workflowRunURL())
+}
+
+// updateIssues is a function to apply the planned action to the issue of every check of the results.
+func updateIssues(ctx context.Context, writer io.Writer, client *githubClient, repository string,
+	result *scorecardResult, threshold float64, runURL string) error {
+	open, err := findCheckIssues(ctx, client, repository)
+	if err != nil {
+		return err
+	}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		existing, hasIssue := open[check.Name]
+		var body bytes.Buffer
+		switch planIssue(check, hasIssue, threshold) {
+		case issueOpen:
+			renderIssue(&body, check, threshold, runURL, result.Repo.Commit)
+			req := issueRequest{
+				Title:  issueTitle(check),
+				Body:   body.String(),
+				Labels: []string{issueLabel, issueCheckLabelPrefix + check.Name},
+			}
+			var created issue
+			if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", repository), &req, &created); err != nil {
+				return err
+			}
+			fmt.Fprintf(writer, "Opened issue #%d for %s.\n", created.Number, check.Name)
+		case issueUpdate:
+			renderIssue(&body, check, threshold, runURL, result.Repo.Commit)
+			req := issueRequest{Title: issueTitle(check), Body: body.String()}
+			path := fmt.Sprintf("/repos/%s/issues/%d", repository, existing.Number)
+			if err := client.do(ctx, http.MethodPatch, path, &req, nil); err != nil {
+				return err
+			}
+		case issueClose:
+			comment := issueComment{Body: fmt.Sprintf("%s now scores %d / 10. Closing this issue.", check.Name, check.Score)}
+			path := fmt.Sprintf("/repos/%s/issues/%d", repository, existing.Number)
+			if err := client.do(ctx, http.MethodPost, path+"/comments", &comment, nil); err != nil {
+				return err
+			}
+			req := issueRequest{State: "closed", StateReason: "completed"}
+			if err := client.do(ctx, http.MethodPatch, path, &req, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(writer, "Closed issue #%d: %s recovered.\n", existing.Number, check.Name)
+		case issueNone:
+		}
+	}
+	return nil
+}
+
+// planIssue is a function to decide what to do with the issue of a check. Inconclusive checks
+// leave their issue as it is, since their score says nothing about the fix.
+func planIssue(check *checkResult, hasIssue bool, threshold float64) issueAction {
+	if check.Score == inconclusiveScore {
+		return issueNone
+	}
+	failing := float64(check.Score) < threshold
+	switch {
+	case failing && hasIssue:
+		return issueUpdate
+	case failing:
+		return issueOpen
+	case hasIssue:
+		return issueClose
+	default:
+		return issueNone
+	}
+}
+
+// findCheckIssues is a function to get the open issues of the action, by check name.
+func findCheckIssues(ctx context.Context, client *githubClient, repository string) (map[string]issue, error) {
+	found := make(map[string]issue)
+	for page := 1; ; page++ {
+		var issues []issue
+		path := fmt.Sprintf("/repos/%s/issues?state=open&labels=%s&per_page=%d&page=%d",
+			repository, url.QueryEscape(issueLabel), githubPageSize, page)
+		if err := client.do(ctx, http.MethodGet, path, nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, i := range issues {
+			for _, label := range i.Labels {
+				if strings.HasPrefix(label.Name, issueCheckLabelPrefix) {
+					found[strings.TrimPrefix(label.Name, issueCheckLabelPrefix)] = i
+				}
+			}
+		}
+		if len(issues) < githubPageSize {
+			return found, nil
+		}
+	}
+}
+
+// issueTitle is a function to get the title of the issue of a failing check.
+func issueTitle(check *checkResult) string {
+	return fmt.Sprintf("Scorecard: %s scores %d / 10", check.Name, check.Score)
+}
+
+// renderIssue is a function to render the body of the issue of a failing check as markdown:
+// why the check failed and how to remediate it.
+func renderIssue(writer io.Writer, check *checkResult, threshold float64, runURL, commit string) {
+	fmt.Fprintf(writer, "The **%s** check scores **%d** / 10, below the threshold of %g.\n\n",
+		check.Name, check.Score, threshold)
+	fmt.Fprintf(writer, "**Reason:** %s\n\n", check.Reason)
+	if len(check.Details) > 0 {
+		fmt.Fprintf(writer, "**Details:**\n\n```\n%s\n```\n\n", strings.Join(check.Details, "\n"))
+	}
+	fmt.Fprintf(writer, "**Remediation:** ")
+	if check.Documentation.Short != "" {
+		fmt.Fprintf(writer, "%s ", check.Documentation.Short)
+	}
+	if check.Documentation.URL != "" {
+		fmt.Fprintf(writer, "See the [%s documentation](%s) for how to improve the score.", check.Name,
+			check.Documentation.URL)
+	}
+	fmt.Fprintf(writer, "\n\n")
+
+	run := "the latest Scorecard run"
+	if runURL != "" {
+		run = fmt.Sprintf("[the latest Scorecard run](%s)", runURL)
+	}
+	if commit != "" {
+		run += fmt.Sprintf(" of `%s`", commit)
+	}
+	fmt.Fprintf(writer, "_Updated by %s. This issue is closed automatically once the check scores %g or more._\n",
+		run, threshold)
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_planIssue(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name     string
+		score    int
+		hasIssue bool
+		want     issueAction
+	}{
+		{name: "Failing without issue", score: 3, want: issueOpen},
+		{name: "Failing with issue", score: 3, hasIssue: true, want: issueUpdate},
+		{name: "Recovered", score: 8, hasIssue: true, want: issueClose},
+		{name: "Passing", score: 5, want: issueNone},
+		{name: "Inconclusive with issue", score: inconclusiveScore, hasIssue: true, want: issueNone},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			check := checkResult{Name: "Branch-Protection", Score: tt.score}
+			if got := planIssue(&check, tt.hasIssue, 5); got != tt.want {
+				t.Errorf("planIssue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_renderIssue(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	var check *checkResult
+	for i := range result.Checks {
+		if result.Checks[i].Name == "Branch-Protection" {
+			check = &result.Checks[i]
+		}
+	}
+	var buf bytes.Buffer
+	renderIssue(&buf, check, 5, "https://github.com/ossf/scorecard-action/actions/runs/42", "aa0496aa")
+	got := buf.String()
+	for _, want := range []string{
+		"The **Branch-Protection** check scores **3** / 10, below the threshold of 5.",
+		"**Reason:** branch protection is not maximal on development and all release branches",
+		"Warn: number of required reviewers is only 1",
+		"[Branch-Protection documentation](https://github.com/ossf/scorecard/blob/main/docs/checks.md#branch-protection)",
+		"[the latest Scorecard run](https://github.com/ossf/scorecard-action/actions/runs/42) of `aa0496aa`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderIssue() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func Test_updateIssues(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var requests []string
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[
+				{"number": 1, "title": "Scorecard: Binary-Artifacts scores 0 / 10",
+				 "labels": [{"name": "scorecard"}, {"name": "scorecard/Binary-Artifacts"}]},
+				{"number": 2, "title": "Scorecard: Pinned-Dependencies scores 2 / 10",
+				 "labels": [{"name": "scorecard"}, {"name": "scorecard/Pinned-Dependencies"}]},
+				{"number": 3, "title": "Scorecard: CII-Best-Practices scores 0 / 10",
+				 "labels": [{"name": "scorecard"}, {"name": "scorecard/CII-Best-Practices"}]}
+			]`)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %t", r.Method, r.URL.Path, strings.Contains(string(body), `"state":"closed"`)))
+		fmt.Fprint(w, `{"number": 4}`)
+	}))
+	result := newTestResult(6.8,
+		checkResult{Name: "Binary-Artifacts", Score: 10},
+		checkResult{Name: "Branch-Protection", Score: 3},
+		checkResult{Name: "CII-Best-Practices", Score: inconclusiveScore},
+		checkResult{Name: "Pinned-Dependencies", Score: 4},
+	)
+
+	var log bytes.Buffer
+	if err := updateIssues(context.Background(), &log, client, "owner/repo", result, 5, ""); err != nil {
+		t.Fatalf("updateIssues() error = %v", err)
+	}
+	want := []string{
+		// Binary-Artifacts recovered: comment, then close.
+		"POST /repos/owner/repo/issues/1/comments false",
+		"PATCH /repos/owner/repo/issues/1 true",
+		// Branch-Protection has no issue yet.
+		"POST /repos/owner/repo/issues false",
+		// Pinned-Dependencies still fails.
+		"PATCH /repos/owner/repo/issues/2 false",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("updateIssues() requests (-want +got):\n%s", diff)
+	}
+	wantLog := "Closed issue #1: Binary-Artifacts recovered.\nOpened issue #4 for Branch-Protection.\n"
+	if log.String() != wantLog {
+		t.Errorf("updateIssues() logged %q, want %q", log.String(), wantLog)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	junitFormat = "junit"
+	// defaultJUnitThreshold is the check score below which a test case fails.
+	defaultJUnitThreshold = 5
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+}
+
+// junitTestSuite is the test suite of a repository, with a test case per check.
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitTestCase `xml:"testcase"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+}
+
+// junitProperty is a property of a test suite.
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase is the test case of a check.
+type junitTestCase struct {
+	Failure   *junitMessage `xml:"failure"`
+	Skipped   *junitMessage `xml:"skipped"`
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the failure or the reason for skipping a test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport is a function to render the results as a JUnit XML report: a test suite per repository
+// and a test case per check, failing when the check scores below the JUnit threshold.
+// Inconclusive checks are skipped.
+func writeJUnitReport(writer io.Writer, results []*scorecardResult) error {
+	report := newJUnitReport(results, scorecardJUnitThreshold)
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the JUnit report: %w", err)
+	}
+	if _, err := fmt.Fprintf(writer, "%s%s\n", xml.Header, data); err != nil {
+		return fmt.Errorf("error writing the JUnit report: %w", err)
+	}
+	return nil
+}
+
+// newJUnitReport is a function to convert the results to a JUnit report.
+func newJUnitReport(results []*scorecardResult, threshold float64) junitTestSuites {
+	report := junitTestSuites{Name: checkRunName}
+	for _, result := range results {
+		suite := junitTestSuite{
+			Name:      result.Repo.Name,
+			Timestamp: result.Date,
+			Properties: []junitProperty{
+				{Name: "score", Value: fmt.Sprintf("%.1f", result.Score)},
+				{Name: "commit", Value: result.Repo.Commit},
+				{Name: "scorecard_version", Value: result.Scorecard.Version},
+			},
+		}
+		for i := range result.Checks {
+			check := &result.Checks[i]
+			testCase := junitTestCase{
+				Name:      check.Name,
+				ClassName: result.Repo.Name,
+				SystemOut: strings.Join(check.Details, "\n"),
+			}
+			switch {
+			case check.Score == inconclusiveScore:
+				testCase.Skipped = &junitMessage{Message: check.Reason}
+				suite.Skipped++
+			case float64(check.Score) < threshold:
+				testCase.Failure = &junitMessage{
+					Message: fmt.Sprintf("score %d is below %g: %s", check.Score, threshold, check.Reason),
+					Type:    "score",
+					Text:    check.Documentation.URL,
+				}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Tests = len(suite.Cases)
+		report.Suites = append(report.Suites, suite)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+	return report
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newJUnitReport(t *testing.T) {
+	t.Parallel()
+	failing := checkResult{Name: "Code-Review", Reason: "found 2 unreviewed changesets", Score: 3}
+	failing.Documentation.URL = "https://example.com/docs/checks.md#code-review"
+	result := newTestResult(6,
+		checkResult{Name: "Binary-Artifacts", Reason: "no binaries found", Details: []string{"a", "b"}, Score: 10},
+		failing,
+		checkResult{Name: "Fuzzing", Reason: "internal error", Score: inconclusiveScore},
+	)
+	result.Date = "2022-08-01"
+
+	got := newJUnitReport([]*scorecardResult{result, result}, 5)
+	name := "github.com/ossf/scorecard-action"
+	wantSuite := junitTestSuite{
+		Name:      name,
+		Timestamp: "2022-08-01",
+		Properties: []junitProperty{
+			{Name: "score", Value: "6.0"},
+			{Name: "commit"},
+			{Name: "scorecard_version"},
+		},
+		Cases: []junitTestCase{
+			{Name: "Binary-Artifacts", ClassName: name, SystemOut: "a\nb"},
+			{
+				Name:      "Code-Review",
+				ClassName: name,
+				Failure: &junitMessage{
+					Message: "score 3 is below 5: found 2 unreviewed changesets",
+					Type:    "score",
+					Text:    "https://example.com/docs/checks.md#code-review",
+				},
+			},
+			{Name: "Fuzzing", ClassName: name, Skipped: &junitMessage{Message: "internal error"}},
+		},
+		Tests:    3,
+		Failures: 1,
+		Skipped:  1,
+	}
+	want := junitTestSuites{
+		Name:     checkRunName,
+		Suites:   []junitTestSuite{wantSuite, wantSuite},
+		Tests:    6,
+		Failures: 2,
+		Skipped:  2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newJUnitReport() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_writeJUnitReport(t *testing.T) {
+	t.Parallel()
+	result := newTestResult(6, checkResult{Name: "Code-Review", Reason: "<none>", Score: 0})
+	var buf bytes.Buffer
+	if err := writeJUnitReport(&buf, []*scorecardResult{result}); err != nil {
+		t.Fatalf("writeJUnitReport() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header+"<testsuites") {
+		t.Errorf("writeJUnitReport() = %s", buf.String())
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("writeJUnitReport() wrote invalid XML: %v", err)
+	}
+	if report.Failures != 1 || report.Suites[0].Cases[0].Failure == nil {
+		t.Errorf("writeJUnitReport() report = %+v", report)
+	}
+}
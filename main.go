@@ -14,6 +14,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -22,8 +23,15 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/ossf/scorecard-action/pkg/auth"
+	"github.com/ossf/scorecard-action/pkg/exporter"
+	"github.com/ossf/scorecard-action/pkg/notify"
+	"github.com/ossf/scorecard-action/pkg/policy"
 )
 
 var (
@@ -41,14 +49,121 @@ var (
 	errEmptyGitHubAuthToken       = errors.New("repo_token variable is empty")
 	errOnlyDefaultBranchSupported = errors.New("only default branch is supported")
 	errEmptyScorecardBin          = errors.New("scorecard_bin variable is empty")
-	enabledChecks                 = ""
+	errInvalidResultsFormat       = errors.New("invalid results format")
+	errDuplicateResultsFormat     = errors.New("duplicate results format")
+	errResultsFileCountMismatch   = errors.New("number of results files does not match number of results formats")
+	errEmptyGitHubToken           = errors.New("github_token variable is empty")
 	scorecardPrivateRepository    = ""
 	scorecardDefaultBranch        = ""
 	scorecardPublishResults       = ""
 	scorecardResultsFormat        = ""
 	scorecardResultsFile          = ""
+	scorecardResultsOutputs       []resultsOutput
+	scorecardPRComment            = ""
+	scorecardGitHubToken          = ""
+	scorecardCheckRun             = ""
+	scorecardFailOnScore          = float64(noScoreThreshold)
+	scorecardScorePolicy          *policy.ScorePolicy
+	scorecardRegoPolicies         []string
+	scorecardRegoData             = ""
+	scorecardRegoQuery            = defaultRegoQuery
+	scorecardOPABin               = defaultOPABin
+	scorecardBaselineSource       = ""
+	scorecardBaselineArtifact     = defaultBaselineArtifact
+	scorecardFailOnRegression     = ""
+	scorecardSaveHistory          = ""
+	scorecardHistoryBranch        = defaultHistoryBranch
+	scorecardHistoryPath          = ""
+	scorecardBadgeFormat          = ""
+	scorecardBadgeFile            = ""
+	scorecardBadgeBranch          = ""
+	scorecardBadgeGist            = ""
+	scorecardLocalPath            = ""
+	scorecardOrganization         = ""
+	scorecardIncludeRepos         = ""
+	scorecardExcludeRepos         = ""
+	scorecardResultsDir           = defaultResultsDir
+	scorecardParallelism          = defaultParallelism
+	scorecardReposFile            = ""
+	scorecardSubPaths             []string
+	scorecardChecks               []string
+	scorecardCustomChecks         []string
+	scorecardCheckTimeouts        checkTimeouts
+	scorecardCheckParallelism     = 0
+	scorecardTimeout              time.Duration
+	scorecardContinueOnCheckError = ""
+	scorecardPreRunCommand        = ""
+	scorecardPostRunCommand       = ""
+	scorecardCheckMetrics         = ""
+	scorecardRetryPolicy          = retryPolicy{maxRetries: defaultMaxRetries, backoff: defaultRetryBackoff}
+	scorecardAppID                int64
+	scorecardAppPrivateKey        = ""
+	scorecardAppInstallationID    int64
+	// scorecardAppTokens mints the GitHub App installation tokens used in place of github_token.
+	scorecardAppTokens *auth.TokenSource
+	scorecardDryRun    = ""
+	scorecardOffline   = ""
+	// scorecardHTTPClient sends the HTTP requests of the action.
+	scorecardHTTPClient = http.DefaultClient
+	// scorecardExporters are the destinations the results are exported to.
+	scorecardExporters      []string
+	scorecardWebhookSecret  = ""
+	scorecardSplunkToken    = ""
+	scorecardPushgatewayURL = ""
+	scorecardPushgatewayJob = defaultPushgatewayJob
+	scorecardDatadogAPIKey  = ""
+	scorecardDatadogSite    = exporter.DefaultDatadogSite
+	scorecardNotifier       = notify.NotifierSlack
+	scorecardNotifyURL      = ""
+	scorecardNotifyOn       = notify.OnRegression
+	scorecardOpenIssues     = ""
+	scorecardIssueThreshold = float64(defaultIssueThreshold)
+	// scorecardRemediations are the fixes proposed in pull requests for failing checks.
+	scorecardRemediations   []string
+	scorecardJUnitThreshold = float64(defaultJUnitThreshold)
+	scorecardSARIFCategory  = ""
+	scorecardSARIFLevels    *sarifLevels
+	scorecardSuppressions   *suppressions
+	// scorecardAnnotations are the maintainer annotations of the scorecard configuration file of the workspace.
+	scorecardAnnotations checkAnnotations
+	// suppressedFindings are the findings the suppressions removed from the SARIF results, for the step summary.
+	suppressedFindings       []suppressedFinding
+	scorecardUploadSARIF     = ""
+	scorecardNewFindingsOnly = ""
+	scorecardCommitStatus    = ""
+	scorecardCommitStatusURL = ""
+	// scorecardCacheDir is the directory the GitHub API responses and the results of the checks are cached in.
+	scorecardCacheDir      = ""
+	scorecardSkipUnchanged = ""
+	// scorecardVersion is the scorecard release to run, pinned to the SHA-256 checksum of its archive.
+	scorecardVersion = ""
+	scorecardSHA256  = ""
+	scorecardBin     = "/scorecard"
+	// scorecardRepository is the repository analyzed instead of the repository of the workflow.
+	scorecardRepository = ""
+	// scorecardRef is the branch, tag or commit analyzed instead of the head of the default branch.
+	scorecardRef = ""
 )
 
+// resultsFileExtensions maps each supported results format to the extension
+// used when deriving per-format results files from a single results_file.
+var resultsFileExtensions = map[string]string{
+	"default":        ".txt",
+	"json":           ".json",
+	sarif:            ".sarif",
+	htmlFormat:       ".html",
+	junitFormat:      ".xml",
+	probeFormat:      ".probe.json",
+	defectDojoFormat: ".defectdojo.json",
+	sonarQubeFormat:  ".sonarqube.json",
+}
+
+// resultsOutput is a results file and the format scorecard writes into it.
+type resultsOutput struct {
+	format string
+	file   string
+}
+
 type repositoryInformation struct {
 	DefaultBranch string `json:"default_branch"`
 	Private       bool   `json:"private"`
@@ -63,68 +178,609 @@ const (
 	githubRepository        = "GITHUB_REPOSITORY"
 	githubRef               = "GITHUB_REF"
 	githubWorkspace         = "GITHUB_WORKSPACE"
+	githubStepSummary       = "GITHUB_STEP_SUMMARY"
+	githubSHA               = "GITHUB_SHA"
+	githubWorkflowRef       = "GITHUB_WORKFLOW_REF"
+	//nolint:gosec
+	githubAuthToken = "GITHUB_AUTH_TOKEN"
+	//nolint:gosec
+	inputrepotoken            = "INPUT_REPO_TOKEN"
+	inputresultsfile          = "INPUT_RESULTS_FILE"
+	inputresultsformat        = "INPUT_RESULTS_FORMAT"
+	inputpublishresults       = "INPUT_PUBLISH_RESULTS"
+	inputprcomment            = "INPUT_PR_COMMENT"
+	inputcheckrun             = "INPUT_CHECK_RUN"
+	inputfailonscore          = "INPUT_FAIL_ON_SCORE"
+	inputscorepolicy          = "INPUT_SCORE_POLICY_FILE"
+	inputconfigfile           = "INPUT_CONFIG_FILE"
+	inputregopolicies         = "INPUT_REGO_POLICIES"
+	inputregodata             = "INPUT_REGO_DATA"
+	inputregoquery            = "INPUT_REGO_QUERY"
+	inputopabin               = "INPUT_OPA_BIN"
+	inputbaselinesource       = "INPUT_BASELINE_SOURCE"
+	inputapienvironment       = "INPUT_API_ENVIRONMENT"
+	inputbaselineartifact     = "INPUT_BASELINE_ARTIFACT"
+	inputfailonregression     = "INPUT_FAIL_ON_REGRESSION"
+	inputsavehistory          = "INPUT_SAVE_HISTORY"
+	inputhistorybranch        = "INPUT_HISTORY_BRANCH"
+	inputhistorypath          = "INPUT_HISTORY_PATH"
+	inputbadge                = "INPUT_BADGE"
+	inputbadgefile            = "INPUT_BADGE_FILE"
+	inputbadgebranch          = "INPUT_BADGE_BRANCH"
+	inputbadgegist            = "INPUT_BADGE_GIST"
+	inputlocalpath            = "INPUT_LOCAL_PATH"
+	inputorganization         = "INPUT_ORGANIZATION"
+	inputincluderepos         = "INPUT_INCLUDE_REPOS"
+	inputexcluderepos         = "INPUT_EXCLUDE_REPOS"
+	inputresultsdir           = "INPUT_RESULTS_DIR"
+	inputparallelism          = "INPUT_PARALLELISM"
+	inputreposfile            = "INPUT_REPOS_FILE"
+	inputsubpaths             = "INPUT_SUB_PATHS"
+	inputchecks               = "INPUT_CHECKS"
+	inputskipchecks           = "INPUT_SKIP_CHECKS"
+	inputcustomchecks         = "INPUT_CUSTOM_CHECKS"
+	inputchecktimeout         = "INPUT_CHECK_TIMEOUT"
+	inputchecktimeouts        = "INPUT_CHECK_TIMEOUTS"
+	inputtimeout              = "INPUT_TIMEOUT"
+	inputcontinueoncheckerror = "INPUT_CONTINUE_ON_CHECK_ERROR"
+	inputprerun               = "INPUT_PRE_RUN_COMMAND"
+	inputpostrun              = "INPUT_POST_RUN_COMMAND"
+	inputcheckparallelism     = "INPUT_CHECK_PARALLELISM"
+	inputcheckmetrics         = "INPUT_CHECK_METRICS"
+	inputmaxretries           = "INPUT_MAX_RETRIES"
+	inputretrybackoff         = "INPUT_RETRY_BACKOFF"
+	inputwaitonratelimit      = "INPUT_WAIT_ON_RATE_LIMIT"
+	inputmaxratelimitwait     = "INPUT_MAX_RATE_LIMIT_WAIT"
+	inputappid                = "INPUT_APP_ID"
+	//nolint:gosec
+	inputappprivatekey     = "INPUT_APP_PRIVATE_KEY"
+	inputappinstallationid = "INPUT_APP_INSTALLATION_ID"
+	inputdryrun            = "INPUT_DRY_RUN"
+	inputoffline           = "INPUT_OFFLINE"
+	inputcabundle          = "INPUT_CA_BUNDLE"
+	inputexporters         = "INPUT_EXPORTERS"
+	//nolint:gosec
+	inputwebhooksecret = "INPUT_WEBHOOK_SECRET"
 	//nolint:gosec
-	githubAuthToken     = "GITHUB_AUTH_TOKEN"
-	inputresultsfile    = "INPUT_RESULTS_FILE"
-	inputresultsformat  = "INPUT_RESULTS_FORMAT"
-	inputpublishresults = "INPUT_PUBLISH_RESULTS"
-	scorecardBin        = "/scorecard"
-	scorecardPolicyFile = "./policy.yml"
+	inputsplunkhectoken = "INPUT_SPLUNK_HEC_TOKEN"
+	inputpushgatewayurl = "INPUT_PUSHGATEWAY_URL"
+	inputpushgatewayjob = "INPUT_PUSHGATEWAY_JOB"
+	//nolint:gosec
+	inputdatadogapikey = "INPUT_DATADOG_API_KEY"
+	inputdatadogsite   = "INPUT_DATADOG_SITE"
+	//nolint:gosec
+	inputslackwebhookurl = "INPUT_SLACK_WEBHOOK_URL"
+	inputnotifyon        = "INPUT_NOTIFY_ON"
+	inputnotifier        = "INPUT_NOTIFIER"
+	//nolint:gosec
+	inputnotifywebhookurl = "INPUT_NOTIFY_WEBHOOK_URL"
+	inputopenissues       = "INPUT_OPEN_ISSUES"
+	inputissuethreshold   = "INPUT_ISSUE_THRESHOLD"
+	inputremediate        = "INPUT_REMEDIATE"
+	inputjunitthreshold   = "INPUT_JUNIT_THRESHOLD"
+	inputsarifcategory    = "INPUT_SARIF_CATEGORY"
+	inputsariflevelsfile  = "INPUT_SARIF_LEVELS_FILE"
+	inputignorefile       = "INPUT_IGNORE_FILE"
+	inputuploadsarif      = "INPUT_UPLOAD_SARIF"
+	inputnewfindingsonly  = "INPUT_NEW_FINDINGS_ONLY"
+	inputcommitstatus     = "INPUT_COMMIT_STATUS"
+	inputcommitstatusurl  = "INPUT_COMMIT_STATUS_URL"
+	inputcachedir         = "INPUT_CACHE_DIR"
+	inputskipunchanged    = "INPUT_SKIP_UNCHANGED"
+	inputscorecardversion = "INPUT_SCORECARD_VERSION"
+	inputscorecardsha256  = "INPUT_SCORECARD_SHA256"
+	inputrepository       = "INPUT_REPOSITORY"
+	inputref              = "INPUT_REF"
+	inputscorecardbin     = "INPUT_SCORECARD_BIN"
+	//nolint:gosec
+	inputgithubtoken    = "INPUT_GITHUB_TOKEN"
+	scorecardPolicyFile = "/policy.yml"
 	scorecardFork       = "SCORECARD_IS_FORK"
 	sarif               = "sarif"
 )
 
 // main is the entrypoint for the action.
 func main() {
-	// TODO - This is a port of the entrypoint.sh script.
-	// This is still a work in progress.
+	if err := redactOutput(); err != nil {
+		exitWithError(err)
+	}
+	defer flushOutput()
+	if err := resolveInputs(os.Stderr); err != nil {
+		exitWithError(configError(err))
+	}
+	registerSecretEnvs()
+	if len(os.Args) > 1 && os.Args[1] == doctorCommand {
+		code := runDoctor(context.Background(), os.Stdout, os.Args[2:])
+		flushOutput()
+		os.Exit(code)
+	}
+	if len(os.Args) > 1 && os.Args[1] == diffCommand {
+		code := runDiff(context.Background(), os.Stdout, os.Args[2:])
+		flushOutput()
+		os.Exit(code)
+	}
 	if err := initalizeENVVariables(); err != nil {
-		panic(err)
+		exitWithError(configError(err))
+	}
+	if scorecardForkPullRequest {
+		if err := degradeForkPullRequest(os.Stdout); err != nil {
+			exitWithError(err)
+		}
+	}
+	if scorecardRepository != "" {
+		if err := useTargetRepository(os.Stdout, scorecardRepository); err != nil {
+			exitWithError(err)
+		}
 	}
 	if err := checkIfRequiredENVSet(); err != nil {
-		panic(err)
+		exitWithError(configError(err))
+	}
+	if scorecardDryRun == "true" {
+		if err := dryRun(os.Stdout); err != nil {
+			exitWithError(configError(err))
+		}
+		return
+	}
+	if scorecardAppID != 0 {
+		if err := authenticateGitHubApp(context.Background()); err != nil {
+			exitWithError(authError(err))
+		}
 	}
 
 	repository := os.Getenv(githubRepository)
 	token := os.Getenv(githubAuthToken)
 
-	repo, err := getRepositoryInformation(repository, token)
-	if err != nil {
-		panic(err)
-	}
-
-	if err := updateRepositoryInformation(repo.Private, repo.DefaultBranch); err != nil {
-		panic(err)
+	var err error
+	if offlineLocal() {
+		// Without the repository information, the repository is handled as private,
+		// so that nothing is published.
+		fmt.Println("Offline local run: skipping the repository information.")
+		scorecardPrivateRepository = "true"
+	} else {
+		repo, err := getRepositoryInformation(repository, repositoryInformationToken(token))
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := updateRepositoryInformation(repo.Private, repo.DefaultBranch); err != nil {
+			exitWithError(err)
+		}
+		if usesWorkflowToken() {
+			if err := useWorkflowToken(os.Stdout); err != nil {
+				exitWithError(err)
+			}
+			token = os.Getenv(githubAuthToken)
+		}
+		if scorecardRepository != "" {
+			err := useTargetCommit(context.Background(), newGitHubClient(token), repository, scorecardDefaultBranch)
+			if err != nil {
+				exitWithError(err)
+			}
+		}
+		if scorecardRef != "" {
+			if err := useRef(context.Background(), newGitHubClient(token), repository, scorecardRef); err != nil {
+				exitWithError(err)
+			}
+		}
 	}
 
 	if err := updateEnvVariables(); err != nil {
-		panic(err)
+		exitWithError(err)
 	}
 
 	printEnvVariables(os.Stdout)
 
 	if err := validate(os.Stderr); err != nil {
-		panic(err)
+		exitWithError(configError(err))
 	}
+	reportTokens(os.Stdout)
 
-	// gets the cmd run settings
-	cmd, err := runScorecardSettings(os.Getenv(githubEventName),
-		scorecardPolicyFile, scorecardResultsFormat,
-		scorecardBin, scorecardResultsFile, os.Getenv(githubRepository))
-	if err != nil {
-		panic(err)
+	if scorecardPreRunCommand != "" {
+		err := runHook(context.Background(), inputprerun, scorecardPreRunCommand, hookEnv(scorecardResultsOutputs))
+		if err != nil {
+			exitWithError(err)
+		}
 	}
-	cmd.Dir = os.Getenv(githubWorkspace)
-	if err := cmd.Run(); err != nil {
-		panic(err)
+
+	if downloadsScorecard() {
+		bin, err := installScorecard(context.Background(), os.Stdout, newReleaseClient(token), scorecardVersion,
+			scorecardSHA256)
+		if err != nil {
+			exitWithError(err)
+		}
+		scorecardBin = bin
+	}
+	if err := checkScorecardArch(os.Stdout, scorecardBin, runnerGOARCH(os.Getenv(runnerArch))); err != nil {
+		exitWithError(configError(err))
 	}
 
-	results, err := ioutil.ReadFile(scorecardResultsFile)
-	if err != nil {
-		panic(err)
+	// The multi-repository modes scan other repositories, so none of the features below apply.
+	if scorecardOrganization != "" || scorecardReposFile != "" {
+		if scorecardOrganization != "" {
+			err = scanOrganization(context.Background())
+		} else {
+			err = scanReposFile()
+		}
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	}
+
+	features := enabledFeatures(os.Getenv(githubEventName))
+	requestedOutputs := scorecardResultsOutputs
+	headResultsFile := ""
+	if features.needJSON() {
+		scorecardResultsOutputs, headResultsFile = ensureJSONOutput(scorecardResultsOutputs)
+	}
+	var state runState
+	if features.skipUnchanged {
+		var skip bool
+		state, skip, err = skipUnchanged(context.Background(), os.Stdout, token)
+		if err != nil {
+			exitWithError(err)
+		}
+		if skip {
+			return
+		}
+	}
+	var metrics *metricsRecorder
+	if features.metrics {
+		var client *githubClient
+		if !offlineLocal() {
+			client = newGitHubClient(token)
+		}
+		metrics = startMetrics(context.Background(), client)
+	}
+
+	// Report the checks the token lacks permissions for before running them. Local runs do not use the token.
+	if cmd, err := runScorecardSettings(os.Getenv(githubEventName), "", "json", scorecardBin,
+		os.Getenv(githubRepository), scorecardLocalPath, scorecardChecks); err == nil && !isLocalRun(cmd.Args) {
+		checkTokenPermissions(context.Background(), os.Stdout, newGitHubClient(token), os.Getenv(githubRepository),
+			commandChecks(cmd.Args))
+	}
+
+	// The run timeout stops the checks early enough for the action to keep the results computed so far.
+	runCtx := context.Background()
+	if scorecardTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, scorecardTimeout)
+		defer cancel()
+	}
+
+	// scorecard renders a single format per invocation, so run it once per requested output.
+	for _, output := range scorecardResultsOutputs {
+		// The action renders the formats scorecard does not support once the JSON results are complete.
+		if _, ok := resultsRenderers[output.format]; ok {
+			continue
+		}
+		// We only use the policy file if the requested format is sarif.
+		policyFile := ""
+		if output.format == sarif {
+			policyFile = scorecardPolicyFile
+		}
+		// gets the cmd run settings
+		cmd, err := runScorecardSettings(os.Getenv(githubEventName),
+			policyFile, output.format, scorecardBin, os.Getenv(githubRepository), scorecardLocalPath, scorecardChecks)
+		if err != nil {
+			exitWithError(err)
+		}
+		cmd.Dir = os.Getenv(githubWorkspace)
+		// The probe results of separate checks cannot be merged, so they are computed by a single process.
+		if runsPerCheck() && output.format != probeFormat {
+			parallelism := scorecardCheckParallelism
+			if parallelism == 0 {
+				parallelism = len(knownChecks)
+			}
+			var client *githubClient
+			if !offlineLocal() {
+				client = newGitHubClient(token)
+			}
+			err = runScorecardPerCheck(runCtx, cmd, output, scorecardCheckTimeouts, parallelism, client,
+				scorecardContinueOnCheckError == "true")
+		} else {
+			err = runScorecard(cmd, output.file)
+		}
+		if err != nil {
+			exitWithError(err)
+		}
+		if scorecardCommit != "" && output.format == "json" {
+			if err := annotateRef(output.file, scorecardRef, scorecardCommit); err != nil {
+				exitWithError(err)
+			}
+		}
+
+		results, err := ioutil.ReadFile(output.file)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		fmt.Println(string(results))
 	}
 
-	fmt.Println(string(results))
+	if runCtx.Err() != nil {
+		fmt.Printf("::warning::The run timed out after %s: the checks that did not complete are reported as "+
+			"inconclusive and the results are partial.\n", scorecardTimeout)
+	}
+
+	if len(scorecardCustomChecks) > 0 {
+		checks, err := runCustomChecks(context.Background(), scorecardCustomChecks, os.Getenv(githubWorkspace))
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := mergeCustomChecks(scorecardResultsOutputs, checks); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("Added %d custom check(s) to the results.\n", len(checks))
+	}
+
+	if len(scorecardAnnotations) > 0 {
+		if err := annotateResults(scorecardResultsOutputs, scorecardAnnotations); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if sarifFile, ok := sarifResultsFile(scorecardResultsOutputs); ok {
+		if err := enhanceSARIF(sarifFile, scorecardSARIFCategory); err != nil {
+			exitWithError(err)
+		}
+		if scorecardSuppressions != nil {
+			suppressedFindings, err = suppressSARIF(os.Stdout, sarifFile, scorecardSuppressions, time.Now())
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Printf("Suppressed %d finding(s) of the SARIF results.\n", len(suppressedFindings))
+		}
+	}
+
+	if features.newFindings {
+		sarifFile, _ := sarifResultsFile(scorecardResultsOutputs)
+		if err := keepNewFindings(os.Stdout, sarifFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.render {
+		if err := renderResults(scorecardResultsOutputs, headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if summaryFile := os.Getenv(githubStepSummary); summaryFile != "" {
+		if err := stepSummary(summaryFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if outputFile := os.Getenv(githubOutput); outputFile != "" {
+		if err := exportOutputs(outputFile, requestedOutputs, scorecardResultsOutputs); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.subPaths {
+		if err := scanSubPaths(os.Stdout, scorecardResultsOutputs, scorecardSubPaths); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.prComment {
+		if err := postPRComment(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.checkRun {
+		if err := postCheckRun(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.commitStatus {
+		if err := postCommitStatus(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.uploadSARIF {
+		if err := uploadSARIF(context.Background(), os.Stdout); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.history {
+		if err := saveHistory(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.badge {
+		if err := generateBadge(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.issues {
+		if err := syncIssues(context.Background(), os.Stdout, headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.remediate {
+		if err := proposeRemediations(context.Background(), os.Stdout, headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.export {
+		err := exportResults(context.Background(), scorecardExporters, headResultsFile, requestedOutputs, nil)
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if features.metrics {
+		if err := metrics.push(context.Background(), headResultsFile); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	// The post-run command consumes the results before the policies are evaluated, so that it also runs
+	// when they fail.
+	if scorecardPostRunCommand != "" {
+		err := runHook(context.Background(), inputpostrun, scorecardPostRunCommand, hookEnv(scorecardResultsOutputs))
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	var baseline *scorecardResult
+	var deltas []checkDelta
+	if features.baseline {
+		baseline, deltas, err = compareBaseline(context.Background(), os.Stdout, headResultsFile)
+		switch {
+		case errors.Is(err, errBaselineNotFound):
+			// e.g. the first run, before any results were uploaded or published.
+			fmt.Printf("Skipping the baseline comparison: %v\n", err)
+		case err != nil:
+			exitWithError(err)
+		}
+	}
+
+	// The scores are sent to Datadog after the baseline comparison, which the regression event needs.
+	if features.datadog {
+		err := exportResults(context.Background(), []string{datadogDestination()}, headResultsFile, nil, deltas)
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	// Policies are evaluated last, so that a policy failure still reports the results.
+	failed := false
+	if features.evaluatePolicy {
+		result, err := readScorecardResult(headResultsFile)
+		if err != nil {
+			exitWithError(err)
+		}
+		failed = evaluatePolicies(os.Stderr, result, scorecardFailOnScore, scorecardScorePolicy) != nil
+		if len(scorecardRegoPolicies) > 0 {
+			err := evaluateRegoPolicies(context.Background(), os.Stderr, scorecardOPABin, scorecardRegoPolicies,
+				scorecardRegoData, scorecardRegoQuery, headResultsFile)
+			switch {
+			case errors.Is(err, errRegoPolicyFailed):
+				failed = true
+			case err != nil:
+				exitWithError(err)
+			}
+		}
+	}
+	regressed := len(regressions(deltas)) > 0
+	if regressed && scorecardFailOnRegression == "true" {
+		fmt.Fprintf(os.Stderr, "Failing because checks regressed from the baseline.\n")
+		failed = true
+	}
+	if features.notify {
+		if err := sendNotifications(context.Background(), headResultsFile, baseline, deltas, failed); err != nil {
+			exitWithError(err)
+		}
+	}
+	if failed {
+		exitWithError(errPolicyFailed)
+	}
+	// The state is only stored once the run succeeded, so that a failing run is not skipped next time.
+	if features.skipUnchanged {
+		if err := writeRunState(context.Background(), newGitHubClient(scorecardGitHubToken),
+			os.Getenv(githubRepository), state); err != nil {
+			exitWithError(err)
+		}
+	}
+}
+
+// runFeatures are the features of a single repository run that use the scorecard results.
+type runFeatures struct {
+	prComment      bool
+	checkRun       bool
+	evaluatePolicy bool
+	baseline       bool
+	history        bool
+	badge          bool
+	subPaths       bool
+	export         bool
+	metrics        bool
+	datadog        bool
+	notify         bool
+	issues         bool
+	remediate      bool
+	render         bool
+	uploadSARIF    bool
+	newFindings    bool
+	commitStatus   bool
+	skipUnchanged  bool
+}
+
+// enabledFeatures is a function to get the features the inputs enable for the event.
+func enabledFeatures(eventName string) runFeatures {
+	pullRequest := strings.Contains(eventName, "pull_request")
+	return runFeatures{
+		// The pull request comment compares the JSON results of the pull request with the default branch.
+		prComment: scorecardPRComment == "true" && pullRequest,
+		checkRun:  scorecardCheckRun == "true",
+		evaluatePolicy: scorecardFailOnScore != noScoreThreshold || scorecardScorePolicy != nil ||
+			len(scorecardRegoPolicies) > 0,
+		baseline: scorecardBaselineSource != "",
+		// The history only records the results of the default branch.
+		history: scorecardSaveHistory == "true" && !pullRequest,
+		// The badge shows the score of the default branch.
+		badge:    scorecardBadgeFormat != "" && !pullRequest,
+		subPaths: len(scorecardSubPaths) > 0,
+		export:   len(scorecardExporters) > 0,
+		metrics:  scorecardPushgatewayURL != "",
+		datadog:  scorecardDatadogAPIKey != "",
+		notify:   scorecardNotifyURL != "",
+		// Issues track the failing checks of the default branch.
+		issues: scorecardOpenIssues == "true" && !pullRequest,
+		// Remediations fix the default branch.
+		remediate:   len(scorecardRemediations) > 0 && !pullRequest,
+		render:      renderedOutputs(scorecardResultsOutputs),
+		uploadSARIF: scorecardUploadSARIF == "true",
+		// Pull requests are compared with the default branch.
+		newFindings:  scorecardNewFindingsOnly == "true" && pullRequest,
+		commitStatus: scorecardCommitStatus == "true",
+		// Pull requests always change the repository.
+		skipUnchanged: scorecardSkipUnchanged == "true" && !pullRequest,
+	}
+}
+
+// needJSON is a function to check if any enabled feature reads the JSON results.
+func (f runFeatures) needJSON() bool {
+	return f.prComment || f.checkRun || f.evaluatePolicy || f.baseline || f.history || f.badge || f.subPaths ||
+		f.export || f.metrics || f.datadog || f.notify || f.issues || f.remediate || f.render || f.commitStatus
+}
+
+// runsPerCheck is a function to check if checks run in their own scorecard process:
+// checks with a timeout or a parallelism limit must be, so that they can be stopped and scheduled,
+// and so must cached checks, so that they can be skipped, measured checks, so that they can be told apart,
+// and checks allowed to fail, so that their failure does not take the other checks down.
+func runsPerCheck() bool {
+	return scorecardCheckParallelism > 0 || scorecardCheckTimeouts.timeout > 0 ||
+		len(scorecardCheckTimeouts.perCheck) > 0 || scorecardCacheDir != "" || scorecardCheckMetrics == "true" ||
+		scorecardTimeout > 0 || scorecardContinueOnCheckError == "true"
+}
+
+// stepSummary is a function to write the step summary from the JSON results, when they are requested.
+func stepSummary(summaryFile string) error {
+	resultsFile, ok := jsonResultsFile(scorecardResultsOutputs)
+	if !ok {
+		fmt.Println("Skipping the step summary: add json to results_format to enable it.")
+		return nil
+	}
+	result, err := readScorecardResult(resultsFile)
+	if err != nil {
+		return err
+	}
+	if err := appendStepSummary(summaryFile, result); err != nil {
+		return err
+	}
+	return appendSuppressedFindings(summaryFile, suppressedFindings)
 }
 
 // initalizeENVVariables is a function to initialize the environment variables required for the action.
@@ -150,34 +806,333 @@ func initalizeENVVariables() error {
 		}
 	}
 
+	// The configuration file only sets inputs, so it is applied before they are read.
+	if err := loadConfigFile(); err != nil {
+		return err
+	}
+
+	// Every input is validated, so that all the invalid ones are reported at once.
+	var errs inputErrors
 	if result, exists := os.LookupEnv(inputresultsfile); !exists {
-		return errInputResultFileNotSet
+		errs.add("", errInputResultFileNotSet)
+	} else if result == "" {
+		errs.add("", errInputResultFileEmpty)
 	} else {
-		if result == "" {
-			return errInputResultFileEmpty
-		}
 		scorecardResultsFile = result
 	}
 
 	if result, exists := os.LookupEnv(inputresultsformat); !exists {
-		return errInputResultFormatNotSet
+		errs.add("", errInputResultFormatNotSet)
+	} else if result == "" {
+		errs.add("", errInputResultFormatEmtpy)
 	} else {
-		if result == "" {
-			return errInputResultFormatEmtpy
-		}
 		scorecardResultsFormat = result
 	}
 
 	if result, exists := os.LookupEnv(inputpublishresults); !exists {
-		return errInputPublishResultsNotSet
+		errs.add("", errInputPublishResultsNotSet)
+	} else if result == "" {
+		errs.add("", errInputPublishResultsEmpty)
 	} else {
-		if result == "" {
-			return errInputPublishResultsEmpty
-		}
 		scorecardPublishResults = result
 	}
 
-	return gitHubEventPath()
+	scorecardPRComment = os.Getenv(inputprcomment)
+	scorecardGitHubToken = os.Getenv(inputgithubtoken)
+	scorecardCheckRun = os.Getenv(inputcheckrun)
+	scorecardCommitStatus = os.Getenv(inputcommitstatus)
+	scorecardCommitStatusURL = os.Getenv(inputcommitstatusurl)
+	scorecardCacheDir = os.Getenv(inputcachedir)
+	scorecardSkipUnchanged = os.Getenv(inputskipunchanged)
+	scorecardDryRun = os.Getenv(inputdryrun)
+	if result := os.Getenv(inputfailonscore); result != "" {
+		if threshold, err := parseScoreThreshold(result); errs.check(inputfailonscore, err) {
+			scorecardFailOnScore = threshold
+		}
+	}
+	errs.add(inputscorepolicy, loadScorePolicy())
+	if result := os.Getenv(inputregopolicies); result != "" {
+		scorecardRegoPolicies = splitList(result)
+	}
+	scorecardRegoData = os.Getenv(inputregodata)
+	if result := os.Getenv(inputregoquery); result != "" {
+		scorecardRegoQuery = result
+	}
+	if result := os.Getenv(inputopabin); result != "" {
+		scorecardOPABin = result
+	}
+	if len(scorecardRegoPolicies) > 0 {
+		errs.add(inputopabin, validateOPABin(scorecardOPABin))
+	}
+	errs.add(inputapienvironment, useAPIEnvironment(os.Getenv(inputapienvironment)))
+	scorecardBaselineSource = os.Getenv(inputbaselinesource)
+	errs.add(inputbaselinesource, validateBaselineSource(scorecardBaselineSource))
+	if result := os.Getenv(inputbaselineartifact); result != "" {
+		scorecardBaselineArtifact = result
+	}
+	scorecardFailOnRegression = os.Getenv(inputfailonregression)
+	scorecardSaveHistory = os.Getenv(inputsavehistory)
+	if result := os.Getenv(inputhistorybranch); result != "" {
+		scorecardHistoryBranch = result
+	}
+	scorecardHistoryPath = os.Getenv(inputhistorypath)
+	if scorecardSkipUnchanged == "true" && scorecardCacheDir == "" && scorecardSaveHistory != "true" {
+		errs.add(inputskipunchanged, errSkipNeedsState)
+	}
+	scorecardVersion = os.Getenv(inputscorecardversion)
+	scorecardSHA256 = os.Getenv(inputscorecardsha256)
+	errs.add(inputscorecardsha256, validateScorecardVersion(scorecardVersion, scorecardSHA256))
+	if result := os.Getenv(inputscorecardbin); result != "" {
+		if scorecardVersion != "" {
+			errs.add(inputscorecardbin, errScorecardBinConflict)
+		} else if err := validateScorecardBin(result); err != nil {
+			errs.add(inputscorecardbin, err)
+		} else {
+			scorecardBin = result
+		}
+	}
+	scorecardOpenIssues = os.Getenv(inputopenissues)
+	if result := os.Getenv(inputissuethreshold); result != "" {
+		if threshold, err := parseScoreThreshold(result); errs.check(inputissuethreshold, err) {
+			scorecardIssueThreshold = threshold
+		}
+	}
+	scorecardRemediations = splitList(os.Getenv(inputremediate))
+	errs.add(inputremediate, validateRemediations(scorecardRemediations))
+	if result := os.Getenv(inputjunitthreshold); result != "" {
+		if threshold, err := parseScoreThreshold(result); errs.check(inputjunitthreshold, err) {
+			scorecardJUnitThreshold = threshold
+		}
+	}
+	scorecardSARIFCategory = os.Getenv(inputsarifcategory)
+	if annotations, err := readAnnotations(os.Getenv(githubWorkspace)); errs.check("", err) {
+		scorecardAnnotations = annotations
+	}
+	s, err := loadSuppressions(os.Getenv(inputignorefile), os.Getenv(githubWorkspace))
+	if errs.check(inputignorefile, err) {
+		scorecardSuppressions = s
+	}
+	if path := os.Getenv(inputsariflevelsfile); path != "" {
+		if levels, err := readSARIFLevels(path); errs.check(inputsariflevelsfile, err) {
+			scorecardSARIFLevels = levels
+		}
+	}
+	scorecardBadgeFormat = os.Getenv(inputbadge)
+	errs.add(inputbadge, validateBadgeFormat(scorecardBadgeFormat))
+	scorecardBadgeFile = os.Getenv(inputbadgefile)
+	scorecardBadgeBranch = os.Getenv(inputbadgebranch)
+	scorecardBadgeGist = os.Getenv(inputbadgegist)
+	scorecardLocalPath = os.Getenv(inputlocalpath)
+	scorecardOrganization = os.Getenv(inputorganization)
+	scorecardIncludeRepos = os.Getenv(inputincluderepos)
+	scorecardExcludeRepos = os.Getenv(inputexcluderepos)
+	scorecardReposFile = os.Getenv(inputreposfile)
+	if result := os.Getenv(inputsubpaths); result != "" {
+		scorecardSubPaths = splitList(result)
+	}
+	checks, err := selectChecks(splitList(os.Getenv(inputchecks)), splitList(os.Getenv(inputskipchecks)))
+	if errs.check(inputchecks, err) {
+		scorecardChecks = checks
+	}
+	if result := os.Getenv(inputcustomchecks); result != "" {
+		scorecardCustomChecks = splitList(result)
+	}
+	if result := os.Getenv(inputchecktimeout); result != "" {
+		if timeout, err := parseTimeout(result); errs.check(inputchecktimeout, err) {
+			scorecardCheckTimeouts.timeout = timeout
+		}
+	}
+	if result := os.Getenv(inputtimeout); result != "" {
+		if timeout, err := parseTimeout(result); errs.check(inputtimeout, err) {
+			scorecardTimeout = timeout
+		}
+	}
+	if timeouts, err := parseCheckTimeouts(os.Getenv(inputchecktimeouts)); errs.check(inputchecktimeouts, err) {
+		scorecardCheckTimeouts.perCheck = timeouts
+	}
+	if result := os.Getenv(inputcheckparallelism); result != "" {
+		if parallelism, err := parseParallelism(result); errs.check(inputcheckparallelism, err) {
+			scorecardCheckParallelism = parallelism
+		}
+	}
+	scorecardCheckMetrics = os.Getenv(inputcheckmetrics)
+	scorecardContinueOnCheckError = os.Getenv(inputcontinueoncheckerror)
+	scorecardPreRunCommand = os.Getenv(inputprerun)
+	scorecardPostRunCommand = os.Getenv(inputpostrun)
+	if result := os.Getenv(inputmaxretries); result != "" {
+		if retries, err := parseMaxRetries(result); errs.check(inputmaxretries, err) {
+			scorecardRetryPolicy.maxRetries = retries
+		}
+	}
+	if result := os.Getenv(inputretrybackoff); result != "" {
+		if backoff, err := time.ParseDuration(result); errs.check(inputretrybackoff, err) {
+			scorecardRetryPolicy.backoff = backoff
+		}
+	}
+	if os.Getenv(inputwaitonratelimit) == "true" {
+		scorecardRetryPolicy.maxRateLimitWait = defaultMaxRateLimitWait
+		if result := os.Getenv(inputmaxratelimitwait); result != "" {
+			if wait, err := time.ParseDuration(result); errs.check(inputmaxratelimitwait, err) {
+				scorecardRetryPolicy.maxRateLimitWait = wait
+			}
+		}
+	}
+	if result := os.Getenv(inputappid); result != "" {
+		if id, err := parseGitHubAppID(result); errs.check(inputappid, err) {
+			scorecardAppID = id
+		}
+	}
+	scorecardAppPrivateKey = os.Getenv(inputappprivatekey)
+	if (os.Getenv(inputappid) != "") != (scorecardAppPrivateKey != "") {
+		errs.add(inputappprivatekey, errIncompleteGitHubApp)
+	}
+	if result := os.Getenv(inputappinstallationid); result != "" {
+		if id, err := parseGitHubAppID(result); errs.check(inputappinstallationid, err) {
+			scorecardAppInstallationID = id
+		}
+	}
+	if scorecardOrganization != "" && scorecardReposFile != "" {
+		errs.add(inputreposfile, errConflictingRepoLists)
+	}
+	if result := os.Getenv(inputref); result != "" {
+		if errs.check(inputref, validateRef(os.Getenv(githubEventName))) {
+			scorecardRef = result
+		}
+	}
+	if result := os.Getenv(inputrepository); result != "" {
+		if errs.check(inputrepository, validateRepository(result, os.Getenv(githubEventName))) {
+			scorecardRepository = result
+		}
+	}
+	if result := os.Getenv(inputresultsdir); result != "" {
+		scorecardResultsDir = result
+	}
+	if result := os.Getenv(inputparallelism); result != "" {
+		if parallelism, err := parseParallelism(result); errs.check(inputparallelism, err) {
+			scorecardParallelism = parallelism
+		}
+	}
+
+	scorecardUploadSARIF = os.Getenv(inputuploadsarif)
+	scorecardNewFindingsOnly = os.Getenv(inputnewfindingsonly)
+	// The results outputs are only parsed once both of their inputs are valid.
+	if scorecardResultsFormat != "" && scorecardResultsFile != "" {
+		outputs, err := parseResultsOutputs(scorecardResultsFormat, scorecardResultsFile)
+		if errs.check(inputresultsformat, err) {
+			scorecardResultsOutputs = outputs
+			_, hasSARIF := sarifResultsFile(outputs)
+			if scorecardUploadSARIF == "true" && !hasSARIF {
+				errs.add(inputuploadsarif, errUploadNeedsSARIF)
+			}
+			if scorecardNewFindingsOnly == "true" && !hasSARIF {
+				errs.add(inputnewfindingsonly, errNewFindingsNeedsSARIF)
+			}
+			errs.add(inputresultsformat, validateProbeFormat(outputs, scorecardVersion, os.Getenv(inputscorecardbin) != ""))
+		}
+	}
+
+	if result := os.Getenv(inputcabundle); result != "" {
+		errs.add(inputcabundle, useCABundle(result))
+	}
+	scorecardWebhookSecret = os.Getenv(inputwebhooksecret)
+	scorecardSplunkToken = os.Getenv(inputsplunkhectoken)
+	if result := os.Getenv(inputexporters); result != "" {
+		scorecardExporters = splitList(result)
+		errs.add(inputexporters, validateExporters(scorecardExporters))
+	}
+	scorecardPushgatewayURL = os.Getenv(inputpushgatewayurl)
+	if result := os.Getenv(inputpushgatewayjob); result != "" {
+		scorecardPushgatewayJob = result
+	}
+	scorecardDatadogAPIKey = os.Getenv(inputdatadogapikey)
+	if result := os.Getenv(inputdatadogsite); result != "" {
+		scorecardDatadogSite = strings.TrimSpace(result)
+	}
+	errs.add(inputdatadogsite, validateDatadog())
+	errs.add(inputnotifier, initializeNotifier())
+	if result := os.Getenv(inputnotifyon); result != "" {
+		scorecardNotifyOn = result
+	}
+	errs.add(inputnotifyon, validateNotifyOn(scorecardNotifyOn))
+	scorecardOffline = os.Getenv(inputoffline)
+	errs.add(inputoffline, applyOffline())
+	errs.add("", gitHubEventPath())
+	if scorecardOrganization != "" || scorecardReposFile != "" {
+		for _, input := range unsupportedMultiRepoInputs() {
+			errs.add(input, errMultiRepoUnsupported)
+		}
+	}
+
+	return errs.err()
+}
+
+// loadScorePolicy is a function to load the score policy file, if any.
+// The default policy file is optional, while a policy file set by the user must exist.
+func loadScorePolicy() error {
+	path, exists := os.LookupEnv(inputscorepolicy)
+	if !exists || path == "" {
+		if _, err := os.Stat(defaultScorePolicyFile); err != nil {
+			return nil
+		}
+		path = defaultScorePolicyFile
+	}
+	scorePolicy, err := policy.ReadScorePolicy(path)
+	if err != nil {
+		return err
+	}
+	scorecardScorePolicy = scorePolicy
+	return nil
+}
+
+// splitList is a function to split a comma-separated input into its non-empty, trimmed values.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseResultsOutputs is a function to pair each of the comma-separated results formats with its results file.
+// If a single results file is given for several formats, a file per format is derived from it
+// by replacing its extension, e.g. results.sarif becomes results.sarif and results.json.
+func parseResultsOutputs(formats, files string) ([]resultsOutput, error) {
+	formatList := strings.Split(formats, ",")
+	seen := make(map[string]bool, len(formatList))
+	for i := range formatList {
+		format := strings.TrimSpace(formatList[i])
+		if _, ok := resultsFileExtensions[format]; !ok {
+			return nil, fmt.Errorf("%w: %q", errInvalidResultsFormat, format)
+		}
+		if seen[format] {
+			return nil, fmt.Errorf("%w: %q", errDuplicateResultsFormat, format)
+		}
+		seen[format] = true
+		formatList[i] = format
+	}
+
+	fileList := strings.Split(files, ",")
+	for i := range fileList {
+		fileList[i] = strings.TrimSpace(fileList[i])
+	}
+
+	outputs := make([]resultsOutput, 0, len(formatList))
+	switch {
+	case len(fileList) == len(formatList):
+		for i, format := range formatList {
+			outputs = append(outputs, resultsOutput{format: format, file: fileList[i]})
+		}
+	case len(fileList) == 1:
+		base := strings.TrimSuffix(fileList[0], filepath.Ext(fileList[0]))
+		for _, format := range formatList {
+			outputs = append(outputs, resultsOutput{format: format, file: base + resultsFileExtensions[format]})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d files for %d formats", errResultsFileCountMismatch, len(fileList), len(formatList))
+	}
+	return outputs, nil
 }
 
 // gitHubEventPath is a function to get the path to the GitHub event
@@ -204,6 +1159,10 @@ func gitHubEventPath() error {
 		return fmt.Errorf("error checking if scorecard is a fork: %w", err)
 	}
 
+	if scorecardForkPullRequest, err = isForkPullRequest(os.Getenv(githubEventName), data); err != nil {
+		return err
+	}
+
 	if isFork {
 		if err := os.Setenv(scorecardFork, "true"); err != nil {
 			return fmt.Errorf("error setting %s: %w", scorecardFork, err)
@@ -258,25 +1217,10 @@ func checkIfRequiredENVSet() error {
 // dependency on the github.com/google/go-github/github library
 // which will in turn require other dependencies.
 func getRepositoryInformation(name, githubauthToken string) (repositoryInformation, error) {
-	//nolint
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.github.com/repos/%s", name), nil)
-	if err != nil {
-		return repositoryInformation{}, fmt.Errorf("error creating request: %w", err)
-	}
-	req.Header.Set("Authorization", githubauthToken)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return repositoryInformation{}, fmt.Errorf("error creating request: %w", err)
-	}
-	defer resp.Body.Close()
-	if err != nil {
-		return repositoryInformation{}, fmt.Errorf("error reading response body: %w", err)
-	}
 	var r repositoryInformation
-	err = json.NewDecoder(resp.Body).Decode(&r)
-	if err != nil {
-		return repositoryInformation{}, fmt.Errorf("error decoding response body: %w", err)
+	client := newGitHubClient(githubauthToken)
+	if err := client.do(context.Background(), http.MethodGet, fmt.Sprintf("/repos/%s", name), nil, &r); err != nil {
+		return repositoryInformation{}, fmt.Errorf("error getting the repository information: %w", err)
 	}
 	return r, nil
 }
@@ -313,16 +1257,28 @@ func printEnvVariables(writer io.Writer) {
 
 // validate is a function to validate the scorecard configuration based on the environment variables.
 func validate(writer io.Writer) error {
-	if os.Getenv(githubAuthToken) == "" {
+	// Dry runs validate the inputs before a GitHub App token is minted.
+	if os.Getenv(githubAuthToken) == "" && scorecardAppID == 0 && !offlineLocal() {
 		fmt.Fprintf(writer, "The 'repo_token' variable is empty.\n")
 		if os.Getenv(scorecardFork) == "true" {
 			fmt.Fprintf(writer, "We have detected you are running on a fork.\n")
 		}
 		//nolint:lll
 		fmt.Fprintf(writer,
-			"Please follow the instructions at https://github.com/ossf/scorecard-action#authentication to create the read-only PAT token.\n")
+			"Please follow the instructions at https://github.com/ossf/scorecard-action#authentication to create the read-only PAT token, "+
+				"or authenticate as a GitHub App with app_id and app_private_key.\n")
 		return errEmptyGitHubAuthToken
 	}
+	if (scorecardPRComment == "true" || scorecardCheckRun == "true" || scorecardBaselineSource == baselineSourceArtifact ||
+		scorecardSaveHistory == "true" || scorecardBadgeBranch != "" || scorecardBadgeGist != "" ||
+		scorecardOpenIssues == "true" || len(scorecardRemediations) > 0 || scorecardUploadSARIF == "true" ||
+		scorecardCommitStatus == "true") &&
+		scorecardGitHubToken == "" && scorecardAppID == 0 {
+		fmt.Fprintf(writer, "The 'github_token' variable is required to comment on pull requests, create check runs and commit statuses, "+
+			"download artifacts, save the history, commit the badge, open issues, propose remediations "+
+			"and upload SARIF results.\n")
+		return errEmptyGitHubToken
+	}
 	if strings.Contains(os.Getenv(githubEventName), "pull_request") &&
 		os.Getenv(githubRef) == scorecardDefaultBranch {
 		fmt.Fprintf(writer, "%s not supported with %s event.\n", os.Getenv(githubRef), os.Getenv(githubEventName))
@@ -332,71 +1288,81 @@ func validate(writer io.Writer) error {
 	return nil
 }
 
+// runScorecardSettings is a function to get the scorecard command for a single results format.
+// The results are written to the command's stdout.
+// If localPath is set, scorecard analyzes that folder of the workspace instead of the repository on GitHub.
+// If checks are set, scorecard only runs those checks.
 func runScorecardSettings(githubEventName, scorecardPolicyFile, scorecardResultsFormat, scorecardBin,
-	scorecardResultsFile, githubRepository string) (*exec.Cmd, error) {
+	githubRepository, localPath string, checks []string) (*exec.Cmd, error) {
 	if scorecardBin == "" {
 		return nil, errEmptyScorecardBin
 	}
 	var result exec.Cmd
 	result.Path = scorecardBin
+	result.Args = []string{scorecardBin}
+	switch {
 	// if pull_request
-	if strings.Contains(githubEventName, "pull_request") {
-		// empty policy file
-		if scorecardPolicyFile == "" {
-			result.Args = []string{
-				"--local",
-				".",
-				"--format",
-				scorecardResultsFormat,
-				"--show-details",
-				">",
-				scorecardResultsFile,
-			}
-			return &result, nil
-		}
-		result.Args = []string{
-			"--local",
-			".",
-			"--format",
-			scorecardResultsFormat,
-			"--policy",
-			scorecardPolicyFile,
-			"--show-details",
-			">",
-			scorecardResultsFile,
-		}
-		return &result, nil
-	}
-
-	enabledChecks = ""
-	if githubEventName == "branch_protection_rule" {
-		enabledChecks = "--checks Branch-Protection"
-	}
-
-	if scorecardPolicyFile == "" {
-		result.Args = []string{
-			"--repo",
-			githubRepository,
-			"--format",
-			enabledChecks,
-			scorecardResultsFormat,
-			"--show-details",
-			">",
-			scorecardResultsFile,
-		}
-		return &result, nil
-	}
-	result.Args = []string{
-		"--repo",
-		githubRepository,
-		"--format",
-		enabledChecks,
-		scorecardResultsFormat,
-		"--policy",
-		scorecardPolicyFile,
-		"--show-details",
-		">",
-		scorecardResultsFile,
+	case strings.Contains(githubEventName, "pull_request"):
+		// For pull request events, we run on a local folder.
+		result.Args = append(result.Args, "--local", ".")
+	case localPath != "":
+		// The checkout is analyzed without cloning the repository, which saves API calls.
+		result.Args = append(result.Args, "--local", localPath)
+	default:
+		result.Args = append(result.Args, "--repo", githubRepository)
+		if scorecardCommit != "" {
+			result.Args = append(result.Args, "--commit", scorecardCommit)
+		}
+		// For the branch protection trigger, we only run the Branch-Protection check.
+		if githubEventName == "branch_protection_rule" {
+			checks = []string{"Branch-Protection"}
+		}
+	}
+	if len(checks) > 0 {
+		result.Args = append(result.Args, "--checks", strings.Join(checks, ","))
+	}
+	result.Args = append(result.Args, "--format", scorecardResultsFormat, "--show-details")
+	if scorecardPolicyFile != "" {
+		result.Args = append(result.Args, "--policy", scorecardPolicyFile)
 	}
 	return &result, nil
 }
+
+// runScorecard is a function to run the scorecard command and store its output in the results file,
+// retrying failed runs with exponential backoff. It logs a heartbeat while scorecard runs.
+func runScorecard(cmd *exec.Cmd, resultsFile string) error {
+	start := time.Now()
+	stopHeartbeat := startHeartbeat(func() {
+		fmt.Fprintf(os.Stderr, "Scorecard is still running, %s elapsed.\n", formatElapsed(time.Since(start)))
+	})
+	defer stopHeartbeat()
+	for attempt := 0; ; attempt++ {
+		err := runScorecardOnce(cmd, resultsFile)
+		if err == nil || attempt >= scorecardRetryPolicy.maxRetries {
+			return err
+		}
+		delay := scorecardRetryPolicy.delay(attempt)
+		fmt.Fprintf(os.Stderr, "%v, retrying in %s\n", err, delay)
+		time.Sleep(delay)
+		// A command cannot be started twice.
+		cmd = &exec.Cmd{Path: cmd.Path, Args: cmd.Args, Dir: cmd.Dir, Env: cmd.Env}
+	}
+}
+
+// runScorecardOnce is a function to run the scorecard command once and store its output in the results file.
+func runScorecardOnce(cmd *exec.Cmd, resultsFile string) error {
+	f, err := os.Create(resultsFile)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", resultsFile, err)
+	}
+	defer f.Close()
+
+	cmd.Stdout = f
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running scorecard: %w", err)
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -487,7 +488,6 @@ func Test_runScorecardSettings(t *testing.T) {
 		scorecardPolicyFile    string
 		scorecardResultsFormat string
 		scorecardBin           string
-		scorecardResultsFile   string
 		githubRepository       string
 	}
 	//nolint
@@ -504,7 +504,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				scorecardPolicyFile:    "./testdata/scorecard.yaml",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -529,7 +528,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				scorecardPolicyFile:    "./testdata/scorecard.yaml",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -554,7 +552,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				scorecardPolicyFile:    "./testdata/scorecard.yaml",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -578,7 +575,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				githubEventName:        "pull_request",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -600,7 +596,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				githubEventName:        "pull_request",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -621,7 +616,6 @@ func Test_runScorecardSettings(t *testing.T) {
 			args: args{
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -643,7 +637,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				githubEventName:        "branch_protection_rule",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -666,7 +659,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				githubEventName:        "branch_protection_rule",
 				scorecardResultsFormat: "json",
 				scorecardBin:           "scorecard",
-				scorecardResultsFile:   "./testdata/scorecard.json",
 				githubRepository:       "foo/bar",
 			},
 			want: &exec.Cmd{
@@ -690,7 +682,6 @@ func Test_runScorecardSettings(t *testing.T) {
 				githubEventName:        "",
 				scorecardResultsFormat: "",
 				scorecardBin:           "",
-				scorecardResultsFile:   "",
 				githubRepository:       "",
 			},
 			wantErr: true,
@@ -702,7 +693,7 @@ func Test_runScorecardSettings(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			got, err := runScorecardSettings(tt.args.githubEventName, tt.args.scorecardPolicyFile,
-				tt.args.scorecardResultsFormat, tt.args.scorecardBin, tt.args.scorecardResultsFile, tt.args.githubRepository)
+				tt.args.scorecardResultsFormat, tt.args.scorecardBin, tt.args.githubRepository, "", nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("runScorecardSettings() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -713,3 +704,188 @@ func Test_runScorecardSettings(t *testing.T) {
 		})
 	}
 }
+
+func Test_runScorecardSettings_args(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name            string
+		githubEventName string
+		localPath       string
+		checks          []string
+		want            []string
+	}{
+		{
+			name:            "Remote repository",
+			githubEventName: "push",
+			want:            []string{"scorecard", "--repo", "foo/bar", "--format", "json", "--show-details"},
+		},
+		{
+			name:            "Local path",
+			githubEventName: "push",
+			localPath:       "src",
+			want:            []string{"scorecard", "--local", "src", "--format", "json", "--show-details"},
+		},
+		{
+			name:            "Pull request always analyzes the checkout",
+			githubEventName: "pull_request",
+			localPath:       "src",
+			want:            []string{"scorecard", "--local", ".", "--format", "json", "--show-details"},
+		},
+		{
+			name:            "Selected checks",
+			githubEventName: "push",
+			checks:          []string{"SAST", "Fuzzing"},
+			want: []string{
+				"scorecard", "--repo", "foo/bar", "--checks", "SAST,Fuzzing", "--format", "json", "--show-details",
+			},
+		},
+		{
+			name:            "Branch protection trigger overrides the selected checks",
+			githubEventName: "branch_protection_rule",
+			checks:          []string{"SAST"},
+			want: []string{
+				"scorecard", "--repo", "foo/bar", "--checks", "Branch-Protection", "--format", "json", "--show-details",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := runScorecardSettings(tt.githubEventName, "", "json", "scorecard", "foo/bar", tt.localPath,
+				tt.checks)
+			if err != nil {
+				t.Fatalf("runScorecardSettings() error = %v", err)
+			}
+			if !cmp.Equal(got.Args, tt.want) {
+				t.Errorf("runScorecardSettings() = %v, want %v", got.Args, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseResultsOutputs(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		formats string
+		files   string
+		want    []resultsOutput
+		wantErr bool
+	}{
+		{
+			name:    "Success - single format",
+			formats: "sarif",
+			files:   "results.sarif",
+			want:    []resultsOutput{{format: "sarif", file: "results.sarif"}},
+		},
+		{
+			name:    "Success - single format keeps file name",
+			formats: "json",
+			files:   "results.out",
+			want:    []resultsOutput{{format: "json", file: "results.out"}},
+		},
+		{
+			name:    "Success - multiple formats derived from one file",
+			formats: "sarif, json,default",
+			files:   "out/results.sarif",
+			want: []resultsOutput{
+				{format: "sarif", file: "out/results.sarif"},
+				{format: "json", file: "out/results.json"},
+				{format: "default", file: "out/results.txt"},
+			},
+		},
+		{
+			name:    "Success - one file per format",
+			formats: "sarif,json",
+			files:   "a.sarif, b.json",
+			want: []resultsOutput{
+				{format: "sarif", file: "a.sarif"},
+				{format: "json", file: "b.json"},
+			},
+		},
+		{
+			name:    "Failure - invalid format",
+			formats: "sarif,xml",
+			files:   "results.sarif",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - empty format",
+			formats: "sarif,",
+			files:   "results.sarif",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - duplicate format",
+			formats: "json,json",
+			files:   "results.json",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - files do not match formats",
+			formats: "sarif,json,default",
+			files:   "a.sarif,b.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseResultsOutputs(tt.formats, tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseResultsOutputs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !cmp.Equal(got, tt.want, cmp.AllowUnexported(resultsOutput{})) {
+				t.Errorf("parseResultsOutputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitList(t *testing.T) {
+	t.Parallel()
+	want := []string{"a.rego", "b.rego"}
+	if got := splitList(" a.rego, ,b.rego,"); !cmp.Equal(got, want) {
+		t.Errorf("splitList() = %v, want %v", got, want)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList() = %v, want nil", got)
+	}
+}
+
+//not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_stepSummary(t *testing.T) {
+	defer func(outputs []resultsOutput) { scorecardResultsOutputs = outputs }(scorecardResultsOutputs)
+	summaryFile := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(githubStepSummary, summaryFile)
+
+	// The step summary does not run scorecard a second time for JSON results nothing else needs.
+	scorecardResultsOutputs = []resultsOutput{{format: sarif, file: "results.sarif"}}
+	if features := enabledFeatures("push"); features.needJSON() {
+		t.Errorf("enabledFeatures() = %+v, want sarif-only runs not to need the JSON results", features)
+	}
+	if err := stepSummary(summaryFile); err != nil {
+		t.Fatalf("stepSummary() error = %v", err)
+	}
+	if _, err := os.Stat(summaryFile); !os.IsNotExist(err) {
+		t.Errorf("stepSummary() wrote the summary of a sarif-only run, err = %v", err)
+	}
+
+	scorecardResultsOutputs = []resultsOutput{{format: "json", file: "./testdata/results.json"}}
+	if err := stepSummary(summaryFile); err != nil {
+		t.Fatalf("stepSummary() error = %v", err)
+	}
+	data, err := ioutil.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("Aggregate score")) {
+		t.Errorf("stepSummary() wrote %q, want the scores", data)
+	}
+}
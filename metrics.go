@@ -0,0 +1,172 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPushgatewayJob = "scorecard"
+	// unknownAPICalls is the API calls count of runs whose quota could not be compared.
+	unknownAPICalls = -1
+)
+
+var errPushgatewayFailed = errors.New("Pushgateway rejected the metrics")
+
+// metricsRecorder measures a run, to push its metrics to a Prometheus Pushgateway once the results are known.
+type metricsRecorder struct {
+	start    time.Time
+	client   *githubClient
+	quota    rateLimit
+	hasQuota bool
+}
+
+// runMetrics are the metrics of a run.
+type runMetrics struct {
+	result   *scorecardResult
+	duration time.Duration
+	// apiCalls is the GitHub API quota the run used, including the requests of scorecard,
+	// or unknownAPICalls.
+	apiCalls int
+}
+
+// startMetrics is a function to start measuring the run. The client is nil when the run makes no
+// GitHub API request, e.g. offline, and the API calls are then not reported.
+func startMetrics(ctx context.Context, client *githubClient) *metricsRecorder {
+	r := &metricsRecorder{start: time.Now(), client: client}
+	if client != nil {
+		quota, err := fetchRateLimit(ctx, client)
+		r.quota, r.hasQuota = quota, err == nil
+	}
+	return r
+}
+
+// push is a function to push the metrics of the run to the Pushgateway of the inputs.
+func (r *metricsRecorder) push(ctx context.Context, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	metrics := runMetrics{result: result, duration: time.Since(r.start), apiCalls: unknownAPICalls}
+	if r.hasQuota {
+		if quota, err := fetchRateLimit(ctx, r.client); err == nil {
+			metrics.apiCalls = apiCallsUsed(r.quota, quota)
+		}
+	}
+	if err := pushMetrics(ctx, scorecardHTTPClient, scorecardPushgatewayURL, scorecardPushgatewayJob,
+		os.Getenv(githubRepository), metrics); err != nil {
+		return err
+	}
+	fmt.Println("Pushed the metrics to the Pushgateway.")
+	return nil
+}
+
+// fetchRateLimit is a function to get the core GitHub API quota of the token.
+// Requesting it does not count against the quota.
+func fetchRateLimit(ctx context.Context, client *githubClient) (rateLimit, error) {
+	var body struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := client.do(ctx, http.MethodGet, "/rate_limit", nil, &body); err != nil {
+		return rateLimit{}, err
+	}
+	core := body.Resources.Core
+	return rateLimit{reset: time.Unix(core.Reset, 0), limit: core.Limit, remaining: core.Remaining}, nil
+}
+
+// apiCallsUsed is a function to compute the quota used between two snapshots of the rate limit.
+// It is unknown when the quota was reset in between.
+func apiCallsUsed(start, end rateLimit) int {
+	if !start.reset.Equal(end.reset) || end.remaining > start.remaining {
+		return unknownAPICalls
+	}
+	return start.remaining - end.remaining
+}
+
+// writeMetrics is a function to write the metrics in the Prometheus text format.
+// Inconclusive checks have no score, so they are left out rather than reported as -1.
+func writeMetrics(writer io.Writer, metrics runMetrics) {
+	fmt.Fprintf(writer, "# HELP scorecard_score Aggregate scorecard score of the repository.\n")
+	fmt.Fprintf(writer, "# TYPE scorecard_score gauge\n")
+	fmt.Fprintf(writer, "scorecard_score %g\n", metrics.result.Score)
+	fmt.Fprintf(writer, "# HELP scorecard_check_score Score of a scorecard check.\n")
+	fmt.Fprintf(writer, "# TYPE scorecard_check_score gauge\n")
+	for i := range metrics.result.Checks {
+		check := &metrics.result.Checks[i]
+		if check.Score == inconclusiveScore {
+			continue
+		}
+		fmt.Fprintf(writer, "scorecard_check_score{check=\"%s\"} %d\n", escapeLabelValue(check.Name), check.Score)
+	}
+	fmt.Fprintf(writer, "# HELP scorecard_run_duration_seconds Duration of the scorecard action run.\n")
+	fmt.Fprintf(writer, "# TYPE scorecard_run_duration_seconds gauge\n")
+	fmt.Fprintf(writer, "scorecard_run_duration_seconds %g\n", metrics.duration.Seconds())
+	if metrics.apiCalls != unknownAPICalls {
+		fmt.Fprintf(writer, "# HELP scorecard_github_api_calls GitHub API quota used by the run.\n")
+		fmt.Fprintf(writer, "# TYPE scorecard_github_api_calls gauge\n")
+		fmt.Fprintf(writer, "scorecard_github_api_calls %d\n", metrics.apiCalls)
+	}
+}
+
+// escapeLabelValue is a function to escape a label value of the Prometheus text format.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// pushgatewayURL is a function to get the URL of the metrics group of the repository. The repository
+// is base64 encoded, as the Pushgateway requires for grouping key values containing a slash.
+func pushgatewayURL(baseURL, job, repository string) string {
+	return fmt.Sprintf("%s/metrics/job/%s/repository@base64/%s", strings.TrimSuffix(baseURL, "/"), job,
+		base64.RawURLEncoding.EncodeToString([]byte(repository)))
+}
+
+// pushMetrics is a function to replace the metrics of the repository's group in the Pushgateway.
+func pushMetrics(ctx context.Context, httpClient *http.Client, baseURL, job, repository string,
+	metrics runMetrics) error {
+	var body bytes.Buffer
+	writeMetrics(&body, metrics)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, pushgatewayURL(baseURL, job, repository), &body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error pushing the metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+		return fmt.Errorf("%w: %d %s", errPushgatewayFailed, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
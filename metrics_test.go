@@ -0,0 +1,121 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_apiCallsUsed(t *testing.T) {
+	t.Parallel()
+	reset := time.Unix(1650000000, 0)
+	//nolint
+	tests := []struct {
+		name  string
+		start rateLimit
+		end   rateLimit
+		want  int
+	}{
+		{
+			name:  "Same window",
+			start: rateLimit{reset: reset, limit: 5000, remaining: 4900},
+			end:   rateLimit{reset: reset, limit: 5000, remaining: 4650},
+			want:  250,
+		},
+		{
+			name:  "Reset in between",
+			start: rateLimit{reset: reset, limit: 5000, remaining: 100},
+			end:   rateLimit{reset: reset.Add(time.Hour), limit: 5000, remaining: 4900},
+			want:  unknownAPICalls,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := apiCallsUsed(tt.start, tt.end); got != tt.want {
+				t.Errorf("apiCallsUsed() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeMetrics(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	var buf bytes.Buffer
+	writeMetrics(&buf, runMetrics{result: result, duration: 90 * time.Second, apiCalls: 250})
+	got := buf.String()
+	for _, want := range []string{
+		"scorecard_score 6.8\n",
+		"scorecard_check_score{check=\"Binary-Artifacts\"} 10\n",
+		"scorecard_check_score{check=\"Branch-Protection\"} 3\n",
+		"scorecard_run_duration_seconds 90\n",
+		"scorecard_github_api_calls 250\n",
+	} {
+		if !bytes.Contains([]byte(got), []byte(want)) {
+			t.Errorf("writeMetrics() = %q, want it to contain %q", got, want)
+		}
+	}
+	if bytes.Contains([]byte(got), []byte("CII-Best-Practices")) {
+		t.Errorf("writeMetrics() reported the inconclusive check: %q", got)
+	}
+
+	buf.Reset()
+	writeMetrics(&buf, runMetrics{result: result, apiCalls: unknownAPICalls})
+	if bytes.Contains(buf.Bytes(), []byte("scorecard_github_api_calls")) {
+		t.Errorf("writeMetrics() reported unknown API calls: %q", buf.String())
+	}
+}
+
+func Test_pushMetrics(t *testing.T) {
+	t.Parallel()
+	var pushed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// ossf/scorecard-action, base64 encoded.
+		if r.Method != http.MethodPut || r.URL.Path != "/metrics/job/scorecard/repository@base64/b3NzZi9zY29yZWNhcmQtYWN0aW9u" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		pushed = string(body)
+	}))
+	defer server.Close()
+	result := &scorecardResult{Score: 7.5}
+
+	err := pushMetrics(context.Background(), server.Client(), server.URL+"/", "scorecard", "ossf/scorecard-action",
+		runMetrics{result: result, apiCalls: unknownAPICalls})
+	if err != nil {
+		t.Fatalf("pushMetrics() error = %v", err)
+	}
+	if !bytes.Contains([]byte(pushed), []byte("scorecard_score 7.5\n")) {
+		t.Errorf("pushMetrics() pushed %q", pushed)
+	}
+
+	err = pushMetrics(context.Background(), server.Client(), server.URL, "other", "ossf/scorecard-action",
+		runMetrics{result: result, apiCalls: unknownAPICalls})
+	if !errors.Is(err, errPushgatewayFailed) {
+		t.Errorf("pushMetrics() error = %v, want %v", err, errPushgatewayFailed)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// subPathScore is the aggregate score of a sub-project of a monorepo.
+type subPathScore struct {
+	path  string
+	score float64
+}
+
+// subPathResultsFile is a function to namespace a results file with a sub-path,
+// e.g. results.sarif becomes results.services_a.sarif for services/a.
+func subPathResultsFile(file, subPath string) string {
+	namespace := strings.ReplaceAll(strings.Trim(filepath.ToSlash(filepath.Clean(subPath)), "/"), "/", "_")
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + "." + namespace + ext
+}
+
+// scanSubPaths is a function to run scorecard on each sub-path of the checkout, in every requested format,
+// and report the aggregate score of each sub-path. The outputs must include JSON results.
+func scanSubPaths(writer io.Writer, outputs []resultsOutput, subPaths []string) error {
+	jsonFile, _ := jsonResultsFile(outputs)
+	scores := make([]subPathScore, 0, len(subPaths))
+	for _, subPath := range subPaths {
+		for _, output := range outputs {
+			if _, ok := resultsRenderers[output.format]; ok {
+				continue
+			}
+			policyFile := ""
+			if output.format == sarif {
+				policyFile = scorecardPolicyFile
+			}
+			cmd, err := runScorecardSettings("", policyFile, output.format, scorecardBin, os.Getenv(githubRepository),
+				subPath, scorecardChecks)
+			if err != nil {
+				return err
+			}
+			cmd.Dir = os.Getenv(githubWorkspace)
+			if err := runScorecard(cmd, subPathResultsFile(output.file, subPath)); err != nil {
+				return fmt.Errorf("error scoring %s: %w", subPath, err)
+			}
+		}
+		result, err := readScorecardResult(subPathResultsFile(jsonFile, subPath))
+		if err != nil {
+			return err
+		}
+		for _, output := range outputs {
+			var err error
+			if render, ok := resultsRenderers[output.format]; ok {
+				err = writeRenderedResults(subPathResultsFile(output.file, subPath), render, []*scorecardResult{result})
+			} else if output.format == sarif {
+				// Each sub-path has its own category, so that its alerts do not replace those of the repository.
+				err = enhanceSARIF(subPathResultsFile(output.file, subPath), sarifCategory(subPath))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		scores = append(scores, subPathScore{path: subPath, score: result.Score})
+	}
+
+	writeSubPathScores(writer, scores)
+	if summaryFile := os.Getenv(githubStepSummary); summaryFile != "" {
+		//nolint:gosec
+		f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", summaryFile, err)
+		}
+		defer f.Close()
+		writeSubPathScores(f, scores)
+	}
+	return nil
+}
+
+// writeSubPathScores is a function to render the aggregate score of each sub-path as a markdown table.
+func writeSubPathScores(writer io.Writer, scores []subPathScore) {
+	fmt.Fprintf(writer, "## Scorecard results per path\n\n")
+	fmt.Fprintf(writer, "| Path | Score |\n")
+	fmt.Fprintf(writer, "| ---- | ----- |\n")
+	for _, s := range scores {
+		fmt.Fprintf(writer, "| %s | %.1f |\n", markdownCell(s.path), s.score)
+	}
+	fmt.Fprintln(writer)
+}
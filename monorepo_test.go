@@ -0,0 +1,74 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_subPathResultsFile(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		file    string
+		subPath string
+		want    string
+	}{
+		{
+			name:    "Nested path",
+			file:    "results.sarif",
+			subPath: "services/a",
+			want:    "results.services_a.sarif",
+		},
+		{
+			name:    "Trailing slash",
+			file:    "out/results.json",
+			subPath: "./services/b/",
+			want:    "out/results.services_b.json",
+		},
+		{
+			name:    "No extension",
+			file:    "results",
+			subPath: "lib",
+			want:    "results.lib",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := subPathResultsFile(tt.file, tt.subPath); got != tt.want {
+				t.Errorf("subPathResultsFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeSubPathScores(t *testing.T) {
+	t.Parallel()
+	var got bytes.Buffer
+	writeSubPathScores(&got, []subPathScore{{path: "services/a", score: 7.25}, {path: "services/b", score: 4}})
+	want := "## Scorecard results per path\n\n" +
+		"| Path | Score |\n" +
+		"| ---- | ----- |\n" +
+		"| services/a | 7.2 |\n" +
+		"| services/b | 4.0 |\n\n"
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("writeSubPathScores() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,438 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultResultsDir  = "scorecard-results"
+	defaultParallelism = 4
+	mergedReportFile   = "report.json"
+)
+
+var (
+	errRepositoryScanFailed = errors.New("scorecard failed on some repositories")
+	errInvalidParallelism   = errors.New("parallelism must be a positive integer")
+	errConflictingRepoLists = errors.New("organization and repos_file cannot be used together")
+	errMultiRepoUnsupported = errors.New("not supported with organization or repos_file")
+)
+
+// repositoryScan is the outcome of running scorecard on one of several repositories.
+type repositoryScan struct {
+	result      *scorecardResult
+	Repository  string  `json:"repository"`
+	ResultsFile string  `json:"results_file,omitempty"`
+	SARIFFile   string  `json:"sarif_file,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Score       float64 `json:"score"`
+}
+
+// multiRepoUnsupportedFeatures are the features of a single repository run that the multi-repository
+// modes do not run, so that setting them fails the inputs instead of being silently ignored.
+//nolint
+var multiRepoUnsupportedFeatures = []struct {
+	input   string
+	enabled func() bool
+}{
+	{inputresultsformat, func() bool { return hasResultsFormat(scorecardResultsOutputs, probeFormat) }},
+	{inputfailonscore, func() bool { return scorecardFailOnScore != noScoreThreshold }},
+	{inputscorepolicy, func() bool { return os.Getenv(inputscorepolicy) != "" }},
+	{inputregopolicies, func() bool { return len(scorecardRegoPolicies) > 0 }},
+	{inputexporters, func() bool { return len(scorecardExporters) > 0 }},
+	{inputpushgatewayurl, func() bool { return scorecardPushgatewayURL != "" }},
+	{inputdatadogapikey, func() bool { return scorecardDatadogAPIKey != "" }},
+	{inputnotifier, func() bool { return scorecardNotifyURL != "" }},
+}
+
+// unsupportedMultiRepoInputs is a function to get the inputs set for features the multi-repository modes
+// do not run.
+func unsupportedMultiRepoInputs() []string {
+	var inputs []string
+	for _, feature := range multiRepoUnsupportedFeatures {
+		if feature.enabled() {
+			inputs = append(inputs, feature.input)
+		}
+	}
+	return inputs
+}
+
+// parseParallelism is a function to parse the number of repositories scanned concurrently.
+func parseParallelism(value string) (int, error) {
+	parallelism, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parallelism < 1 {
+		return 0, fmt.Errorf("%w: %q", errInvalidParallelism, value)
+	}
+	return parallelism, nil
+}
+
+// scanOrganization is a function to run scorecard on the repositories of the organization
+// that match the include and exclude patterns.
+func scanOrganization(ctx context.Context) error {
+	client := newGitHubClient(os.Getenv(githubAuthToken))
+	repos, err := listOrganizationRepositories(ctx, client, scorecardOrganization)
+	if err != nil {
+		return err
+	}
+	repos, err = filterRepositories(repos, splitList(scorecardIncludeRepos), splitList(scorecardExcludeRepos))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scanning %d repositories of %s.\n", len(repos), scorecardOrganization)
+	return scanRepositories(repos)
+}
+
+// scanReposFile is a function to run scorecard on the repositories listed in the repos file.
+func scanReposFile() error {
+	data, err := ioutil.ReadFile(scorecardReposFile)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", scorecardReposFile, err)
+	}
+	repos, err := parseReposFile(scorecardReposFile, data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scanning %d repositories listed in %s.\n", len(repos), scorecardReposFile)
+	return scanRepositories(repos)
+}
+
+// parseReposFile is a function to parse a list of repositories, either one per line or,
+// for .yml and .yaml files, as a YAML list, optionally under a repositories key.
+// Repositories can be written as owner/repo or as github.com URLs.
+func parseReposFile(name string, data []byte) ([]string, error) {
+	var entries []string
+	switch filepath.Ext(name) {
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(data, &entries); err != nil {
+			var list struct {
+				Repositories []string `yaml:"repositories"`
+			}
+			if err := yaml.Unmarshal(data, &list); err != nil {
+				return nil, fmt.Errorf("error unmarshalling %s: %w", name, err)
+			}
+			entries = list.Repositories
+		}
+	default:
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				entries = append(entries, line)
+			}
+		}
+	}
+
+	repos := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		repo := strings.TrimSpace(entry)
+		repo = strings.TrimPrefix(repo, "https://")
+		repo = strings.TrimPrefix(repo, "github.com/")
+		repo = strings.TrimSuffix(repo, "/")
+		if !isRepositoryName(repo) {
+			return nil, fmt.Errorf("%w: %q in %s", errInvalidRepository, entry, name)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// listOrganizationRepositories is a function to list the full names of the organization's repositories,
+// leaving out archived and disabled repositories.
+func listOrganizationRepositories(ctx context.Context, client *githubClient, org string) ([]string, error) {
+	var repos []string
+	for page := 1; ; page++ {
+		var list []struct {
+			FullName string `json:"full_name"`
+			Archived bool   `json:"archived"`
+			Disabled bool   `json:"disabled"`
+		}
+		p := fmt.Sprintf("/orgs/%s/repos?type=all&per_page=%d&page=%d", org, githubPageSize, page)
+		if err := client.do(ctx, http.MethodGet, p, nil, &list); err != nil {
+			return nil, err
+		}
+		for _, repo := range list {
+			if !repo.Archived && !repo.Disabled {
+				repos = append(repos, repo.FullName)
+			}
+		}
+		if len(list) < githubPageSize {
+			return repos, nil
+		}
+	}
+}
+
+// filterRepositories is a function to keep the repositories whose name matches one of the include
+// patterns, if any, and none of the exclude patterns. Patterns are matched against the repository name
+// without its owner, using path.Match syntax.
+func filterRepositories(repos, include, exclude []string) ([]string, error) {
+	var filtered []string
+	for _, repo := range repos {
+		name := path.Base(repo)
+		included := len(include) == 0
+		for _, pattern := range include {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("error matching %q: %w", pattern, err)
+			}
+			included = included || matched
+		}
+		for _, pattern := range exclude {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("error matching %q: %w", pattern, err)
+			}
+			included = included && !matched
+		}
+		if included {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered, nil
+}
+
+// scanRepositories is a function to run scorecard on each repository, writing the results files per
+// repository and a merged report to the results directory, then the merged results to the results files.
+func scanRepositories(repos []string) error {
+	if err := os.MkdirAll(scorecardResultsDir, 0o755); err != nil {
+		return fmt.Errorf("error creating %s: %w", scorecardResultsDir, err)
+	}
+	_, withSARIF := sarifResultsFile(scorecardResultsOutputs)
+	scans := runRepositoryScans(scorecardBin, scorecardResultsDir, repos, scorecardParallelism, withSARIF)
+
+	report, err := json.MarshalIndent(struct {
+		Repositories []repositoryScan `json:"repositories"`
+	}{Repositories: scans}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the report: %w", err)
+	}
+	reportFile := filepath.Join(scorecardResultsDir, mergedReportFile)
+	if err := ioutil.WriteFile(reportFile, report, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", reportFile, err)
+	}
+
+	if err := writeMergedResults(scans, scorecardResultsOutputs); err != nil {
+		return err
+	}
+
+	writeRepositoryScans(os.Stdout, scans)
+	if summaryFile := os.Getenv(githubStepSummary); summaryFile != "" {
+		//nolint:gosec
+		f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", summaryFile, err)
+		}
+		defer f.Close()
+		writeRepositoryScans(f, scans)
+	}
+
+	failed := 0
+	for i := range scans {
+		if scans[i].Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d repositories", errRepositoryScanFailed, failed, len(scans))
+	}
+	return nil
+}
+
+// runRepositoryScans is a function to run scorecard on the repositories with a pool of workers.
+// The scans are returned in the order of the repositories.
+func runRepositoryScans(bin, dir string, repos []string, workers int, withSARIF bool) []repositoryScan {
+	scans := make([]repositoryScan, len(repos))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				scans[i] = runRepositoryScan(bin, dir, repos[i], withSARIF)
+			}
+		}()
+	}
+	for i := range repos {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return scans
+}
+
+// runRepositoryScan is a function to run scorecard on a single repository, into a JSON results file
+// named after the repository, and a SARIF results file if requested.
+func runRepositoryScan(bin, dir, repo string, withSARIF bool) repositoryScan {
+	name := filepath.Join(dir, strings.ReplaceAll(repo, "/", "_"))
+	scan := repositoryScan{
+		Repository:  repo,
+		ResultsFile: name + ".json",
+	}
+	cmd, err := runScorecardSettings("", "", "json", bin, repo, "", scorecardChecks)
+	if err == nil {
+		err = runScorecard(cmd, scan.ResultsFile)
+	}
+	if err == nil {
+		scan.result, err = readScorecardResult(scan.ResultsFile)
+	}
+	if err == nil && withSARIF {
+		scan.SARIFFile = name + ".sarif"
+		if cmd, err = runScorecardSettings("", scorecardPolicyFile, sarif, bin, repo, "", scorecardChecks); err == nil {
+			err = runScorecard(cmd, scan.SARIFFile)
+		}
+	}
+	if err != nil {
+		scan.Error = err.Error()
+		return scan
+	}
+	scan.Score = scan.result.Score
+	return scan
+}
+
+// writeMergedResults is a function to write the results of every successfully scanned repository
+// to the requested results files: a JSON array of the results, a SARIF log with a run per repository,
+// the table of scores for the default format, or a format the action renders from all the results.
+func writeMergedResults(scans []repositoryScan, outputs []resultsOutput) error {
+	for _, output := range outputs {
+		if render, ok := resultsRenderers[output.format]; ok {
+			if err := writeRenderedResults(output.file, render, scannedResults(scans)); err != nil {
+				return err
+			}
+			continue
+		}
+		var data []byte
+		var err error
+		switch output.format {
+		case "json":
+			data, err = mergeJSONResults(scans)
+		case sarif:
+			data, err = mergeSARIFResults(scans)
+		default:
+			var table bytes.Buffer
+			writeRepositoryScans(&table, scans)
+			data = table.Bytes()
+		}
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(output.file, data, 0o600); err != nil {
+			return fmt.Errorf("error writing %s: %w", output.file, err)
+		}
+	}
+	return nil
+}
+
+// scannedResults is a function to get the results of the successfully scanned repositories.
+func scannedResults(scans []repositoryScan) []*scorecardResult {
+	results := make([]*scorecardResult, 0, len(scans))
+	for i := range scans {
+		if scans[i].Error == "" {
+			results = append(results, scans[i].result)
+		}
+	}
+	return results
+}
+
+// mergeJSONResults is a function to merge the JSON results of the repositories into a JSON array.
+func mergeJSONResults(scans []repositoryScan) ([]byte, error) {
+	results := make([]json.RawMessage, 0, len(scans))
+	for i := range scans {
+		if scans[i].Error != "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(scans[i].ResultsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", scans[i].ResultsFile, err)
+		}
+		results = append(results, data)
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling merged results: %w", err)
+	}
+	return data, nil
+}
+
+// mergeSARIFResults is a function to merge the SARIF results of the repositories into a single SARIF log,
+// identifying the run of each repository by its automation details.
+func mergeSARIFResults(scans []repositoryScan) ([]byte, error) {
+	merged := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+	}
+	runs := []map[string]interface{}{}
+	for i := range scans {
+		if scans[i].Error != "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(scans[i].SARIFFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", scans[i].SARIFFile, err)
+		}
+		var log struct {
+			Schema  string                   `json:"$schema"`
+			Version string                   `json:"version"`
+			Runs    []map[string]interface{} `json:"runs"`
+		}
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s: %w", scans[i].SARIFFile, err)
+		}
+		if log.Schema != "" {
+			merged["$schema"] = log.Schema
+		}
+		for _, run := range log.Runs {
+			run["automationDetails"] = map[string]string{"id": sarifAutomationID(sarifCategory(scans[i].Repository))}
+			addSARIFFingerprints(run)
+			if scorecardSARIFLevels != nil {
+				applySARIFLevels(run, scorecardSARIFLevels)
+			}
+			runs = append(runs, run)
+		}
+	}
+	merged["runs"] = runs
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling merged SARIF results: %w", err)
+	}
+	return data, nil
+}
+
+// writeRepositoryScans is a function to render the aggregate score of each repository as a markdown table.
+func writeRepositoryScans(writer io.Writer, scans []repositoryScan) {
+	fmt.Fprintf(writer, "## Scorecard results\n\n")
+	fmt.Fprintf(writer, "| Repository | Score |\n")
+	fmt.Fprintf(writer, "| ---------- | ----- |\n")
+	for i := range scans {
+		score := fmt.Sprintf("%.1f", scans[i].Score)
+		if scans[i].Error != "" {
+			score = "error: " + markdownCell(scans[i].Error)
+		}
+		fmt.Fprintf(writer, "| %s | %s |\n", scans[i].Repository, score)
+	}
+	fmt.Fprintln(writer)
+}
@@ -0,0 +1,314 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeScorecard scores every repository 7.5, except repositories named broken.
+const fakeScorecard = `#!/bin/sh
+case "$2:$4" in
+  */broken:*) echo 'failed' >&2; exit 1 ;;
+  *:sarif) echo '{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"Scorecard"}},"results":[]}]}' ;;
+  *) echo "{\"repo\":{\"name\":\"github.com/$2\"},\"score\":7.5}" ;;
+esac
+`
+
+func Test_parseParallelism(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "Success",
+			value: " 8 ",
+			want:  8,
+		},
+		{
+			name:    "Failure - zero",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - not a number",
+			value:   "many",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseParallelism(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseParallelism() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseParallelism() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterRepositories(t *testing.T) {
+	t.Parallel()
+	repos := []string{"org/service-a", "org/service-b", "org/website", "org/service-legacy"}
+	//nolint
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "No patterns",
+			want: repos,
+		},
+		{
+			name:    "Include and exclude",
+			include: []string{"service-*"},
+			exclude: []string{"*-legacy"},
+			want:    []string{"org/service-a", "org/service-b"},
+		},
+		{
+			name:    "Exclude only",
+			exclude: []string{"website"},
+			want:    []string{"org/service-a", "org/service-b", "org/service-legacy"},
+		},
+		{
+			name:    "Invalid pattern",
+			include: []string{"["},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := filterRepositories(repos, tt.include, tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterRepositories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("filterRepositories() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_listOrganizationRepositories(t *testing.T) {
+	t.Parallel()
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/org/repos" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		// The first page is full, the second one is the last.
+		count := githubPageSize
+		if r.URL.Query().Get("page") == "2" {
+			count = 2
+		}
+		var repos []string
+		for i := 0; i < count; i++ {
+			repos = append(repos, fmt.Sprintf(`{"full_name": "org/repo-%s-%d", "archived": %t}`,
+				r.URL.Query().Get("page"), i, i == 0))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(repos, ","))
+	}))
+
+	got, err := listOrganizationRepositories(context.Background(), client, "org")
+	if err != nil {
+		t.Fatalf("listOrganizationRepositories() error = %v", err)
+	}
+	// Archived repositories are left out of both pages.
+	if len(got) != githubPageSize || got[0] != "org/repo-1-1" || got[len(got)-1] != "org/repo-2-1" {
+		t.Errorf("listOrganizationRepositories() = %v", got)
+	}
+}
+
+//not setting t.Parallel() here because we are mutating the retry policy
+//nolint
+func Test_runRepositoryScans(t *testing.T) {
+	defer func(policy retryPolicy) { scorecardRetryPolicy = policy }(scorecardRetryPolicy)
+	scorecardRetryPolicy = retryPolicy{}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "scorecard")
+	//nolint:gosec
+	if err := ioutil.WriteFile(bin, []byte(fakeScorecard), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", bin, err)
+	}
+
+	scans := runRepositoryScans(bin, dir, []string{"org/a", "org/broken", "org/b"}, 2, true)
+	if len(scans) != 3 {
+		t.Fatalf("runRepositoryScans() = %v", scans)
+	}
+	for i, repo := range []string{"org/a", "org/broken", "org/b"} {
+		if scans[i].Repository != repo {
+			t.Errorf("runRepositoryScans()[%d] = %v, want %v", i, scans[i].Repository, repo)
+		}
+	}
+	if scans[0].Score != 7.5 || scans[0].Error != "" || scans[0].ResultsFile != filepath.Join(dir, "org_a.json") ||
+		scans[0].SARIFFile != filepath.Join(dir, "org_a.sarif") {
+		t.Errorf("runRepositoryScans()[0] = %+v", scans[0])
+	}
+	if scans[1].Error == "" {
+		t.Errorf("runRepositoryScans()[1] = %+v, want an error", scans[1])
+	}
+
+	var got bytes.Buffer
+	writeRepositoryScans(&got, scans)
+	want := "## Scorecard results\n\n" +
+		"| Repository | Score |\n" +
+		"| ---------- | ----- |\n" +
+		"| org/a | 7.5 |\n" +
+		"| org/broken | error: " + scans[1].Error + " |\n" +
+		"| org/b | 7.5 |\n\n"
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("writeRepositoryScans() mismatch (-want +got):\n%s", diff)
+	}
+
+	outputs := []resultsOutput{
+		{format: "json", file: filepath.Join(dir, "merged.json")},
+		{format: sarif, file: filepath.Join(dir, "merged.sarif")},
+	}
+	if err := writeMergedResults(scans, outputs); err != nil {
+		t.Fatalf("writeMergedResults() error = %v", err)
+	}
+	var results []scorecardResult
+	if data, err := ioutil.ReadFile(outputs[0].file); err != nil || json.Unmarshal(data, &results) != nil {
+		t.Fatalf("failed to read the merged JSON results: %v", err)
+	}
+	if len(results) != 2 || results[1].Repo.Name != "github.com/org/b" {
+		t.Errorf("writeMergedResults() JSON results = %+v", results)
+	}
+	var log struct {
+		Runs []struct {
+			AutomationDetails struct {
+				ID string `json:"id"`
+			} `json:"automationDetails"`
+		} `json:"runs"`
+	}
+	if data, err := ioutil.ReadFile(outputs[1].file); err != nil || json.Unmarshal(data, &log) != nil {
+		t.Fatalf("failed to read the merged SARIF results: %v", err)
+	}
+	if len(log.Runs) != 2 || log.Runs[0].AutomationDetails.ID != "scorecard/org/a/" {
+		t.Errorf("writeMergedResults() SARIF runs = %+v", log.Runs)
+	}
+}
+
+func Test_parseReposFile(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Lines",
+			file: "repos.txt",
+			data: "# fleet\nossf/scorecard\n\n  https://github.com/ossf/scorecard-action/ \n",
+			want: []string{"ossf/scorecard", "ossf/scorecard-action"},
+		},
+		{
+			name: "YAML list",
+			file: "repos.yml",
+			data: "- ossf/scorecard\n- github.com/ossf/scorecard-action\n",
+			want: []string{"ossf/scorecard", "ossf/scorecard-action"},
+		},
+		{
+			name: "YAML repositories key",
+			file: "repos.yaml",
+			data: "repositories:\n  - ossf/scorecard\n",
+			want: []string{"ossf/scorecard"},
+		},
+		{
+			name:    "Dot dot",
+			file:    "repos.txt",
+			data:    "ossf/scorecard\nossf/..\n",
+			wantErr: true,
+		},
+		{
+			name:    "Dot",
+			file:    "repos.yml",
+			data:    "- github.com/ossf/.\n",
+			wantErr: true,
+		},
+		{
+			name:    "Not owner/name",
+			file:    "repos.txt",
+			data:    "scorecard\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid YAML",
+			file:    "repos.yml",
+			data:    "repositories: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseReposFile(tt.file, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReposFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseReposFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_unsupportedMultiRepoInputs(t *testing.T) {
+	defer func(outputs []resultsOutput, threshold float64, exporters []string) {
+		scorecardResultsOutputs, scorecardFailOnScore, scorecardExporters = outputs, threshold, exporters
+	}(scorecardResultsOutputs, scorecardFailOnScore, scorecardExporters)
+	scorecardResultsOutputs = []resultsOutput{{format: "json", file: "results.json"}}
+	scorecardFailOnScore = noScoreThreshold
+	scorecardExporters = nil
+	if got := unsupportedMultiRepoInputs(); len(got) != 0 {
+		t.Errorf("unsupportedMultiRepoInputs() = %v, want none", got)
+	}
+
+	probe := resultsOutput{format: probeFormat, file: "results.probe.json"}
+	scorecardResultsOutputs = append(scorecardResultsOutputs, probe)
+	scorecardFailOnScore = 7
+	scorecardExporters = []string{"gs://bucket/scorecard"}
+	want := []string{inputresultsformat, inputfailonscore, inputexporters}
+	if got := unsupportedMultiRepoInputs(); !cmp.Equal(got, want) {
+		t.Errorf("unsupportedMultiRepoInputs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var errNewFindingsNeedsSARIF = errors.New("new_findings_only requires sarif to be one of the results_format")
+
+// keepNewFindings is a function to score the default branch with SARIF results and remove the findings
+// it already has from the SARIF results of the pull request, so that code scanning only annotates
+// the findings the pull request introduces.
+func keepNewFindings(writer io.Writer, sarifFile string) error {
+	cmd, err := runScorecardSettings("", scorecardPolicyFile, sarif, scorecardBin, os.Getenv(githubRepository),
+		"", scorecardChecks)
+	if err != nil {
+		return err
+	}
+	baseFile := filepath.Join(os.TempDir(), "scorecard-base-results.sarif")
+	if err := runScorecard(cmd, baseFile); err != nil {
+		return err
+	}
+	if err := enhanceSARIF(baseFile, ""); err != nil {
+		return err
+	}
+	base, err := sarifFingerprints(baseFile)
+	if err != nil {
+		return err
+	}
+	removed, kept, err := removeSARIFFindings(sarifFile, base)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "Kept %d new finding(s) of the pull request, removed %d finding(s) of the default branch.\n",
+		kept, removed)
+	return nil
+}
+
+// sarifResults is a function to get the results of every run of a SARIF log.
+func sarifResults(log map[string]interface{}) []map[string]interface{} {
+	var results []map[string]interface{}
+	runs, _ := log["runs"].([]interface{})
+	for _, r := range runs {
+		run, _ := r.(map[string]interface{})
+		list, _ := run["results"].([]interface{})
+		for _, result := range list {
+			if result, ok := result.(map[string]interface{}); ok {
+				results = append(results, result)
+			}
+		}
+	}
+	return results
+}
+
+// sarifFingerprint is a function to get the fingerprint the action added to a SARIF result.
+func sarifFingerprint(result map[string]interface{}) string {
+	fingerprints, _ := result["partialFingerprints"].(map[string]interface{})
+	fingerprint, _ := fingerprints[sarifFingerprintKey].(string)
+	return fingerprint
+}
+
+// sarifFingerprints is a function to get the fingerprints of the findings of a SARIF results file.
+func sarifFingerprints(file string) (map[string]bool, error) {
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return nil, err
+	}
+	fingerprints := make(map[string]bool)
+	for _, result := range sarifResults(log) {
+		if fingerprint := sarifFingerprint(result); fingerprint != "" {
+			fingerprints[fingerprint] = true
+		}
+	}
+	return fingerprints, nil
+}
+
+// removeSARIFFindings is a function to remove the results with one of the fingerprints from a SARIF
+// results file. It returns the number of removed and kept results.
+func removeSARIFFindings(file string, fingerprints map[string]bool) (int, int, error) {
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return 0, 0, err
+	}
+	removed, kept := 0, 0
+	runs, _ := log["runs"].([]interface{})
+	for _, r := range runs {
+		run, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		results, _ := run["results"].([]interface{})
+		newResults := []interface{}{}
+		for _, result := range results {
+			if r, ok := result.(map[string]interface{}); ok && fingerprints[sarifFingerprint(r)] {
+				removed++
+				continue
+			}
+			newResults = append(newResults, result)
+		}
+		kept += len(newResults)
+		run["results"] = newResults
+	}
+	return removed, kept, writeJSONFile(file, log)
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_removeSARIFFindings(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	log := func(messages ...string) map[string]interface{} {
+		results := make([]interface{}, 0, len(messages))
+		for _, message := range messages {
+			results = append(results, map[string]interface{}{
+				"ruleId":  "PinnedDependenciesID",
+				"message": map[string]interface{}{"text": message},
+			})
+		}
+		return map[string]interface{}{
+			"version": "2.1.0",
+			"runs":    []interface{}{map[string]interface{}{"results": results}},
+		}
+	}
+	baseFile := filepath.Join(dir, "base.sarif")
+	headFile := filepath.Join(dir, "head.sarif")
+	if err := writeJSONFile(baseFile, log(
+		"score is 5: dependency not pinned by hash: ci.yml:12",
+		"score is 5: dependency not pinned by hash: release.yml:8",
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSONFile(headFile, log(
+		"score is 3: dependency not pinned by hash: ci.yml:15",
+		"score is 3: dependency not pinned by hash: release.yml:8",
+		"score is 3: dependency not pinned by hash: deploy.yml:4",
+	)); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{baseFile, headFile} {
+		if err := enhanceSARIF(file, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base, err := sarifFingerprints(baseFile)
+	if err != nil {
+		t.Fatalf("sarifFingerprints() error = %v", err)
+	}
+	if len(base) != 2 {
+		t.Errorf("sarifFingerprints() = %v, want 2 fingerprints", base)
+	}
+	removed, kept, err := removeSARIFFindings(headFile, base)
+	if err != nil {
+		t.Fatalf("removeSARIFFindings() error = %v", err)
+	}
+	if removed != 2 || kept != 1 {
+		t.Errorf("removeSARIFFindings() = %d removed, %d kept, want 2, 1", removed, kept)
+	}
+
+	var head map[string]interface{}
+	if err := readJSONFile(headFile, &head); err != nil {
+		t.Fatal(err)
+	}
+	results := sarifResults(head)
+	if len(results) != 1 {
+		t.Fatalf("removeSARIFFindings() results = %v", results)
+	}
+	message, _ := results[0]["message"].(map[string]interface{})
+	if message["text"] != "score is 3: dependency not pinned by hash: deploy.yml:4" {
+		t.Errorf("removeSARIFFindings() kept %v", message["text"])
+	}
+}
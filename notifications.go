@@ -0,0 +1,105 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ossf/scorecard-action/pkg/notify"
+)
+
+const githubServerURL = "GITHUB_SERVER_URL"
+
+var errNotifyNeedsBaseline = errors.New("notify_on: regression needs a baseline_source to compare the results with")
+
+// initializeNotifier is a function to read the notifier inputs. slack_webhook_url is still accepted
+// in place of notify_webhook_url, as it predates the other notifiers.
+func initializeNotifier() error {
+	if result := os.Getenv(inputnotifier); result != "" {
+		scorecardNotifier = result
+	}
+	scorecardNotifyURL = os.Getenv(inputnotifywebhookurl)
+	if scorecardNotifyURL == "" {
+		scorecardNotifyURL = os.Getenv(inputslackwebhookurl)
+	}
+	// Creating the notifier checks its kind.
+	_, err := notify.New(scorecardNotifier, scorecardNotifyURL, scorecardHTTPClient)
+	return err
+}
+
+// validateNotifyOn is a function to check when to notify, and that regressions can be detected
+// when notifying of them.
+func validateNotifyOn(on string) error {
+	if err := notify.ValidateOn(on); err != nil {
+		return err
+	}
+	if on == notify.OnRegression && scorecardNotifyURL != "" && scorecardBaselineSource == "" {
+		return errNotifyNeedsBaseline
+	}
+	return nil
+}
+
+// sendNotifications is a function to post the summary of the run with the notifier, if notify_on warrants it.
+// The baseline is nil when no baseline was compared.
+func sendNotifications(ctx context.Context, jsonResultsFile string, baseline *scorecardResult,
+	deltas []checkDelta, failed bool) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	summary := notificationSummary(result, baseline, deltas, failed)
+	if !notify.ShouldNotify(scorecardNotifyOn, summary) {
+		fmt.Printf("Skipping the %s notification: notify_on is %s.\n", scorecardNotifier, scorecardNotifyOn)
+		return nil
+	}
+	notifier, err := notify.New(scorecardNotifier, scorecardNotifyURL, scorecardHTTPClient)
+	if err != nil {
+		return err
+	}
+	if err := notifier.Notify(ctx, summary); err != nil {
+		return err
+	}
+	fmt.Printf("Sent the %s notification of the results.\n", scorecardNotifier)
+	return nil
+}
+
+// workflowRunURL is a function to get the URL of the current workflow run, if running in GitHub Actions.
+func workflowRunURL() string {
+	serverURL, runID := os.Getenv(githubServerURL), os.Getenv(githubRunID)
+	if serverURL == "" || runID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", serverURL, os.Getenv(githubRepository), runID)
+}
+
+// notificationSummary is a function to summarize the run for the notifications.
+func notificationSummary(result, baseline *scorecardResult, deltas []checkDelta, failed bool) notify.Summary {
+	summary := notify.Summary{
+		Repository: os.Getenv(githubRepository),
+		RunURL:     workflowRunURL(),
+		Score:      result.Score,
+		Failed:     failed,
+	}
+	if baseline != nil {
+		summary.HasBaseline = true
+		summary.BaseScore = baseline.Score
+	}
+	for _, d := range regressions(deltas) {
+		summary.Regressions = append(summary.Regressions, notify.Regression{Check: d.name, Base: d.base, Head: d.head})
+	}
+	return summary
+}
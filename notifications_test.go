@@ -0,0 +1,116 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/pkg/notify"
+)
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_initializeNotifier(t *testing.T) {
+	for _, name := range []string{inputnotifier, inputnotifywebhookurl, inputslackwebhookurl} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+	defer func(notifier, url string) {
+		scorecardNotifier, scorecardNotifyURL = notifier, url
+	}(scorecardNotifier, scorecardNotifyURL)
+
+	os.Setenv(inputslackwebhookurl, "https://hooks.slack.com/services/T000/B000/XXXX")
+	if err := initializeNotifier(); err != nil {
+		t.Fatalf("initializeNotifier() error = %v", err)
+	}
+	if scorecardNotifier != notify.NotifierSlack || scorecardNotifyURL != "https://hooks.slack.com/services/T000/B000/XXXX" {
+		t.Errorf("initializeNotifier() = %s %s, want the slack_webhook_url", scorecardNotifier, scorecardNotifyURL)
+	}
+
+	os.Setenv(inputnotifier, notify.NotifierTeams)
+	os.Setenv(inputnotifywebhookurl, "https://example.webhook.office.com/webhookb2/1")
+	if err := initializeNotifier(); err != nil {
+		t.Fatalf("initializeNotifier() error = %v", err)
+	}
+	if scorecardNotifier != notify.NotifierTeams || scorecardNotifyURL != "https://example.webhook.office.com/webhookb2/1" {
+		t.Errorf("initializeNotifier() = %s %s, want the notify_webhook_url", scorecardNotifier, scorecardNotifyURL)
+	}
+
+	os.Setenv(inputnotifier, "irc")
+	if err := initializeNotifier(); err == nil {
+		t.Errorf("initializeNotifier() error = nil, want an error")
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the global variables
+//nolint
+func Test_validateNotifyOn(t *testing.T) {
+	defer func(url, source string) {
+		scorecardNotifyURL, scorecardBaselineSource = url, source
+	}(scorecardNotifyURL, scorecardBaselineSource)
+	scorecardNotifyURL = "https://hooks.slack.com/services/T000/B000/XXXX"
+
+	scorecardBaselineSource = ""
+	if err := validateNotifyOn(notify.OnRegression); !errors.Is(err, errNotifyNeedsBaseline) {
+		t.Errorf("validateNotifyOn() error = %v, want %v", err, errNotifyNeedsBaseline)
+	}
+	if err := validateNotifyOn(notify.OnFailure); err != nil {
+		t.Errorf("validateNotifyOn() error = %v", err)
+	}
+	scorecardBaselineSource = baselineSourceArtifact
+	if err := validateNotifyOn(notify.OnRegression); err != nil {
+		t.Errorf("validateNotifyOn() error = %v", err)
+	}
+	if err := validateNotifyOn("never"); err == nil {
+		t.Errorf("validateNotifyOn() error = nil, want an error")
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_notificationSummary(t *testing.T) {
+	for _, name := range []string{githubRepository, githubServerURL, githubRunID} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+	os.Setenv(githubRepository, "ossf/scorecard-action")
+	os.Setenv(githubServerURL, "https://github.com")
+	os.Setenv(githubRunID, "42")
+	head, err := readScorecardResult("testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	base := &scorecardResult{Score: 7.2}
+	deltas := []checkDelta{
+		{name: "Binary-Artifacts", base: 10, head: 10},
+		{name: "Branch-Protection", base: 8, head: 3},
+	}
+
+	got := notificationSummary(head, base, deltas, true)
+	want := notify.Summary{
+		Repository:  "ossf/scorecard-action",
+		RunURL:      "https://github.com/ossf/scorecard-action/actions/runs/42",
+		Regressions: []notify.Regression{{Check: "Branch-Protection", Base: 8, Head: 3}},
+		Score:       6.8,
+		BaseScore:   7.2,
+		HasBaseline: true,
+		Failed:      true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("notificationSummary() (-want +got):\n%s", diff)
+	}
+}
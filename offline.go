@@ -0,0 +1,110 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errOfflinePublish = errors.New("publish_results cannot be true in offline mode")
+	errOfflineNetwork = errors.New("offline mode disables the network access needed by")
+)
+
+// networkChecks are the checks that query services other than GitHub, with the service they query.
+var networkChecks = map[string]string{
+	"CII-Best-Practices": "bestpractices.coreinfrastructure.org",
+	"Vulnerabilities":    "osv.dev",
+}
+
+// offlineLocal is a function to check if the run makes no network call at all:
+// offline mode on a local folder does not even call the GitHub API.
+func offlineLocal() bool {
+	return scorecardOffline == "true" && scorecardLocalPath != ""
+}
+
+// applyOffline is a function to check the inputs do not need network access offline mode disables,
+// and to skip the checks that query services other than GitHub.
+func applyOffline() error {
+	if scorecardOffline != "true" {
+		return nil
+	}
+	if scorecardPublishResults == "true" {
+		return errOfflinePublish
+	}
+	if scorecardBaselineSource == baselineSourceAPI {
+		return fmt.Errorf("%w: baseline_source=%s, which reads the scorecard API", errOfflineNetwork, baselineSourceAPI)
+	}
+	for _, destination := range scorecardExporters {
+		if !strings.HasPrefix(destination, "file://") {
+			return fmt.Errorf("%w: exporting the results to %s", errOfflineNetwork, exportScheme(destination))
+		}
+	}
+	if scorecardPushgatewayURL != "" {
+		return fmt.Errorf("%w: pushing the metrics to the Pushgateway", errOfflineNetwork)
+	}
+	if scorecardDatadogAPIKey != "" {
+		return fmt.Errorf("%w: sending the scores to Datadog", errOfflineNetwork)
+	}
+	if scorecardNotifyURL != "" {
+		return fmt.Errorf("%w: sending %s notifications", errOfflineNetwork, scorecardNotifier)
+	}
+	for _, check := range scorecardChecks {
+		if service, ok := networkChecks[check]; ok {
+			return fmt.Errorf("%w: the %s check, which queries %s", errOfflineNetwork, check, service)
+		}
+	}
+
+	if !offlineLocal() {
+		if len(scorecardChecks) == 0 {
+			for _, check := range knownChecks {
+				if _, ok := networkChecks[check]; !ok {
+					scorecardChecks = append(scorecardChecks, check)
+				}
+			}
+		}
+		return nil
+	}
+
+	// Offline local runs do not call the GitHub API at all.
+	forgeInputs := []struct {
+		name string
+		set  bool
+	}{
+		{"pr_comment", scorecardPRComment == "true"},
+		{"check_run", scorecardCheckRun == "true"},
+		{"commit_status", scorecardCommitStatus == "true"},
+		{"skip_unchanged", scorecardSkipUnchanged == "true"},
+		{"scorecard_version", downloadsScorecard()},
+		{"baseline_source", scorecardBaselineSource != ""},
+		{"save_history", scorecardSaveHistory == "true"},
+		{"badge_branch", scorecardBadgeBranch != ""},
+		{"badge_gist", scorecardBadgeGist != ""},
+		{"open_issues", scorecardOpenIssues == "true"},
+		{"remediate", len(scorecardRemediations) > 0},
+		{"upload_sarif", scorecardUploadSARIF == "true"},
+		{"new_findings_only", scorecardNewFindingsOnly == "true"},
+		{"organization", scorecardOrganization != ""},
+		{"repos_file", scorecardReposFile != ""},
+		{"app_id", scorecardAppID != 0},
+	}
+	for _, input := range forgeInputs {
+		if input.set {
+			return fmt.Errorf("%w: %s, which calls the GitHub API", errOfflineNetwork, input.name)
+		}
+	}
+	return nil
+}
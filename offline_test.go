@@ -0,0 +1,105 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_applyOffline(t *testing.T) {
+	offline, publish, localPath, checks, baseline, checkRun := scorecardOffline, scorecardPublishResults,
+		scorecardLocalPath, scorecardChecks, scorecardBaselineSource, scorecardCheckRun
+	datadogAPIKey := scorecardDatadogAPIKey
+	defer func() {
+		scorecardOffline, scorecardPublishResults, scorecardLocalPath, scorecardChecks, scorecardBaselineSource,
+			scorecardCheckRun = offline, publish, localPath, checks, baseline, checkRun
+		scorecardDatadogAPIKey = datadogAPIKey
+	}()
+
+	tests := []struct {
+		name       string
+		setup      func()
+		wantErr    error
+		wantChecks []string
+	}{
+		{
+			name:  "Online",
+			setup: func() { scorecardOffline = "false" },
+		},
+		{
+			name:  "Skips the checks querying other services",
+			setup: func() {},
+			wantChecks: []string{
+				"Binary-Artifacts", "Branch-Protection", "CI-Tests", "Code-Review", "Contributors",
+				"Dangerous-Workflow", "Dependency-Update-Tool", "Fuzzing", "License", "Maintained", "Packaging",
+				"Pinned-Dependencies", "SAST", "Security-Policy", "Signed-Releases", "Token-Permissions",
+			},
+		},
+		{
+			name:    "Publishing",
+			setup:   func() { scorecardPublishResults = "true" },
+			wantErr: errOfflinePublish,
+		},
+		{
+			name:    "Selected check querying another service",
+			setup:   func() { scorecardChecks = []string{"Code-Review", "Vulnerabilities"} },
+			wantErr: errOfflineNetwork,
+		},
+		{
+			name:    "Scorecard API baseline",
+			setup:   func() { scorecardBaselineSource = baselineSourceAPI },
+			wantErr: errOfflineNetwork,
+		},
+		{
+			name:    "Datadog",
+			setup:   func() { scorecardDatadogAPIKey = "dd-api-key" },
+			wantErr: errOfflineNetwork,
+		},
+		{
+			name: "Local run calling the GitHub API",
+			setup: func() {
+				scorecardLocalPath = "."
+				scorecardCheckRun = "true"
+			},
+			wantErr: errOfflineNetwork,
+		},
+		{
+			name:  "Local run",
+			setup: func() { scorecardLocalPath = "." },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scorecardOffline, scorecardPublishResults, scorecardLocalPath, scorecardChecks, scorecardBaselineSource,
+				scorecardCheckRun = "true", "false", "", nil, "", ""
+			scorecardDatadogAPIKey = ""
+			tt.setup()
+			err := applyOffline()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("applyOffline() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(tt.wantChecks, scorecardChecks); diff != "" {
+					t.Errorf("applyOffline() checks mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// githubOutput is the file GitHub Actions reads the outputs of the step from.
+const githubOutput = "GITHUB_OUTPUT"
+
+// exportOutputs is a function to write the results files and scores to the GITHUB_OUTPUT file,
+// so that later steps do not have to parse the results. The scores need JSON results.
+func exportOutputs(outputFile string, requested, outputs []resultsOutput) error {
+	//nolint:gosec
+	f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", outputFile, err)
+	}
+	defer f.Close()
+
+	writeResultsFileOutputs(f, requested)
+	resultsFile, ok := jsonResultsFile(outputs)
+	if !ok {
+		fmt.Println("Skipping the score outputs: add json to results_format to enable them.")
+		return nil
+	}
+	result, err := readScorecardResult(resultsFile)
+	if err != nil {
+		return err
+	}
+	return writeScoreOutputs(f, result)
+}
+
+// writeResultsFileOutputs is a function to write the results file of every requested format,
+// and the comma-separated list of all of them.
+func writeResultsFileOutputs(writer io.Writer, outputs []resultsOutput) {
+	files := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		fmt.Fprintf(writer, "results_file_%s=%s\n", output.format, output.file)
+		files = append(files, output.file)
+	}
+	fmt.Fprintf(writer, "results_files=%s\n", strings.Join(files, ","))
+}
+
+// writeScoreOutputs is a function to write the aggregate score, the score of every check,
+// and all check scores as a JSON object. Inconclusive checks have a score of -1.
+func writeScoreOutputs(writer io.Writer, result *scorecardResult) error {
+	fmt.Fprintf(writer, "score=%.1f\n", result.Score)
+	scores := make(map[string]int, len(result.Checks))
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		scores[check.Name] = check.Score
+		fmt.Fprintf(writer, "%s=%d\n", checkScoreOutput(check.Name), check.Score)
+	}
+	data, err := json.Marshal(scores)
+	if err != nil {
+		return fmt.Errorf("error marshalling the check scores: %w", err)
+	}
+	fmt.Fprintf(writer, "check_scores=%s\n", data)
+	return nil
+}
+
+// checkScoreOutput is a function to get the name of the output of a check score,
+// e.g. score_branch_protection for Branch-Protection.
+func checkScoreOutput(check string) string {
+	return "score_" + strings.ReplaceAll(strings.ToLower(check), "-", "_")
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_exportOutputs(t *testing.T) {
+	t.Parallel()
+	outputFile := filepath.Join(t.TempDir(), "output")
+	requested := []resultsOutput{{format: sarif, file: "results.sarif"}}
+	outputs := append(requested, resultsOutput{format: "json", file: "./testdata/results.json"})
+	if err := exportOutputs(outputFile, requested, outputs); err != nil {
+		t.Fatalf("exportOutputs() error = %v", err)
+	}
+	got, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := `results_file_sarif=results.sarif
+results_files=results.sarif
+score=6.8
+score_binary_artifacts=10
+score_branch_protection=3
+score_cii_best_practices=-1
+score_pinned_dependencies=7
+check_scores={"Binary-Artifacts":10,"Branch-Protection":3,"CII-Best-Practices":-1,"Pinned-Dependencies":7}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("exportOutputs() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_checkScoreOutput(t *testing.T) {
+	t.Parallel()
+	if got := checkScoreOutput("CII-Best-Practices"); got != "score_cii_best_practices" {
+		t.Errorf("checkScoreOutput() = %q", got)
+	}
+}
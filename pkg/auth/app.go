@@ -0,0 +1,136 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates the action as a GitHub App, minting installation tokens
+// so that workflows do not need a long-lived personal access token. Its JWT signing is shared
+// with the exporters authenticating to cloud services with service account keys.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// jwtLifetime is how long the JWTs authenticating as the app are valid. GitHub accepts at most 10 minutes.
+	jwtLifetime = 9 * time.Minute
+	// clockDrift backdates the JWTs in case the runner's clock is ahead of GitHub's.
+	clockDrift = time.Minute
+	// maxErrorMessageSize caps how much of an error response is kept in the error message.
+	maxErrorMessageSize = 1024
+)
+
+var (
+	errInvalidPrivateKey = errors.New("invalid RSA private key")
+	errGitHubAPI         = errors.New("GitHub API request failed")
+)
+
+// App is a GitHub App authenticating with its private key.
+type App struct {
+	// HTTPClient sends the GitHub API requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	key        *rsa.PrivateKey
+	baseURL    string
+	id         int64
+}
+
+// Token is a GitHub App installation token.
+type Token struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Value     string    `json:"token"`
+}
+
+// NewApp is a function to create a GitHub App from its ID and PEM encoded private key,
+// using the GitHub API at baseURL.
+func NewApp(id int64, privateKey []byte, baseURL string) (*App, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &App{
+		HTTPClient: http.DefaultClient,
+		key:        key,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		id:         id,
+	}, nil
+}
+
+// JWT is a function to create the RS256 signed JSON Web Token authenticating as the app.
+func (a *App) JWT(now time.Time) (string, error) {
+	return SignJWT(a.key, map[string]int64{
+		"iat": now.Add(-clockDrift).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": a.id,
+	})
+}
+
+// InstallationID is a function to get the ID of the app installation on the repository.
+func (a *App) InstallationID(ctx context.Context, repository string) (int64, error) {
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/installation", repository), &installation); err != nil {
+		return 0, fmt.Errorf("error getting the app installation of %s: %w", repository, err)
+	}
+	return installation.ID, nil
+}
+
+// InstallationToken is a function to mint a token of the app installation.
+// Installation tokens expire after an hour.
+func (a *App) InstallationToken(ctx context.Context, installationID int64) (Token, error) {
+	var token Token
+	p := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+	if err := a.do(ctx, http.MethodPost, p, &token); err != nil {
+		return Token{}, fmt.Errorf("error creating an installation token: %w", err)
+	}
+	return token, nil
+}
+
+// do is a function to send a request authenticated as the app and decode its JSON response into out.
+func (a *App) do(ctx context.Context, method, path string, out interface{}) error {
+	jwt, err := a.JWT(time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+		return fmt.Errorf("%w: %s %s: %d %s", errGitHubAPI, method, path, resp.StatusCode,
+			strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	return nil
+}
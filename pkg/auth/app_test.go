@@ -0,0 +1,165 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestApp is a function to create an app with a freshly generated key, using the handler as the GitHub API.
+func newTestApp(t *testing.T, handler http.Handler) (*App, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	app, err := NewApp(42, pemKey, server.URL+"/")
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	app.HTTPClient = server.Client()
+	return app, key
+}
+
+func TestApp_JWT(t *testing.T) {
+	t.Parallel()
+	app, key := newTestApp(t, http.NotFoundHandler())
+	now := time.Unix(1650000000, 0)
+	jwt, err := app.JWT(now)
+	if err != nil {
+		t.Fatalf("JWT() error = %v", err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT() = %q, want 3 parts", jwt)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("error decoding signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("JWT() signature does not verify: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("error decoding claims: %v", err)
+	}
+	var claims map[string]int64
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("error unmarshalling claims: %v", err)
+	}
+	if claims["iss"] != 42 || claims["iat"] != now.Add(-clockDrift).Unix() || claims["exp"] != now.Add(jwtLifetime).Unix() {
+		t.Errorf("JWT() claims = %v", claims)
+	}
+}
+
+func TestNewApp_privateKey(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	//nolint
+	tests := []struct {
+		name    string
+		key     []byte
+		wantErr bool
+	}{
+		{
+			name: "PKCS #8 key",
+			key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			name:    "Not PEM",
+			key:     []byte("not a key"),
+			wantErr: true,
+		},
+		{
+			name:    "Not a private key",
+			key:     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewApp(1, tt.key, "https://api.github.com")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewApp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errInvalidPrivateKey) {
+				t.Errorf("NewApp() error = %v, want %v", err, errInvalidPrivateKey)
+			}
+		})
+	}
+}
+
+func TestApp_InstallationToken(t *testing.T) {
+	t.Parallel()
+	app, _ := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ey") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/installation":
+			w.Write([]byte(`{"id": 7}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/7/access_tokens":
+			w.Write([]byte(`{"token": "ghs_token", "expires_at": "2022-04-15T06:20:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	id, err := app.InstallationID(context.Background(), "owner/repo")
+	if err != nil || id != 7 {
+		t.Fatalf("InstallationID() = %v, %v, want 7", id, err)
+	}
+	token, err := app.InstallationToken(context.Background(), id)
+	if err != nil {
+		t.Fatalf("InstallationToken() error = %v", err)
+	}
+	want := Token{Value: "ghs_token", ExpiresAt: time.Date(2022, 4, 15, 6, 20, 0, 0, time.UTC)}
+	if token.Value != want.Value || !token.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("InstallationToken() = %+v, want %+v", token, want)
+	}
+
+	if _, err := app.InstallationID(context.Background(), "owner/missing"); !errors.Is(err, errGitHubAPI) {
+		t.Errorf("InstallationID() error = %v, want %v", err, errGitHubAPI)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// refreshMargin is how long before it expires an installation token is replaced,
+// so that requests in flight do not fail with an expired token.
+const refreshMargin = 5 * time.Minute
+
+// TokenSource mints installation tokens of an app installation, refreshing them before they expire.
+// It is safe for concurrent use.
+type TokenSource struct {
+	app            *App
+	now            func() time.Time
+	token          Token
+	installationID int64
+	mu             sync.Mutex
+}
+
+// NewTokenSource is a function to create a token source for the app installation.
+func NewTokenSource(app *App, installationID int64) *TokenSource {
+	return &TokenSource{
+		app:            app,
+		now:            time.Now,
+		installationID: installationID,
+	}
+}
+
+// Token is a function to get a valid installation token, minting a new one
+// when the current token is about to expire.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Value != "" && s.now().Add(refreshMargin).Before(s.token.ExpiresAt) {
+		return s.token.Value, nil
+	}
+	token, err := s.app.InstallationToken(ctx, s.installationID)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	return token.Value, nil
+}
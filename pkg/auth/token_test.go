@@ -0,0 +1,57 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenSource_Token(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 4, 15, 6, 0, 0, 0, time.UTC)
+	minted := 0
+	app, _ := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		minted++
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, minted,
+			now.Add(time.Hour).Format(time.RFC3339))
+	}))
+	tokens := NewTokenSource(app, 7)
+	tokens.now = func() time.Time { return now }
+
+	//nolint
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    string
+	}{
+		{name: "Mints the first token", want: "token-1"},
+		{name: "Reuses a valid token", elapsed: 50 * time.Minute, want: "token-1"},
+		{name: "Refreshes a token about to expire", elapsed: 56 * time.Minute, want: "token-2"},
+	}
+	// The subtests share the token source, so they run in order.
+	for _, tt := range tests {
+		tokens.now = func() time.Time { return now.Add(tt.elapsed) }
+		got, err := tokens.Token(context.Background())
+		if err != nil {
+			t.Fatalf("%s: Token() error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Token() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
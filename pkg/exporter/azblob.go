@@ -0,0 +1,157 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	azureStorageSASToken    = "AZURE_STORAGE_SAS_TOKEN" //nolint:gosec
+	azureClientID           = "AZURE_CLIENT_ID"
+	azureTenantID           = "AZURE_TENANT_ID"
+	azureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE" //nolint:gosec
+	azureAuthorityHost      = "https://login.microsoftonline.com"
+	// azureOIDCAudience is the audience of the GitHub OIDC tokens Azure AD federated credentials accept.
+	azureOIDCAudience   = "api://AzureADTokenExchange"
+	azureStorageScope   = "https://storage.azure.com/.default"
+	azureBlobAPIVersion = "2020-10-02"
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+var errAzureUploadFailed = errors.New("Azure Blob Storage upload failed")
+
+// azureBlob uploads the results to an Azure Blob Storage container.
+type azureBlob struct {
+	client *http.Client
+	// credentials returns the SAS token query or the bearer token the uploads are authorized with.
+	credentials func(ctx context.Context) (sas, token string, err error)
+	endpoint    string
+	container   string
+	prefix      string
+}
+
+// newAzureBlob is a function to create the exporter of an az://account/container/prefix destination.
+func newAzureBlob(destination *url.URL, options Options) (Exporter, error) {
+	parts := strings.SplitN(strings.Trim(destination.Path, "/"), "/", 2)
+	if destination.Host == "" || parts[0] == "" {
+		return nil, fmt.Errorf("%w: %s is not az://account/container/prefix", errInvalidDestination, destination)
+	}
+	prefix := ""
+	if len(parts) == 2 {
+		prefix = parts[1]
+	}
+	client := options.HTTPClient
+	return &azureBlob{
+		client: client,
+		credentials: func(ctx context.Context) (string, string, error) {
+			return azureCredentialsFromEnv(ctx, client, azureAuthorityHost)
+		},
+		endpoint:  fmt.Sprintf("https://%s.blob.core.windows.net", destination.Host),
+		container: parts[0],
+		prefix:    prefix,
+	}, nil
+}
+
+// Export is a function to upload the JSON results and the results files under the prefix.
+func (a *azureBlob) Export(ctx context.Context, results Results) error {
+	files, err := objects(results)
+	if err != nil {
+		return err
+	}
+	sas, token, err := a.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := a.upload(ctx, sas, token, objectName(a.prefix, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upload is a function to PUT a single block blob into the container.
+func (a *azureBlob) upload(ctx context.Context, sas, token, name string, data []byte) error {
+	u := a.endpoint + (&url.URL{Path: "/" + a.container + "/" + name}).EscapedPath()
+	if sas != "" {
+		u += "?" + sas
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(name))
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", azureBlobAPIVersion)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading %s/%s: %w", a.container, name, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return fmt.Errorf("%s/%s: %w", a.container, name, responseError(errAzureUploadFailed, resp))
+	}
+	return nil
+}
+
+// azureCredentialsFromEnv is a function to get the runner's ambient Azure credentials: a SAS token,
+// or an Azure AD token obtained with workload identity federation. The federated token is read from
+// AZURE_FEDERATED_TOKEN_FILE, or requested from GitHub's OIDC provider as azure/login does.
+func azureCredentialsFromEnv(ctx context.Context, client *http.Client, authorityHost string) (string, string, error) {
+	if sas := os.Getenv(azureStorageSASToken); sas != "" {
+		return strings.TrimPrefix(sas, "?"), "", nil
+	}
+	clientID, tenantID := os.Getenv(azureClientID), os.Getenv(azureTenantID)
+	if clientID == "" || tenantID == "" {
+		return "", "", fmt.Errorf("%w: set %s, or %s and %s", errNoCredentials, azureStorageSASToken,
+			azureClientID, azureTenantID)
+	}
+	var assertion string
+	if file := os.Getenv(azureFederatedTokenFile); file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", "", fmt.Errorf("error reading %s: %w", azureFederatedTokenFile, err)
+		}
+		assertion = strings.TrimSpace(string(data))
+	} else {
+		var err error
+		if assertion, err = githubOIDCToken(ctx, client, azureOIDCAudience); err != nil {
+			return "", "", err
+		}
+	}
+	token, err := postTokenForm(ctx, client, fmt.Sprintf("%s/%s/oauth2/v2.0/token", authorityHost, tenantID), url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {azureStorageScope},
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {assertion},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return "", token, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAzureBlob_Export(t *testing.T) {
+	t.Parallel()
+	uploaded := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("sig") != "signature" && r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		uploaded[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	e := &azureBlob{
+		client: server.Client(),
+		credentials: func(ctx context.Context) (string, string, error) {
+			return "sv=2020-10-02&sig=signature", "", nil
+		},
+		endpoint:  server.URL,
+		container: "container",
+		prefix:    "ossf/scorecard",
+	}
+	results := Results{JSON: []byte(`{"score": 7}`)}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	e.prefix = "bearer"
+	e.credentials = func(ctx context.Context) (string, string, error) { return "", "token", nil }
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	want := map[string]string{
+		"/container/ossf/scorecard/results.json": `{"score": 7}`,
+		"/container/bearer/results.json":         `{"score": 7}`,
+	}
+	if diff := cmp.Diff(want, uploaded); diff != "" {
+		t.Errorf("Export() uploaded (-want +got):\n%s", diff)
+	}
+
+	e.credentials = func(ctx context.Context) (string, string, error) { return "", "expired", nil }
+	if err := e.Export(context.Background(), results); !errors.Is(err, errAzureUploadFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errAzureUploadFailed)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_azureCredentialsFromEnv(t *testing.T) {
+	names := []string{azureStorageSASToken, azureClientID, azureTenantID, azureFederatedTokenFile,
+		actionsIDTokenRequestURL, actionsIDTokenRequestToken}
+	for _, name := range names {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("audience") != azureOIDCAudience {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"value": "oidc-token"}`))
+	})
+	mux.HandleFunc("/tenant/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("client_assertion") != "oidc-token" ||
+			r.Form.Get("client_id") != "client" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token": "azure-token"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	if _, _, err := azureCredentialsFromEnv(context.Background(), server.Client(), server.URL); !errors.Is(err, errNoCredentials) {
+		t.Errorf("azureCredentialsFromEnv() error = %v, want %v", err, errNoCredentials)
+	}
+
+	os.Setenv(azureClientID, "client")
+	os.Setenv(azureTenantID, "tenant")
+	if _, _, err := azureCredentialsFromEnv(context.Background(), server.Client(), server.URL); !errors.Is(err, errNoOIDC) {
+		t.Errorf("azureCredentialsFromEnv() error = %v, want %v", err, errNoOIDC)
+	}
+
+	os.Setenv(actionsIDTokenRequestURL, server.URL+"/oidc?api-version=2.0")
+	os.Setenv(actionsIDTokenRequestToken, "request-token")
+	sas, token, err := azureCredentialsFromEnv(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("azureCredentialsFromEnv() error = %v", err)
+	}
+	if sas != "" || token != "azure-token" {
+		t.Errorf("azureCredentialsFromEnv() = %q, %q, want the Azure AD token", sas, token)
+	}
+
+	os.Setenv(azureStorageSASToken, "?sv=2020-10-02&sig=signature")
+	if sas, _, _ := azureCredentialsFromEnv(context.Background(), server.Client(), server.URL); sas != "sv=2020-10-02&sig=signature" {
+		t.Errorf("azureCredentialsFromEnv() SAS = %q", sas)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultBigQueryEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+	bigQueryInsertScope     = "https://www.googleapis.com/auth/bigquery.insertdata"
+)
+
+var errBigQueryInsertFailed = errors.New("BigQuery insert failed")
+
+// resultRepo is the analyzed repository and commit.
+type resultRepo struct {
+	Name   string `json:"name"`
+	Commit string `json:"commit"`
+}
+
+// resultScorecard is the scorecard release that produced the results.
+type resultScorecard struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// scorecardResult is the subset of scorecard's JSON results stored in BigQuery.
+type scorecardResult struct {
+	Repo      resultRepo      `json:"repo"`
+	Scorecard resultScorecard `json:"scorecard"`
+	Date      string          `json:"date"`
+	Checks    []checkResult   `json:"checks"`
+	Score     float64         `json:"score"`
+}
+
+// checkResult is the result of a single scorecard check, with the fields of the cron project's checks.
+type checkResult struct {
+	Documentation struct {
+		Short string `json:"short"`
+		URL   string `json:"url"`
+	} `json:"documentation"`
+	Name    string   `json:"name"`
+	Reason  string   `json:"reason"`
+	Details []string `json:"details"`
+	Score   int      `json:"score"`
+}
+
+// bigQueryRow is the row of a single check. It flattens the repeated checks of the scorecard cron
+// project's schema, keeping its field names so that the rows can be joined with the public data.
+type bigQueryRow struct {
+	Repo      resultRepo      `json:"repo"`
+	Scorecard resultScorecard `json:"scorecard"`
+	Check     checkResult     `json:"check"`
+	Date      string          `json:"date"`
+	Score     float64         `json:"score"`
+}
+
+// bigQuery streams the results into a BigQuery table, one row per check.
+type bigQuery struct {
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+	endpoint string
+	project  string
+	dataset  string
+	table    string
+}
+
+// newBigQuery is a function to create the exporter of a bq://project/dataset/table destination.
+func newBigQuery(destination *url.URL, options Options) (Exporter, error) {
+	parts := strings.Split(strings.Trim(destination.Path, "/"), "/")
+	if destination.Host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%w: %s is not bq://project/dataset/table", errInvalidDestination, destination)
+	}
+	client := options.HTTPClient
+	return &bigQuery{
+		client: client,
+		token: func(ctx context.Context) (string, error) {
+			return googleAccessToken(ctx, client, bigQueryInsertScope)
+		},
+		endpoint: defaultBigQueryEndpoint,
+		project:  destination.Host,
+		dataset:  parts[0],
+		table:    parts[1],
+	}, nil
+}
+
+// Export is a function to insert a row per check with the tabledata.insertAll streaming API.
+func (b *bigQuery) Export(ctx context.Context, results Results) error {
+	rows, err := bigQueryRows(results.JSON)
+	if err != nil {
+		return err
+	}
+	token, err := b.token(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{"rows": rows})
+	if err != nil {
+		return fmt.Errorf("error marshalling rows: %w", err)
+	}
+	u := fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", b.endpoint,
+		url.PathEscape(b.project), url.PathEscape(b.dataset), url.PathEscape(b.table))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error inserting into %s.%s.%s: %w", b.project, b.dataset, b.table, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return responseError(errBigQueryInsertFailed, resp)
+	}
+	var response struct {
+		InsertErrors []struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+			Index int `json:"index"`
+		} `json:"insertErrors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	if len(response.InsertErrors) > 0 {
+		first := response.InsertErrors[0]
+		var messages []string
+		for _, e := range first.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("%w: %d of %d rows rejected, row %d: %s", errBigQueryInsertFailed,
+			len(response.InsertErrors), len(rows), first.Index, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// bigQueryRows is a function to flatten the JSON results into the insertAll rows of every check.
+// The insert IDs are derived from the repository, commit, date and check, so that BigQuery drops
+// the duplicates of a retried run.
+func bigQueryRows(data []byte) ([]map[string]interface{}, error) {
+	var result scorecardResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the JSON results: %w", err)
+	}
+	rows := make([]map[string]interface{}, 0, len(result.Checks))
+	for i := range result.Checks {
+		check := result.Checks[i]
+		if check.Details == nil {
+			check.Details = []string{}
+		}
+		id := sha256.Sum256([]byte(strings.Join([]string{result.Repo.Name, result.Repo.Commit, result.Date, check.Name}, "\n")))
+		rows = append(rows, map[string]interface{}{
+			"insertId": hex.EncodeToString(id[:]),
+			"json": bigQueryRow{
+				Repo:      result.Repo,
+				Scorecard: result.Scorecard,
+				Check:     check,
+				Date:      result.Date,
+				Score:     result.Score,
+			},
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const bigQueryTestResults = `{
+  "date": "2022-03-01",
+  "repo": {"name": "github.com/ossf/scorecard-action", "commit": "aa0496aa"},
+  "scorecard": {"version": "v4.1.0", "commit": "b2ed3b9e"},
+  "score": 6.8,
+  "checks": [
+    {"name": "Binary-Artifacts", "score": 10, "reason": "no binaries found in the repo", "details": null,
+     "documentation": {"short": "Determines if the project has binary artifacts.", "url": "https://example.com/ba"}},
+    {"name": "Branch-Protection", "score": 3, "reason": "branch protection is not maximal",
+     "details": ["Warn: number of required reviewers is only 1"],
+     "documentation": {"short": "Determines if branches are protected.", "url": "https://example.com/bp"}}
+  ]
+}`
+
+func TestBigQuery_Export(t *testing.T) {
+	t.Parallel()
+	var inserted []bigQueryRow
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/project/datasets/scorecard/tables/results/insertAll" ||
+			r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body struct {
+			Rows []struct {
+				InsertID string      `json:"insertId"`
+				JSON     bigQueryRow `json:"json"`
+			} `json:"rows"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, row := range body.Rows {
+			inserted = append(inserted, row.JSON)
+		}
+		w.Write([]byte(`{"kind": "bigquery#tableDataInsertAllResponse"}`))
+	}))
+	defer server.Close()
+
+	e := &bigQuery{
+		client:   server.Client(),
+		token:    func(ctx context.Context) (string, error) { return "token", nil },
+		endpoint: server.URL,
+		project:  "project",
+		dataset:  "scorecard",
+		table:    "results",
+	}
+	if err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	got := make(map[string]int)
+	for _, row := range inserted {
+		if row.Repo.Name != "github.com/ossf/scorecard-action" || row.Date != "2022-03-01" || row.Score != 6.8 {
+			t.Errorf("Export() inserted %+v", row)
+		}
+		got[row.Check.Name] = row.Check.Score
+	}
+	if diff := cmp.Diff(map[string]int{"Binary-Artifacts": 10, "Branch-Protection": 3}, got); diff != "" {
+		t.Errorf("Export() inserted check scores (-want +got):\n%s", diff)
+	}
+
+	e.table = "missing"
+	if err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)}); !errors.Is(err, errBigQueryInsertFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errBigQueryInsertFailed)
+	}
+}
+
+func TestBigQuery_Export_insertErrors(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"insertErrors": [{"index": 1, "errors": [{"message": "no such field: check"}]}]}`))
+	}))
+	defer server.Close()
+
+	e := &bigQuery{
+		client:   server.Client(),
+		token:    func(ctx context.Context) (string, error) { return "token", nil },
+		endpoint: server.URL,
+		project:  "project",
+		dataset:  "scorecard",
+		table:    "results",
+	}
+	if err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)}); !errors.Is(err, errBigQueryInsertFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errBigQueryInsertFailed)
+	}
+}
+
+func Test_bigQueryRows(t *testing.T) {
+	t.Parallel()
+	rows, err := bigQueryRows([]byte(bigQueryTestResults))
+	if err != nil {
+		t.Fatalf("bigQueryRows() error = %v", err)
+	}
+	again, err := bigQueryRows([]byte(bigQueryTestResults))
+	if err != nil {
+		t.Fatalf("bigQueryRows() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("bigQueryRows() = %d rows, want 2", len(rows))
+	}
+	if rows[0]["insertId"] != again[0]["insertId"] || rows[0]["insertId"] == rows[1]["insertId"] {
+		t.Errorf("bigQueryRows() insert IDs are not stable per check: %v, %v, %v",
+			rows[0]["insertId"], again[0]["insertId"], rows[1]["insertId"])
+	}
+	if details := rows[0]["json"].(bigQueryRow).Check.Details; details == nil {
+		t.Errorf("bigQueryRows() details = nil, want an empty list for the REPEATED field")
+	}
+
+	if _, err := bigQueryRows([]byte("not json")); err == nil {
+		t.Errorf("bigQueryRows() error = nil, want an error")
+	}
+}
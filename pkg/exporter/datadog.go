@@ -0,0 +1,191 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDatadogSite is the Datadog site of datadog:// destinations without a host.
+	DefaultDatadogSite = "datadoghq.com"
+	// inconclusiveScore is the score of the checks scorecard could not conclude on.
+	inconclusiveScore = -1
+)
+
+var (
+	errDatadogFailed       = errors.New("Datadog rejected the request")
+	errNoDatadogAPIKey     = errors.New("no Datadog API key")
+	errInvalidDatadogSite  = errors.New("invalid Datadog site")
+	errDatadogNoRepository = errors.New("the results do not name their repository")
+)
+
+// datadogSeries is a metric of the Datadog v1 series API.
+type datadogSeries struct {
+	Metric string       `json:"metric"`
+	Type   string       `json:"type"`
+	Points [][2]float64 `json:"points"`
+	Tags   []string     `json:"tags"`
+}
+
+// datadogEvent is an event of the Datadog v1 events API.
+type datadogEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	AlertType      string   `json:"alert_type"`
+	AggregationKey string   `json:"aggregation_key"`
+	SourceTypeName string   `json:"source_type_name"`
+	Tags           []string `json:"tags"`
+}
+
+// datadog sends the scores as Datadog metrics, and an event when checks regressed from the baseline.
+type datadog struct {
+	client  *http.Client
+	now     func() time.Time
+	baseURL string
+	apiKey  string
+}
+
+// newDatadog is a function to create the exporter of a datadog://site destination, e.g. datadog://datadoghq.eu.
+// The site defaults to datadoghq.com.
+func newDatadog(destination *url.URL, options Options) (Exporter, error) {
+	site := destination.Host
+	if site == "" {
+		site = DefaultDatadogSite
+	}
+	if destination.Port() != "" || strings.Trim(destination.Path, "/") != "" {
+		return nil, fmt.Errorf("%w: %s is not datadog://site, e.g. datadog://%s", errInvalidDatadogSite,
+			destination, DefaultDatadogSite)
+	}
+	if options.DatadogAPIKey == "" {
+		return nil, fmt.Errorf("%w: set datadog_api_key", errNoDatadogAPIKey)
+	}
+	return &datadog{client: options.HTTPClient, now: time.Now, baseURL: "https://api." + site,
+		apiKey: options.DatadogAPIKey}, nil
+}
+
+// Export submits the scorecard.score gauge and a scorecard.check.score gauge per check, and an event
+// listing the regressions of the results, if any.
+func (d *datadog) Export(ctx context.Context, results Results) error {
+	var result scorecardResult
+	if err := json.Unmarshal(results.JSON, &result); err != nil {
+		return fmt.Errorf("error unmarshalling the JSON results: %w", err)
+	}
+	repository := results.Repository
+	if repository == "" {
+		repository = strings.TrimPrefix(result.Repo.Name, "github.com/")
+	}
+	if repository == "" {
+		return errDatadogNoRepository
+	}
+	tags := datadogTags(repository)
+	series := map[string]interface{}{"series": datadogSeriesOf(&result, tags, d.now())}
+	if err := d.post(ctx, "/api/v1/series", series); err != nil {
+		return err
+	}
+	if len(results.Regressions) == 0 {
+		return nil
+	}
+	event := datadogRegressionEvent(&result, results.Regressions, repository, results.RunURL, tags)
+	return d.post(ctx, "/api/v1/events", event)
+}
+
+// datadogTags is a function to get the tags of the metrics and events of the repository, so that monitors
+// can be scoped to a repository or grouped by organization.
+func datadogTags(repository string) []string {
+	tags := []string{"repo:" + repository}
+	if owner := strings.SplitN(repository, "/", 2)[0]; owner != "" {
+		tags = append(tags, "org:"+owner)
+	}
+	return tags
+}
+
+// datadogSeriesOf is a function to convert the results to the scorecard.score gauge and a
+// scorecard.check.score gauge per check. Inconclusive checks have no score, so they are left out.
+func datadogSeriesOf(result *scorecardResult, tags []string, now time.Time) []datadogSeries {
+	timestamp := float64(now.Unix())
+	series := []datadogSeries{{
+		Metric: "scorecard.score",
+		Type:   "gauge",
+		Points: [][2]float64{{timestamp, result.Score}},
+		Tags:   tags,
+	}}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Score == inconclusiveScore {
+			continue
+		}
+		checkTags := append(append([]string{}, tags...), "check:"+check.Name)
+		series = append(series, datadogSeries{
+			Metric: "scorecard.check.score",
+			Type:   "gauge",
+			Points: [][2]float64{{timestamp, float64(check.Score)}},
+			Tags:   checkTags,
+		})
+	}
+	return series
+}
+
+// datadogRegressionEvent is a function to describe the checks that regressed from the baseline as an event.
+func datadogRegressionEvent(result *scorecardResult, regressions []Regression, repository, runURL string,
+	tags []string) datadogEvent {
+	var text strings.Builder
+	text.WriteString("%%% \n")
+	for _, r := range regressions {
+		fmt.Fprintf(&text, "- %s: %d → %d\n", r.Check, r.Base, r.Head)
+	}
+	if runURL != "" {
+		fmt.Fprintf(&text, "\n[Workflow run](%s)\n", runURL)
+	}
+	text.WriteString(" %%%")
+	return datadogEvent{
+		Title:          fmt.Sprintf("Scorecard regression in %s: score %.1f", repository, result.Score),
+		Text:           text.String(),
+		AlertType:      "warning",
+		AggregationKey: "scorecard:" + repository,
+		SourceTypeName: "scorecard",
+		Tags:           tags,
+	}
+}
+
+// post is a function to POST a JSON body to the Datadog API.
+func (d *datadog) post(ctx context.Context, path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshalling the Datadog request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending to Datadog: %w", err)
+	}
+	defer resp.Body.Close()
+	if !successful(resp) {
+		return responseError(errDatadogFailed, resp)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDatadog_Export(t *testing.T) {
+	t.Parallel()
+	var series map[string][]datadogSeries
+	var events []datadogEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors": ["Forbidden"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/series":
+			if err := json.NewDecoder(r.Body).Decode(&series); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case "/api/v1/events":
+			var event datadogEvent
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			events = append(events, event)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	e := &datadog{
+		client:  server.Client(),
+		now:     func() time.Time { return time.Unix(1650000000, 0) },
+		baseURL: server.URL,
+		apiKey:  "key",
+	}
+	if err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	tags := []string{"repo:ossf/scorecard-action", "org:ossf"}
+	want := []datadogSeries{
+		{Metric: "scorecard.score", Type: "gauge", Points: [][2]float64{{1650000000, 6.8}}, Tags: tags},
+		{
+			Metric: "scorecard.check.score",
+			Type:   "gauge",
+			Points: [][2]float64{{1650000000, 10}},
+			Tags:   append(append([]string{}, tags...), "check:Binary-Artifacts"),
+		},
+		{
+			Metric: "scorecard.check.score",
+			Type:   "gauge",
+			Points: [][2]float64{{1650000000, 3}},
+			Tags:   append(append([]string{}, tags...), "check:Branch-Protection"),
+		},
+	}
+	if diff := cmp.Diff(want, series["series"]); diff != "" {
+		t.Errorf("Export() series mismatch (-want +got):\n%s", diff)
+	}
+	if len(events) != 0 {
+		t.Errorf("Export() sent %d events without regressions, want 0", len(events))
+	}
+
+	results := Results{
+		JSON:        []byte(bigQueryTestResults),
+		Repository:  "owner/repo",
+		RunURL:      "https://github.com/owner/repo/actions/runs/1",
+		Regressions: []Regression{{Check: "Branch-Protection", Base: 8, Head: 3}},
+	}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Export() sent %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.Title != "Scorecard regression in owner/repo: score 6.8" || got.AlertType != "warning" ||
+		got.AggregationKey != "scorecard:owner/repo" {
+		t.Errorf("Export() event = %+v", got)
+	}
+	if !strings.Contains(got.Text, "- Branch-Protection: 8 → 3\n") || !strings.Contains(got.Text, results.RunURL) {
+		t.Errorf("Export() event text = %q", got.Text)
+	}
+
+	e.apiKey = "wrong"
+	err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)})
+	if !errors.Is(err, errDatadogFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errDatadogFailed)
+	}
+}
+
+func Test_newDatadog(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		destination string
+		want        string
+		wantErr     bool
+	}{
+		{destination: "datadog://", want: "https://api.datadoghq.com"},
+		{destination: "datadog://datadoghq.eu", want: "https://api.datadoghq.eu"},
+		{destination: "datadog://us5.datadoghq.com", want: "https://api.us5.datadoghq.com"},
+		{destination: "datadog://datadoghq.com:443", wantErr: true},
+		{destination: "datadog://datadoghq.com/api", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.destination, func(t *testing.T) {
+			t.Parallel()
+			u, err := url.Parse(tt.destination)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+			got, err := newDatadog(u, Options{HTTPClient: http.DefaultClient, DatadogAPIKey: "key"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newDatadog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, errInvalidDatadogSite) {
+					t.Errorf("newDatadog() error = %v, want %v", err, errInvalidDatadogSite)
+				}
+				return
+			}
+			if d := got.(*datadog); d.baseURL != tt.want {
+				t.Errorf("newDatadog() base URL = %s, want %s", d.baseURL, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newDatadogNoAPIKey(t *testing.T) {
+	t.Parallel()
+	u, err := url.Parse("datadog://datadoghq.com")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	if _, err := newDatadog(u, Options{HTTPClient: http.DefaultClient}); !errors.Is(err, errNoDatadogAPIKey) {
+		t.Errorf("newDatadog() error = %v, want %v", err, errNoDatadogAPIKey)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exporter sends scorecard results to destinations outside the workflow run.
+// Exporters are selected by the scheme of their destination URL, and new destinations are added
+// by registering a Factory for their scheme.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jsonResultsFile is the name the exporters give the JSON results.
+const jsonResultsFile = "results.json"
+
+var (
+	errUnknownScheme      = errors.New("no exporter for the destination scheme")
+	errInvalidDestination = errors.New("invalid export destination")
+	errNoCredentials      = errors.New("no cloud credentials found")
+)
+
+// Results are the results of a scorecard run.
+type Results struct {
+	// Files are the results files, by format.
+	Files map[string]string
+	// JSON are the JSON results.
+	JSON []byte
+	// Repository is the analyzed repository, as owner/name. It defaults to the repository of the JSON results.
+	Repository string
+	// RunURL is the URL of the workflow run that produced the results, if any.
+	RunURL string
+	// Regressions are the checks whose score dropped from the baseline, when one was compared.
+	Regressions []Regression
+}
+
+// Regression is a check whose score dropped from the baseline.
+type Regression struct {
+	Check string
+	Base  int
+	Head  int
+}
+
+// Exporter sends the results to a destination.
+type Exporter interface {
+	Export(ctx context.Context, results Results) error
+}
+
+// Options are the settings shared by the exporters.
+type Options struct {
+	// HTTPClient sends the requests of the exporters. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// WebhookSecret, if set, is the key of the HMAC-SHA256 signature of the webhook requests.
+	WebhookSecret string
+	// SplunkToken is the token of the Splunk HTTP Event Collector.
+	SplunkToken string
+	// DatadogAPIKey is the API key of the Datadog site.
+	DatadogAPIKey string
+}
+
+// Factory creates the exporter of a destination.
+type Factory func(destination *url.URL, options Options) (Exporter, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		"file":        newFilesystem,
+		"http":        newWebhook,
+		"https":       newWebhook,
+		"s3":          newS3,
+		"gs":          newGCS,
+		"az":          newAzureBlob,
+		"bq":          newBigQuery,
+		"securityhub": newSecurityHub,
+		"scc":         newSCC,
+		"splunk":      newSplunk,
+		"datadog":     newDatadog,
+	}
+)
+
+// Register makes the factory create the exporters of the destinations with the scheme,
+// replacing any factory registered for it.
+func Register(scheme string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[strings.ToLower(scheme)] = factory
+}
+
+// Schemes returns the sorted schemes exporters are registered for.
+func Schemes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	schemes := make([]string, 0, len(factories))
+	for scheme := range factories {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// New creates the exporter of the destination URL.
+func New(destination string, options Options) (Exporter, error) {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidDestination, err)
+	}
+	factoriesMu.RLock()
+	factory, ok := factories[strings.ToLower(u.Scheme)]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, want one of %s", errUnknownScheme, destination,
+			strings.Join(Schemes(), ", "))
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	return factory(u, options)
+}
+
+// objects is a function to read the files the exporters copy to their destination, by name:
+// the JSON results and every results file.
+func objects(results Results) (map[string][]byte, error) {
+	files := map[string][]byte{jsonResultsFile: results.JSON}
+	for _, file := range results.Files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file, err)
+		}
+		files[filepath.Base(file)] = data
+	}
+	return files, nil
+}
+
+// objectName is a function to get the name of an uploaded file under the destination prefix.
+func objectName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+// contentType is a function to get the media type of an uploaded file from its extension.
+func contentType(name string) string {
+	switch path.Ext(name) {
+	case ".json", ".sarif":
+		return "application/json"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// responseError is a function to describe an unsuccessful response, keeping the start of its body.
+func responseError(err error, resp *http.Response) error {
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+	return fmt.Errorf("%w: %d %s", err, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// successful is a function to check the response has a 2xx status.
+func successful(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type fakeExporter struct {
+	destination string
+}
+
+func (f *fakeExporter) Export(ctx context.Context, results Results) error {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	Register("fake", func(destination *url.URL, options Options) (Exporter, error) {
+		return &fakeExporter{destination: destination.String()}, nil
+	})
+	//nolint
+	tests := []struct {
+		name        string
+		destination string
+		wantErr     error
+	}{
+		{name: "Filesystem", destination: "file:///tmp/results"},
+		{name: "Webhook", destination: "https://dashboard.example.com/scorecard"},
+		{name: "Registered exporter", destination: "FAKE://destination"},
+		{name: "S3", destination: "s3://bucket/scorecard/repo"},
+		{name: "Cloud Storage", destination: "gs://bucket"},
+		{name: "Azure Blob Storage", destination: "az://account/container/scorecard"},
+		{name: "BigQuery", destination: "bq://project/scorecard/results"},
+		{name: "BigQuery without a table", destination: "bq://project/scorecard", wantErr: errInvalidDestination},
+		{name: "S3 without a bucket", destination: "s3:///scorecard", wantErr: errInvalidDestination},
+		{name: "Azure Blob Storage without a container", destination: "az://account", wantErr: errInvalidDestination},
+		{name: "Unknown scheme", destination: "ftp://example.com/results", wantErr: errUnknownScheme},
+		{name: "Filesystem without a directory", destination: "file://", wantErr: errInvalidDestination},
+		{name: "Invalid URL", destination: "https://exa mple.com", wantErr: errInvalidDestination},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := New(tt.destination, Options{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got == nil {
+				t.Errorf("New() = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// filesystem copies the results to a directory, e.g. one shared with other jobs on a self-hosted runner.
+type filesystem struct {
+	dir string
+}
+
+// newFilesystem is a function to create the exporter of a file:// destination.
+// Both file:///absolute/dir and file://relative/dir are accepted.
+func newFilesystem(destination *url.URL, options Options) (Exporter, error) {
+	dir := destination.Host + destination.Path
+	if dir == "" {
+		return nil, fmt.Errorf("%w: %s has no directory", errInvalidDestination, destination)
+	}
+	return &filesystem{dir: filepath.FromSlash(dir)}, nil
+}
+
+// Export is a function to write the JSON results and copy the results files into the directory.
+func (f *filesystem) Export(ctx context.Context, results Results) error {
+	files, err := objects(results)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating %s: %w", f.dir, err)
+	}
+	for name, data := range files {
+		target := filepath.Join(f.dir, name)
+		if err := ioutil.WriteFile(target, data, 0o600); err != nil {
+			return fmt.Errorf("error writing %s: %w", target, err)
+		}
+	}
+	return nil
+}
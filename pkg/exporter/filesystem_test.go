@@ -0,0 +1,48 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesystem_Export(t *testing.T) {
+	t.Parallel()
+	sarifFile := filepath.Join(t.TempDir(), "results.sarif")
+	if err := ioutil.WriteFile(sarifFile, []byte(`{"runs": []}`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), "exported")
+	e, err := New("file://"+filepath.ToSlash(dir), Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	results := Results{JSON: []byte(`{"score": 7}`), Files: map[string]string{"sarif": sarifFile}}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	for file, want := range map[string]string{"results.json": `{"score": 7}`, "results.sarif": `{"runs": []}`} {
+		got, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("Export() wrote %s = %q, want %q", file, got, want)
+		}
+	}
+}
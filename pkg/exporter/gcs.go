@@ -0,0 +1,107 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// storageEmulatorHost is the address of a Cloud Storage emulator, as honored by the Google client libraries.
+	storageEmulatorHost = "STORAGE_EMULATOR_HOST"
+	defaultGCSEndpoint  = "https://storage.googleapis.com"
+	gcsReadWriteScope   = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+var errGCSUploadFailed = errors.New("Cloud Storage upload failed")
+
+// gcs uploads the results to a Cloud Storage bucket.
+type gcs struct {
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+	endpoint string
+	bucket   string
+	prefix   string
+}
+
+// newGCS is a function to create the exporter of a gs://bucket/prefix destination.
+func newGCS(destination *url.URL, options Options) (Exporter, error) {
+	if destination.Host == "" {
+		return nil, fmt.Errorf("%w: %s has no bucket", errInvalidDestination, destination)
+	}
+	endpoint := defaultGCSEndpoint
+	if host := os.Getenv(storageEmulatorHost); host != "" {
+		endpoint = host
+		if !strings.Contains(host, "://") {
+			endpoint = "http://" + host
+		}
+	}
+	client := options.HTTPClient
+	return &gcs{
+		client: client,
+		token: func(ctx context.Context) (string, error) {
+			return googleAccessToken(ctx, client, gcsReadWriteScope)
+		},
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   destination.Host,
+		prefix:   strings.Trim(destination.Path, "/"),
+	}, nil
+}
+
+// Export is a function to upload the JSON results and the results files under the prefix.
+func (g *gcs) Export(ctx context.Context, results Results) error {
+	files, err := objects(results)
+	if err != nil {
+		return err
+	}
+	token, err := g.token(ctx)
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := g.upload(ctx, token, objectName(g.prefix, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upload is a function to upload a single object with the JSON API's simple upload.
+func (g *gcs) upload(ctx context.Context, token, name string, data []byte) error {
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		g.endpoint, url.PathEscape(g.bucket), url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType(name))
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading gs://%s/%s: %w", g.bucket, name, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return fmt.Errorf("gs://%s/%s: %w", g.bucket, name, responseError(errGCSUploadFailed, resp))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGCS_Export(t *testing.T) {
+	t.Parallel()
+	uploaded := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/upload/storage/v1/b/bucket/o" ||
+			r.URL.Query().Get("uploadType") != "media" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		uploaded[r.URL.Query().Get("name")] = string(body)
+	}))
+	defer server.Close()
+
+	e := &gcs{
+		client:   server.Client(),
+		token:    func(ctx context.Context) (string, error) { return "token", nil },
+		endpoint: server.URL,
+		bucket:   "bucket",
+		prefix:   "ossf/scorecard",
+	}
+	results := Results{JSON: []byte(`{"score": 7}`)}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"ossf/scorecard/results.json": `{"score": 7}`}, uploaded); diff != "" {
+		t.Errorf("Export() uploaded (-want +got):\n%s", diff)
+	}
+
+	e.token = func(ctx context.Context) (string, error) { return "expired", nil }
+	if err := e.Export(context.Background(), results); !errors.Is(err, errGCSUploadFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errGCSUploadFailed)
+	}
+}
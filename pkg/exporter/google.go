@@ -0,0 +1,211 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ossf/scorecard-action/pkg/auth"
+)
+
+const (
+	googleApplicationCredentials = "GOOGLE_APPLICATION_CREDENTIALS"
+	googleOAuthAccessToken       = "GOOGLE_OAUTH_ACCESS_TOKEN" //nolint:gosec
+	googleTokenLifetime          = time.Hour
+	jwtBearerGrantType           = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	cloudPlatformScope           = "https://www.googleapis.com/auth/cloud-platform"
+	tokenExchangeGrantType       = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType              = "urn:ietf:params:oauth:token-type:access_token" //nolint:gosec
+)
+
+var errUnsupportedCredentials = errors.New("unsupported Google credentials")
+
+// googleCredentials is the subset of a Google credentials file the exporters understand:
+// a service account key, or an external account as created by google-github-actions/auth
+// for Workload Identity Federation.
+type googleCredentials struct {
+	CredentialSource struct {
+		Headers map[string]string `json:"headers"`
+		File    string            `json:"file"`
+		URL     string            `json:"url"`
+		Format  struct {
+			Type                  string `json:"type"`
+			SubjectTokenFieldName string `json:"subject_token_field_name"`
+		} `json:"format"`
+	} `json:"credential_source"`
+	Type                           string `json:"type"`
+	ClientEmail                    string `json:"client_email"`
+	PrivateKey                     string `json:"private_key"`
+	TokenURI                       string `json:"token_uri"`
+	Audience                       string `json:"audience"`
+	SubjectTokenType               string `json:"subject_token_type"`
+	TokenURL                       string `json:"token_url"`
+	ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+}
+
+// googleAccessToken is a function to get an OAuth access token for the scope from the runner's
+// ambient Google credentials: GOOGLE_OAUTH_ACCESS_TOKEN, or the credentials file at
+// GOOGLE_APPLICATION_CREDENTIALS.
+func googleAccessToken(ctx context.Context, client *http.Client, scope string) (string, error) {
+	if token := os.Getenv(googleOAuthAccessToken); token != "" {
+		return token, nil
+	}
+	file := os.Getenv(googleApplicationCredentials)
+	if file == "" {
+		return "", fmt.Errorf("%w: set %s or %s", errNoCredentials, googleApplicationCredentials, googleOAuthAccessToken)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", googleApplicationCredentials, err)
+	}
+	var creds googleCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", fmt.Errorf("error unmarshalling %s: %w", file, err)
+	}
+	switch creds.Type {
+	case "service_account":
+		return serviceAccountToken(ctx, client, &creds, scope, time.Now())
+	case "external_account":
+		return externalAccountToken(ctx, client, &creds, scope)
+	default:
+		return "", fmt.Errorf("%w: type %q", errUnsupportedCredentials, creds.Type)
+	}
+}
+
+// serviceAccountToken is a function to exchange a JWT signed with the service account key for an access token.
+func serviceAccountToken(ctx context.Context, client *http.Client, creds *googleCredentials, scope string,
+	now time.Time) (string, error) {
+	key, err := auth.ParsePrivateKey([]byte(creds.PrivateKey))
+	if err != nil {
+		return "", err
+	}
+	assertion, err := auth.SignJWT(key, map[string]interface{}{
+		"iss":   creds.ClientEmail,
+		"scope": scope,
+		"aud":   creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(googleTokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return postTokenForm(ctx, client, creds.TokenURI, url.Values{
+		"grant_type": {jwtBearerGrantType},
+		"assertion":  {assertion},
+	})
+}
+
+// externalAccountToken is a function to exchange the external account's subject token, e.g. a GitHub OIDC
+// token, for a federated access token at Google's STS, then for a service account's access token
+// when the credentials impersonate one.
+func externalAccountToken(ctx context.Context, client *http.Client, creds *googleCredentials,
+	scope string) (string, error) {
+	subjectToken, err := externalSubjectToken(ctx, client, creds)
+	if err != nil {
+		return "", err
+	}
+	stsScope := scope
+	if creds.ServiceAccountImpersonationURL != "" {
+		stsScope = cloudPlatformScope
+	}
+	token, err := postTokenForm(ctx, client, creds.TokenURL, url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"audience":             {creds.Audience},
+		"scope":                {stsScope},
+		"requested_token_type": {accessTokenType},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {creds.SubjectTokenType},
+	})
+	if err != nil || creds.ServiceAccountImpersonationURL == "" {
+		return token, err
+	}
+
+	body, err := json.Marshal(map[string][]string{"scope": {scope}})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, creds.ServiceAccountImpersonationURL,
+		bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	var impersonated struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := doJSON(client, req, &impersonated); err != nil {
+		return "", err
+	}
+	return impersonated.AccessToken, nil
+}
+
+// externalSubjectToken is a function to read the subject token from the credential source: a file,
+// or a URL such as GitHub's OIDC token endpoint.
+func externalSubjectToken(ctx context.Context, client *http.Client, creds *googleCredentials) (string, error) {
+	source := &creds.CredentialSource
+	var data []byte
+	switch {
+	case source.File != "":
+		var err error
+		if data, err = ioutil.ReadFile(source.File); err != nil {
+			return "", fmt.Errorf("error reading the subject token: %w", err)
+		}
+	case source.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+		if err != nil {
+			return "", fmt.Errorf("error creating request: %w", err)
+		}
+		for name, value := range source.Headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("error requesting the subject token: %w", err)
+		}
+		defer resp.Body.Close()
+		if !successful(resp) {
+			return "", responseError(errAuthFailed, resp)
+		}
+		if data, err = ioutil.ReadAll(resp.Body); err != nil {
+			return "", fmt.Errorf("error reading the subject token: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("%w: the credential source has neither a file nor a URL", errUnsupportedCredentials)
+	}
+
+	if source.Format.Type != "json" {
+		return strings.TrimSpace(string(data)), nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("error unmarshalling the subject token: %w", err)
+	}
+	token, ok := fields[source.Format.SubjectTokenFieldName].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: the subject token has no %q field", errUnsupportedCredentials,
+			source.Format.SubjectTokenFieldName)
+	}
+	return token, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_serviceAccountToken(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != jwtBearerGrantType ||
+			strings.Count(r.Form.Get("assertion"), ".") != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token": "service-account-token", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+
+	creds := googleCredentials{
+		Type:        "service_account",
+		ClientEmail: "scorecard@project.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    server.URL,
+	}
+	got, err := serviceAccountToken(context.Background(), server.Client(), &creds, gcsReadWriteScope, time.Now())
+	if err != nil {
+		t.Fatalf("serviceAccountToken() error = %v", err)
+	}
+	if got != "service-account-token" {
+		t.Errorf("serviceAccountToken() = %q, want %q", got, "service-account-token")
+	}
+}
+
+func Test_externalAccountToken(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer request-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"value": "oidc-token"}`))
+	})
+	mux.HandleFunc("/sts", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("subject_token") != "oidc-token" ||
+			r.Form.Get("grant_type") != tokenExchangeGrantType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token": "federated-token"}`))
+	})
+	mux.HandleFunc("/impersonate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer federated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"accessToken": "service-account-token"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("oidc-token\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	//nolint
+	tests := []struct {
+		name        string
+		credentials string
+		want        string
+	}{
+		{
+			name: "URL source with impersonation",
+			credentials: `{"type": "external_account", "token_url": "` + server.URL + `/sts",
+				"service_account_impersonation_url": "` + server.URL + `/impersonate",
+				"credential_source": {"url": "` + server.URL + `/oidc", "headers": {"Authorization": "bearer request-token"},
+				"format": {"type": "json", "subject_token_field_name": "value"}}}`,
+			want: "service-account-token",
+		},
+		{
+			name: "File source",
+			credentials: `{"type": "external_account", "token_url": "` + server.URL + `/sts",
+				"credential_source": {"file": "` + filepath.ToSlash(tokenFile) + `"}}`,
+			want: "federated-token",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var creds googleCredentials
+			if err := json.Unmarshal([]byte(tt.credentials), &creds); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			got, err := externalAccountToken(context.Background(), server.Client(), &creds, gcsReadWriteScope)
+			if err != nil {
+				t.Fatalf("externalAccountToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("externalAccountToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	awsAccessKeyID          = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKey      = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	awsSessionToken         = "AWS_SESSION_TOKEN"     //nolint:gosec
+	awsRegion               = "AWS_REGION"
+	awsDefaultRegion        = "AWS_DEFAULT_REGION"
+	awsRoleARN              = "AWS_ROLE_ARN"
+	awsWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE" //nolint:gosec
+	awsEndpointURLS3        = "AWS_ENDPOINT_URL_S3"
+	defaultAWSRegion        = "us-east-1"
+	// awsOIDCAudience is the audience of the GitHub OIDC tokens exchanged for AWS credentials.
+	awsOIDCAudience    = "sts.amazonaws.com"
+	awsRoleSessionName = "scorecard-action"
+)
+
+var (
+	errS3UploadFailed   = errors.New("S3 upload failed")
+	errAssumeRoleFailed = errors.New("AWS STS AssumeRoleWithWebIdentity failed")
+)
+
+// s3 uploads the results to an S3 bucket, e.g. to collect the results of every repository of an organization.
+type s3 struct {
+	client      *http.Client
+	credentials func(ctx context.Context) (awsCredentials, error)
+	now         func() time.Time
+	// endpoint, if set, is the URL of an S3 compatible service, addressing buckets by path.
+	endpoint string
+	region   string
+	bucket   string
+	prefix   string
+}
+
+// newS3 is a function to create the exporter of an s3://bucket/prefix destination.
+// The region and credentials are read from the same environment variables as the AWS CLI.
+func newS3(destination *url.URL, options Options) (Exporter, error) {
+	if destination.Host == "" {
+		return nil, fmt.Errorf("%w: %s has no bucket", errInvalidDestination, destination)
+	}
+	region := awsRegionFromEnv()
+	client := options.HTTPClient
+	return &s3{
+		client: client,
+		credentials: func(ctx context.Context) (awsCredentials, error) {
+			return awsCredentialsFromEnv(ctx, client, region)
+		},
+		now:      time.Now,
+		endpoint: strings.TrimSuffix(os.Getenv(awsEndpointURLS3), "/"),
+		region:   region,
+		bucket:   destination.Host,
+		prefix:   strings.Trim(destination.Path, "/"),
+	}, nil
+}
+
+// Export is a function to upload the JSON results and the results files under the prefix.
+func (s *s3) Export(ctx context.Context, results Results) error {
+	files, err := objects(results)
+	if err != nil {
+		return err
+	}
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := s.upload(ctx, creds, objectName(s.prefix, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upload is a function to PUT a single object into the bucket.
+func (s *s3) upload(ctx context.Context, creds awsCredentials, key string, data []byte) error {
+	u := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region), Path: "/" + key}
+	if s.endpoint != "" {
+		endpoint, err := url.Parse(s.endpoint)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", awsEndpointURLS3, err)
+		}
+		u = endpoint.ResolveReference(&url.URL{Path: "/" + s.bucket + "/" + key})
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(key))
+	signV4(req, data, creds, s.region, "s3", s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading s3://%s/%s: %w", s.bucket, key, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return fmt.Errorf("s3://%s/%s: %w", s.bucket, key, responseError(errS3UploadFailed, resp))
+	}
+	return nil
+}
+
+// awsRegionFromEnv is a function to get the AWS region from the same environment variables as the AWS CLI.
+func awsRegionFromEnv() string {
+	if region := os.Getenv(awsRegion); region != "" {
+		return region
+	}
+	if region := os.Getenv(awsDefaultRegion); region != "" {
+		return region
+	}
+	return defaultAWSRegion
+}
+
+// awsCredentialsFromEnv is a function to get the runner's ambient AWS credentials: the access keys
+// exported by e.g. aws-actions/configure-aws-credentials or, when only AWS_ROLE_ARN is set,
+// the credentials of the role assumed with a web identity token. The token is read from
+// AWS_WEB_IDENTITY_TOKEN_FILE, or requested from GitHub's OIDC provider.
+func awsCredentialsFromEnv(ctx context.Context, client *http.Client, region string) (awsCredentials, error) {
+	if accessKeyID := os.Getenv(awsAccessKeyID); accessKeyID != "" {
+		return awsCredentials{
+			accessKeyID:     accessKeyID,
+			secretAccessKey: os.Getenv(awsSecretAccessKey),
+			sessionToken:    os.Getenv(awsSessionToken),
+		}, nil
+	}
+	roleARN := os.Getenv(awsRoleARN)
+	if roleARN == "" {
+		return awsCredentials{}, fmt.Errorf("%w: set %s or %s", errNoCredentials, awsAccessKeyID, awsRoleARN)
+	}
+	var token string
+	if file := os.Getenv(awsWebIdentityTokenFile); file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("error reading %s: %w", awsWebIdentityTokenFile, err)
+		}
+		token = strings.TrimSpace(string(data))
+	} else {
+		var err error
+		if token, err = githubOIDCToken(ctx, client, awsOIDCAudience); err != nil {
+			return awsCredentials{}, err
+		}
+	}
+	return assumeRoleWithWebIdentity(ctx, client, fmt.Sprintf("https://sts.%s.amazonaws.com", region), roleARN, token)
+}
+
+// assumeRoleWithWebIdentity is a function to exchange the web identity token for temporary credentials of the role.
+// The request needs no signature, the token is the proof of identity.
+func assumeRoleWithWebIdentity(ctx context.Context, client *http.Client, stsURL, roleARN,
+	token string) (awsCredentials, error) {
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {awsRoleSessionName},
+		"WebIdentityToken": {token},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stsURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("error assuming %s: %w", roleARN, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return awsCredentials{}, responseError(errAssumeRoleFailed, resp)
+	}
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return awsCredentials{}, fmt.Errorf("error decoding the STS response: %w", err)
+	}
+	return awsCredentials{
+		accessKeyID:     result.Credentials.AccessKeyID,
+		secretAccessKey: result.Credentials.SecretAccessKey,
+		sessionToken:    result.Credentials.SessionToken,
+	}, nil
+}
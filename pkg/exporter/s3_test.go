@@ -0,0 +1,144 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestS3_Export(t *testing.T) {
+	t.Parallel()
+	uploaded := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20220415/eu-west-1/s3/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		uploaded[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+	sarifFile := filepath.Join(t.TempDir(), "results.sarif")
+	if err := ioutil.WriteFile(sarifFile, []byte(`{"runs": []}`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	e := &s3{
+		client: server.Client(),
+		credentials: func(ctx context.Context) (awsCredentials, error) {
+			return awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret", sessionToken: "session"}, nil
+		},
+		now:      func() time.Time { return time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC) },
+		endpoint: server.URL,
+		region:   "eu-west-1",
+		bucket:   "bucket",
+		prefix:   "ossf/scorecard",
+	}
+	results := Results{JSON: []byte(`{"score": 7}`), Files: map[string]string{"sarif": sarifFile}}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	want := map[string]string{
+		"/bucket/ossf/scorecard/results.json":  `{"score": 7}`,
+		"/bucket/ossf/scorecard/results.sarif": `{"runs": []}`,
+	}
+	if diff := cmp.Diff(want, uploaded); diff != "" {
+		t.Errorf("Export() uploaded (-want +got):\n%s", diff)
+	}
+
+	e.bucket = "denied"
+	e.credentials = func(ctx context.Context) (awsCredentials, error) {
+		return awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret"}, nil
+	}
+	if err := e.Export(context.Background(), results); !errors.Is(err, errS3UploadFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errS3UploadFailed)
+	}
+}
+
+func Test_assumeRoleWithWebIdentity(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("WebIdentityToken") != "oidc-token" ||
+			r.Form.Get("RoleArn") != "arn:aws:iam::123456789012:role/scorecard" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIA</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer server.Close()
+
+	got, err := assumeRoleWithWebIdentity(context.Background(), server.Client(), server.URL,
+		"arn:aws:iam::123456789012:role/scorecard", "oidc-token")
+	if err != nil {
+		t.Fatalf("assumeRoleWithWebIdentity() error = %v", err)
+	}
+	want := awsCredentials{accessKeyID: "ASIA", secretAccessKey: "secret", sessionToken: "session"}
+	if got != want {
+		t.Errorf("assumeRoleWithWebIdentity() = %+v, want %+v", got, want)
+	}
+
+	_, err = assumeRoleWithWebIdentity(context.Background(), server.Client(), server.URL,
+		"arn:aws:iam::123456789012:role/other", "oidc-token")
+	if !errors.Is(err, errAssumeRoleFailed) {
+		t.Errorf("assumeRoleWithWebIdentity() error = %v, want %v", err, errAssumeRoleFailed)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_awsCredentialsFromEnv(t *testing.T) {
+	for _, name := range []string{awsAccessKeyID, awsSecretAccessKey, awsSessionToken, awsRoleARN} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	if _, err := awsCredentialsFromEnv(context.Background(), http.DefaultClient, "us-east-1"); !errors.Is(err, errNoCredentials) {
+		t.Errorf("awsCredentialsFromEnv() error = %v, want %v", err, errNoCredentials)
+	}
+
+	os.Setenv(awsAccessKeyID, "AKID")
+	os.Setenv(awsSecretAccessKey, "secret")
+	got, err := awsCredentialsFromEnv(context.Background(), http.DefaultClient, "us-east-1")
+	if err != nil {
+		t.Fatalf("awsCredentialsFromEnv() error = %v", err)
+	}
+	if want := (awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret"}); got != want {
+		t.Errorf("awsCredentialsFromEnv() = %+v, want %+v", got, want)
+	}
+}
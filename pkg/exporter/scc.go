@@ -0,0 +1,233 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSCCEndpoint = "https://securitycenter.googleapis.com/v1"
+	// sccSourceName is the display name of the source the findings are written to,
+	// when the destination names no source.
+	sccSourceName = "OpenSSF Scorecard"
+	// sccFindingIDSize is the maximum length of a finding ID.
+	sccFindingIDSize = 32
+)
+
+var errSCCRequestFailed = errors.New("Security Command Center request failed")
+
+// sccFinding is a finding of the Security Command Center v1 API.
+type sccFinding struct {
+	SourceProperties map[string]interface{} `json:"sourceProperties"`
+	State            string                 `json:"state"`
+	ResourceName     string                 `json:"resourceName"`
+	Category         string                 `json:"category"`
+	ExternalURI      string                 `json:"externalUri,omitempty"`
+	EventTime        string                 `json:"eventTime"`
+	Severity         string                 `json:"severity"`
+	FindingClass     string                 `json:"findingClass"`
+	Description      string                 `json:"description"`
+}
+
+// scc writes a finding per check to a Security Command Center source of an organization.
+type scc struct {
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+	now      func() time.Time
+	endpoint string
+	org      string
+	// source is the ID of the source, looked up or created by its display name if empty.
+	source string
+}
+
+// newSCC is a function to create the exporter of a scc://organizations/org-id[/sources/source-id] destination.
+func newSCC(destination *url.URL, options Options) (Exporter, error) {
+	parts := strings.Split(strings.Trim(destination.Path, "/"), "/")
+	valid := destination.Host == "organizations" && parts[0] != "" &&
+		(len(parts) == 1 || len(parts) == 3 && parts[1] == "sources" && parts[2] != "")
+	if !valid {
+		return nil, fmt.Errorf("%w: %s is not scc://organizations/org-id[/sources/source-id]",
+			errInvalidDestination, destination)
+	}
+	client := options.HTTPClient
+	e := &scc{
+		client: client,
+		token: func(ctx context.Context) (string, error) {
+			return googleAccessToken(ctx, client, cloudPlatformScope)
+		},
+		now:      time.Now,
+		endpoint: defaultSCCEndpoint,
+		org:      parts[0],
+	}
+	if len(parts) == 3 {
+		e.source = parts[2]
+	}
+	return e, nil
+}
+
+// Export is a function to create or update the finding of every conclusive check. Failing checks are
+// active findings, and checks with the maximum score inactive ones, so that fixing a check resolves
+// its finding in the console.
+func (s *scc) Export(ctx context.Context, results Results) error {
+	var result scorecardResult
+	if err := json.Unmarshal(results.JSON, &result); err != nil {
+		return fmt.Errorf("error unmarshalling the JSON results: %w", err)
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	source := s.source
+	if source == "" {
+		if source, err = s.findOrCreateSource(ctx, token); err != nil {
+			return err
+		}
+	}
+	now := s.now().UTC().Format(time.RFC3339)
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Score < 0 {
+			continue
+		}
+		state := "ACTIVE"
+		if check.Score == maxCheckScore {
+			state = "INACTIVE"
+		}
+		finding := sccFinding{
+			SourceProperties: map[string]interface{}{
+				"score":             check.Score,
+				"reason":            check.Reason,
+				"commit":            result.Repo.Commit,
+				"scorecard_version": result.Scorecard.Version,
+			},
+			State:        state,
+			ResourceName: "//" + result.Repo.Name,
+			Category:     check.Name,
+			ExternalURI:  check.Documentation.URL,
+			EventTime:    now,
+			Severity:     sccSeverity(check.Score),
+			FindingClass: "MISCONFIGURATION",
+			Description:  findingDescription(check),
+		}
+		name := fmt.Sprintf("organizations/%s/sources/%s/findings/%s", s.org, source,
+			sccFindingID(result.Repo.Name, check.Name))
+		if err := s.request(ctx, token, http.MethodPatch, name, finding, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findOrCreateSource is a function to get the ID of the organization's scorecard source, creating it
+// on the first export.
+func (s *scc) findOrCreateSource(ctx context.Context, token string) (string, error) {
+	parent := "organizations/" + s.org
+	pageToken := ""
+	for {
+		var page struct {
+			NextPageToken string `json:"nextPageToken"`
+			Sources       []struct {
+				Name        string `json:"name"`
+				DisplayName string `json:"displayName"`
+			} `json:"sources"`
+		}
+		path := parent + "/sources?pageSize=1000"
+		if pageToken != "" {
+			path += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+		if err := s.request(ctx, token, http.MethodGet, path, nil, &page); err != nil {
+			return "", err
+		}
+		for _, source := range page.Sources {
+			if source.DisplayName == sccSourceName {
+				return source.Name[strings.LastIndex(source.Name, "/")+1:], nil
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	var created struct {
+		Name string `json:"name"`
+	}
+	source := map[string]string{
+		"displayName": sccSourceName,
+		"description": "Security health checks of open source repositories by OpenSSF Scorecard.",
+	}
+	if err := s.request(ctx, token, http.MethodPost, parent+"/sources", source, &created); err != nil {
+		return "", err
+	}
+	return created.Name[strings.LastIndex(created.Name, "/")+1:], nil
+}
+
+// request is a function to call the Security Command Center API, decoding the response into out if not nil.
+func (s *scc) request(ctx context.Context, token, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("error marshalling request: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+"/"+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Security Command Center: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return responseError(errSCCRequestFailed, resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	return nil
+}
+
+// sccFindingID is a function to derive the ID of a check's finding from the repository and check,
+// so that every run updates the same finding.
+func sccFindingID(repo, check string) string {
+	id := sha256.Sum256([]byte(repo + "\n" + check))
+	return hex.EncodeToString(id[:])[:sccFindingIDSize]
+}
+
+// sccSeverity is a function to map the score of a check to a severity, as for Security Hub.
+// Security Command Center has no informational severity, so passed checks are low.
+func sccSeverity(score int) string {
+	if severity := asffSeverity(score); severity != "INFORMATIONAL" {
+		return severity
+	}
+	return "LOW"
+}
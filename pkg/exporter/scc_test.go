@@ -0,0 +1,161 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSCC_Export(t *testing.T) {
+	t.Parallel()
+	findings := make(map[string]sccFinding)
+	var sources []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/organizations/123/sources":
+			if r.URL.Query().Get("pageToken") == "" {
+				w.Write([]byte(`{"sources": [{"name": "organizations/123/sources/1", "displayName": "Other"}],
+					"nextPageToken": "next"}`))
+				return
+			}
+			w.Write([]byte(`{"sources": [{"name": "organizations/123/sources/2", "displayName": "` +
+				strings.Join(sources, "") + `"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/organizations/123/sources":
+			sources = append(sources, sccSourceName)
+			w.Write([]byte(`{"name": "organizations/123/sources/2"}`))
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/organizations/123/sources/2/findings/"):
+			var finding sccFinding
+			if err := json.NewDecoder(r.Body).Decode(&finding); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			findings[strings.TrimPrefix(r.URL.Path, "/organizations/123/sources/2/findings/")] = finding
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	e := &scc{
+		client:   server.Client(),
+		token:    func(ctx context.Context) (string, error) { return "token", nil },
+		now:      func() time.Time { return time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC) },
+		endpoint: server.URL,
+		org:      "123",
+	}
+	results := Results{JSON: []byte(bigQueryTestResults)}
+	for i := 0; i < 2; i++ {
+		if err := e.Export(context.Background(), results); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+	if len(sources) != 1 {
+		t.Errorf("Export() created %d sources, want 1", len(sources))
+	}
+	if len(findings) != 2 {
+		t.Fatalf("Export() wrote %d findings, want 2", len(findings))
+	}
+	want := sccFinding{
+		SourceProperties: map[string]interface{}{
+			"score":             float64(3),
+			"reason":            "branch protection is not maximal",
+			"commit":            "aa0496aa",
+			"scorecard_version": "v4.1.0",
+		},
+		State:        "ACTIVE",
+		ResourceName: "//github.com/ossf/scorecard-action",
+		Category:     "Branch-Protection",
+		ExternalURI:  "https://example.com/bp",
+		EventTime:    "2022-03-01T00:00:00Z",
+		Severity:     "MEDIUM",
+		FindingClass: "MISCONFIGURATION",
+		Description:  "Score 3/10: branch protection is not maximal\nWarn: number of required reviewers is only 1",
+	}
+	got := findings[sccFindingID("github.com/ossf/scorecard-action", "Branch-Protection")]
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Export() finding (-want +got):\n%s", diff)
+	}
+	passed := findings[sccFindingID("github.com/ossf/scorecard-action", "Binary-Artifacts")]
+	if passed.State != "INACTIVE" || passed.Severity != "LOW" {
+		t.Errorf("Export() finding = %+v, want an inactive low finding", passed)
+	}
+
+	e.source = "3"
+	if err := e.Export(context.Background(), results); !errors.Is(err, errSCCRequestFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errSCCRequestFailed)
+	}
+}
+
+func Test_newSCC(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		destination string
+		wantOrg     string
+		wantSource  string
+		wantErr     bool
+	}{
+		{destination: "scc://organizations/123", wantOrg: "123"},
+		{destination: "scc://organizations/123/sources/456", wantOrg: "123", wantSource: "456"},
+		{destination: "scc://organizations/", wantErr: true},
+		{destination: "scc://projects/123", wantErr: true},
+		{destination: "scc://organizations/123/findings/456", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.destination, func(t *testing.T) {
+			t.Parallel()
+			u, err := url.Parse(tt.destination)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+			got, err := newSCC(u, Options{HTTPClient: http.DefaultClient})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newSCC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if e := got.(*scc); e.org != tt.wantOrg || e.source != tt.wantSource {
+				t.Errorf("newSCC() = %s/%s, want %s/%s", e.org, e.source, tt.wantOrg, tt.wantSource)
+			}
+		})
+	}
+}
+
+func Test_sccFindingID(t *testing.T) {
+	t.Parallel()
+	id := sccFindingID("github.com/ossf/scorecard", "Fuzzing")
+	if len(id) != sccFindingIDSize || id != sccFindingID("github.com/ossf/scorecard", "Fuzzing") {
+		t.Errorf("sccFindingID() = %v, want a stable ID of %d characters", id, sccFindingIDSize)
+	}
+	if id == sccFindingID("github.com/ossf/scorecard", "License") {
+		t.Errorf("sccFindingID() = %v for different checks", id)
+	}
+}
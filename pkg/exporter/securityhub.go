@@ -0,0 +1,242 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	asffSchemaVersion = "2018-10-08"
+	asffFindingType   = "Software and Configuration Checks/Industry and Regulatory Standards/OpenSSF Scorecard"
+	// securityHubBatchSize is the maximum number of findings of a BatchImportFindings request.
+	securityHubBatchSize = 100
+	// findingDescriptionSize is the maximum length of the description of a finding.
+	findingDescriptionSize = 1024
+	maxCheckScore          = 10
+)
+
+var (
+	errSecurityHubImportFailed = errors.New("Security Hub import failed")
+	awsAccountIDPattern        = regexp.MustCompile(`^\d{12}$`)
+)
+
+// asffFinding is a finding in the AWS Security Finding Format.
+type asffFinding struct {
+	SchemaVersion string                 `json:"SchemaVersion"`
+	ID            string                 `json:"Id"`
+	ProductArn    string                 `json:"ProductArn"`
+	GeneratorID   string                 `json:"GeneratorId"`
+	AwsAccountID  string                 `json:"AwsAccountId"`
+	CreatedAt     string                 `json:"CreatedAt"`
+	UpdatedAt     string                 `json:"UpdatedAt"`
+	Title         string                 `json:"Title"`
+	Description   string                 `json:"Description"`
+	SourceURL     string                 `json:"SourceUrl,omitempty"`
+	Severity      map[string]string      `json:"Severity"`
+	Compliance    map[string]string      `json:"Compliance"`
+	ProductFields map[string]string      `json:"ProductFields"`
+	Types         []string               `json:"Types"`
+	Resources     []map[string]string    `json:"Resources"`
+	Remediation   map[string]interface{} `json:"Remediation,omitempty"`
+}
+
+// securityHub imports a finding per check into AWS Security Hub, next to the findings of other scanners.
+type securityHub struct {
+	client      *http.Client
+	credentials func(ctx context.Context) (awsCredentials, error)
+	now         func() time.Time
+	// endpoint, if set, replaces the regional Security Hub endpoint.
+	endpoint string
+	region   string
+	account  string
+}
+
+// newSecurityHub is a function to create the exporter of a securityhub://account-id destination.
+// The region and credentials are read from the same environment variables as the AWS CLI.
+func newSecurityHub(destination *url.URL, options Options) (Exporter, error) {
+	if !awsAccountIDPattern.MatchString(destination.Host) {
+		return nil, fmt.Errorf("%w: %s is not securityhub://account-id", errInvalidDestination, destination)
+	}
+	region := awsRegionFromEnv()
+	client := options.HTTPClient
+	return &securityHub{
+		client: client,
+		credentials: func(ctx context.Context) (awsCredentials, error) {
+			return awsCredentialsFromEnv(ctx, client, region)
+		},
+		now:     time.Now,
+		region:  region,
+		account: destination.Host,
+	}, nil
+}
+
+// Export is a function to import the findings of the checks with BatchImportFindings, in batches.
+func (s *securityHub) Export(ctx context.Context, results Results) error {
+	findings, err := s.findings(results.JSON)
+	if err != nil {
+		return err
+	}
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(findings); start += securityHubBatchSize {
+		end := start + securityHubBatchSize
+		if end > len(findings) {
+			end = len(findings)
+		}
+		if err := s.importFindings(ctx, creds, findings[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findings is a function to convert the JSON results into a finding per check. The findings of a check
+// keep the same ID across runs, so that each run updates them, and checks with the maximum score are
+// reported as passed, so that the findings of fixed checks are resolved.
+func (s *securityHub) findings(data []byte) ([]asffFinding, error) {
+	var result scorecardResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the JSON results: %w", err)
+	}
+	now := s.now().UTC().Format(time.RFC3339)
+	findings := make([]asffFinding, 0, len(result.Checks))
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		finding := asffFinding{
+			SchemaVersion: asffSchemaVersion,
+			ID:            fmt.Sprintf("scorecard/%s/%s", result.Repo.Name, check.Name),
+			ProductArn:    fmt.Sprintf("arn:aws:securityhub:%s:%s:product/%s/default", s.region, s.account, s.account),
+			GeneratorID:   "scorecard/" + check.Name,
+			AwsAccountID:  s.account,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Title:         fmt.Sprintf("Scorecard %s: %s", check.Name, result.Repo.Name),
+			Description:   findingDescription(check),
+			SourceURL:     check.Documentation.URL,
+			Severity:      map[string]string{"Label": asffSeverity(check.Score)},
+			Compliance:    map[string]string{"Status": asffComplianceStatus(check.Score)},
+			ProductFields: map[string]string{
+				"scorecard/check":   check.Name,
+				"scorecard/score":   fmt.Sprint(check.Score),
+				"scorecard/commit":  result.Repo.Commit,
+				"scorecard/version": result.Scorecard.Version,
+			},
+			Types:     []string{asffFindingType},
+			Resources: []map[string]string{{"Type": "Other", "Id": result.Repo.Name}},
+		}
+		if check.Documentation.URL != "" {
+			finding.Remediation = map[string]interface{}{
+				"Recommendation": map[string]string{"Text": check.Documentation.Short, "Url": check.Documentation.URL},
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// findingDescription is a function to describe a check with its score, reason and details,
+// truncated to the size Security Hub accepts.
+func findingDescription(check *checkResult) string {
+	description := fmt.Sprintf("Score %d/10: %s", check.Score, check.Reason)
+	if len(check.Details) > 0 {
+		description += "\n" + strings.Join(check.Details, "\n")
+	}
+	if len(description) > findingDescriptionSize {
+		description = description[:findingDescriptionSize-3] + "..."
+	}
+	return description
+}
+
+// asffSeverity is a function to map the score of a check to a severity: the lower the score, the higher
+// the severity. Passed and inconclusive checks are informational.
+func asffSeverity(score int) string {
+	switch {
+	case score < 0 || score == maxCheckScore:
+		return "INFORMATIONAL"
+	case score < 3:
+		return "HIGH"
+	case score < 6:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+// asffComplianceStatus is a function to map the score of a check to the status of its finding.
+func asffComplianceStatus(score int) string {
+	switch {
+	case score < 0:
+		return "NOT_AVAILABLE"
+	case score == maxCheckScore:
+		return "PASSED"
+	default:
+		return "FAILED"
+	}
+}
+
+// importFindings is a function to send a BatchImportFindings request, failing if any finding is rejected.
+func (s *securityHub) importFindings(ctx context.Context, creds awsCredentials, findings []asffFinding) error {
+	body, err := json.Marshal(map[string]interface{}{"Findings": findings})
+	if err != nil {
+		return fmt.Errorf("error marshalling findings: %w", err)
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://securityhub.%s.amazonaws.com", s.region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/findings/import", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signV4(req, body, creds, s.region, "securityhub", s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error importing findings into Security Hub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return responseError(errSecurityHubImportFailed, resp)
+	}
+	var response struct {
+		FailedFindings []struct {
+			ID           string `json:"Id"`
+			ErrorCode    string `json:"ErrorCode"`
+			ErrorMessage string `json:"ErrorMessage"`
+		} `json:"FailedFindings"`
+		FailedCount int `json:"FailedCount"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	if response.FailedCount > 0 && len(response.FailedFindings) > 0 {
+		first := response.FailedFindings[0]
+		return fmt.Errorf("%w: %d of %d findings rejected, %s: %s %s", errSecurityHubImportFailed,
+			response.FailedCount, len(findings), first.ID, first.ErrorCode, first.ErrorMessage)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSecurityHub_Export(t *testing.T) {
+	t.Parallel()
+	var imported []asffFinding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/findings/import" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"),
+				"AWS4-HMAC-SHA256 Credential=AKID/20220415/eu-west-1/securityhub/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var request struct {
+			Findings []asffFinding
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, finding := range request.Findings {
+			if finding.GeneratorID == "scorecard/Fuzzing" {
+				w.Write([]byte(`{"FailedCount": 1, "FailedFindings": [{"Id": "` + finding.ID +
+					`", "ErrorCode": "InvalidInput", "ErrorMessage": "rejected"}]}`))
+				return
+			}
+		}
+		imported = append(imported, request.Findings...)
+		w.Write([]byte(`{"FailedCount": 0, "FailedFindings": [], "SuccessCount": 1}`))
+	}))
+	defer server.Close()
+
+	e := &securityHub{
+		client: server.Client(),
+		credentials: func(ctx context.Context) (awsCredentials, error) {
+			return awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret"}, nil
+		},
+		now:      func() time.Time { return time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC) },
+		endpoint: server.URL,
+		region:   "eu-west-1",
+		account:  "123456789012",
+	}
+	results := Results{JSON: []byte(`{
+		"repo": {"name": "github.com/ossf/scorecard", "commit": "abc"},
+		"scorecard": {"version": "v4.10.2"},
+		"checks": [
+			{"name": "Pinned-Dependencies", "score": 4, "reason": "dependency not pinned by hash",
+				"details": ["Warn: third-party GitHubAction not pinned by hash: .github/workflows/ci.yml:10"],
+				"documentation": {"short": "Pin dependencies.", "url": "https://example.com/pinned"}},
+			{"name": "License", "score": 10, "reason": "license file detected"}
+		]
+	}`)}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("Export() imported %d findings, want 2", len(imported))
+	}
+	want := asffFinding{
+		SchemaVersion: asffSchemaVersion,
+		ID:            "scorecard/github.com/ossf/scorecard/Pinned-Dependencies",
+		ProductArn:    "arn:aws:securityhub:eu-west-1:123456789012:product/123456789012/default",
+		GeneratorID:   "scorecard/Pinned-Dependencies",
+		AwsAccountID:  "123456789012",
+		CreatedAt:     "2022-04-15T00:00:00Z",
+		UpdatedAt:     "2022-04-15T00:00:00Z",
+		Title:         "Scorecard Pinned-Dependencies: github.com/ossf/scorecard",
+		Description: "Score 4/10: dependency not pinned by hash\n" +
+			"Warn: third-party GitHubAction not pinned by hash: .github/workflows/ci.yml:10",
+		SourceURL:  "https://example.com/pinned",
+		Severity:   map[string]string{"Label": "MEDIUM"},
+		Compliance: map[string]string{"Status": "FAILED"},
+		ProductFields: map[string]string{
+			"scorecard/check":   "Pinned-Dependencies",
+			"scorecard/score":   "4",
+			"scorecard/commit":  "abc",
+			"scorecard/version": "v4.10.2",
+		},
+		Types:     []string{asffFindingType},
+		Resources: []map[string]string{{"Type": "Other", "Id": "github.com/ossf/scorecard"}},
+		Remediation: map[string]interface{}{
+			"Recommendation": map[string]interface{}{"Text": "Pin dependencies.", "Url": "https://example.com/pinned"},
+		},
+	}
+	if diff := cmp.Diff(want, imported[0]); diff != "" {
+		t.Errorf("Export() finding (-want +got):\n%s", diff)
+	}
+	if imported[1].Compliance["Status"] != "PASSED" || imported[1].Severity["Label"] != "INFORMATIONAL" {
+		t.Errorf("Export() finding = %+v, want a passed informational finding", imported[1])
+	}
+
+	results.JSON = []byte(`{"repo": {"name": "github.com/ossf/scorecard"}, "checks": [{"name": "Fuzzing", "score": 0}]}`)
+	if err := e.Export(context.Background(), results); !errors.Is(err, errSecurityHubImportFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errSecurityHubImportFailed)
+	}
+}
+
+func Test_asffSeverity(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		score          int
+		wantSeverity   string
+		wantCompliance string
+	}{
+		{score: -1, wantSeverity: "INFORMATIONAL", wantCompliance: "NOT_AVAILABLE"},
+		{score: 0, wantSeverity: "HIGH", wantCompliance: "FAILED"},
+		{score: 2, wantSeverity: "HIGH", wantCompliance: "FAILED"},
+		{score: 3, wantSeverity: "MEDIUM", wantCompliance: "FAILED"},
+		{score: 6, wantSeverity: "LOW", wantCompliance: "FAILED"},
+		{score: 9, wantSeverity: "LOW", wantCompliance: "FAILED"},
+		{score: 10, wantSeverity: "INFORMATIONAL", wantCompliance: "PASSED"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.wantSeverity, func(t *testing.T) {
+			t.Parallel()
+			if got := asffSeverity(tt.score); got != tt.wantSeverity {
+				t.Errorf("asffSeverity(%d) = %v, want %v", tt.score, got, tt.wantSeverity)
+			}
+			if got := asffComplianceStatus(tt.score); got != tt.wantCompliance {
+				t.Errorf("asffComplianceStatus(%d) = %v, want %v", tt.score, got, tt.wantCompliance)
+			}
+		})
+	}
+}
+
+func Test_newSecurityHub(t *testing.T) {
+	t.Parallel()
+	for _, destination := range []string{"securityhub://12345", "securityhub://"} {
+		u, err := url.Parse(destination)
+		if err != nil {
+			t.Fatalf("url.Parse() error = %v", err)
+		}
+		if _, err := newSecurityHub(u, Options{HTTPClient: http.DefaultClient}); !errors.Is(err, errInvalidDestination) {
+			t.Errorf("newSecurityHub(%s) error = %v, want %v", destination, err, errInvalidDestination)
+		}
+	}
+}
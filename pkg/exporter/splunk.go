@@ -0,0 +1,189 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultSplunkPath = "/services/collector/event"
+	// defaultSplunkBatchSize is the number of events sent in a single request.
+	defaultSplunkBatchSize = 100
+	splunkMaxRetries       = 3
+	// splunkRetryBackoff is the delay before the first retry of a batch, doubled for every further retry.
+	splunkRetryBackoff = time.Second
+	splunkSourceType   = "scorecard:check"
+)
+
+var (
+	errSplunkFailed   = errors.New("Splunk HTTP Event Collector rejected the events")
+	errNoSplunkToken  = errors.New("no Splunk HTTP Event Collector token")
+	errSplunkRetrying = errors.New("Splunk HTTP Event Collector unavailable")
+)
+
+// splunkEvent is an event of the HTTP Event Collector. The event of a check has the same fields as the
+// BigQuery rows, so that searches can be shared.
+type splunkEvent struct {
+	Event      bigQueryRow `json:"event"`
+	Index      string      `json:"index,omitempty"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Time       int64       `json:"time"`
+}
+
+// splunk sends an event per check to a Splunk HTTP Event Collector, e.g. for SIEM searches and alerts.
+type splunk struct {
+	client     *http.Client
+	now        func() time.Time
+	url        string
+	token      string
+	index      string
+	source     string
+	sourceType string
+	batchSize  int
+	maxRetries int
+	backoff    time.Duration
+}
+
+// newSplunk is a function to create the exporter of a splunk://host[:port][/path] destination. The events are
+// sent over HTTPS to the path, /services/collector/event by default, and the index, source and sourcetype
+// query parameters set their metadata.
+func newSplunk(destination *url.URL, options Options) (Exporter, error) {
+	if destination.Host == "" {
+		return nil, fmt.Errorf("%w: %s is not splunk://host[:port][/path]", errInvalidDestination, destination)
+	}
+	if options.SplunkToken == "" {
+		return nil, fmt.Errorf("%w: set splunk_hec_token", errNoSplunkToken)
+	}
+	path := destination.Path
+	if path == "" || path == "/" {
+		path = defaultSplunkPath
+	}
+	query := destination.Query()
+	s := &splunk{
+		client:     options.HTTPClient,
+		now:        time.Now,
+		url:        (&url.URL{Scheme: "https", Host: destination.Host, Path: path}).String(),
+		token:      options.SplunkToken,
+		index:      query.Get("index"),
+		source:     query.Get("source"),
+		sourceType: query.Get("sourcetype"),
+		batchSize:  defaultSplunkBatchSize,
+		maxRetries: splunkMaxRetries,
+		backoff:    splunkRetryBackoff,
+	}
+	if s.source == "" {
+		s.source = "scorecard"
+	}
+	if s.sourceType == "" {
+		s.sourceType = splunkSourceType
+	}
+	return s, nil
+}
+
+// Export is a function to send the events of the checks in batches, retrying the batches the collector
+// could not take.
+func (s *splunk) Export(ctx context.Context, results Results) error {
+	var result scorecardResult
+	if err := json.Unmarshal(results.JSON, &result); err != nil {
+		return fmt.Errorf("error unmarshalling the JSON results: %w", err)
+	}
+	now := s.now().Unix()
+	var batch bytes.Buffer
+	encoder := json.NewEncoder(&batch)
+	events := 0
+	for i := range result.Checks {
+		check := result.Checks[i]
+		if check.Details == nil {
+			check.Details = []string{}
+		}
+		event := splunkEvent{
+			Event: bigQueryRow{
+				Repo:      result.Repo,
+				Scorecard: result.Scorecard,
+				Check:     check,
+				Date:      result.Date,
+				Score:     result.Score,
+			},
+			Index:      s.index,
+			Source:     s.source,
+			SourceType: s.sourceType,
+			Time:       now,
+		}
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("error marshalling event: %w", err)
+		}
+		events++
+		if events == s.batchSize || i == len(result.Checks)-1 {
+			if err := s.sendWithRetries(ctx, batch.Bytes()); err != nil {
+				return err
+			}
+			batch.Reset()
+			events = 0
+		}
+	}
+	return nil
+}
+
+// sendWithRetries is a function to send a batch of events, retrying with an exponential backoff while
+// the collector is unreachable or busy.
+func (s *splunk) sendWithRetries(ctx context.Context, body []byte) error {
+	backoff := s.backoff
+	for attempt := 0; ; attempt++ {
+		err := s.send(ctx, body)
+		if !errors.Is(err, errSplunkRetrying) || attempt == s.maxRetries {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("error waiting to retry: %w", ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// send is a function to send a batch of events, one JSON object after the other, in a single request.
+func (s *splunk) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Splunk "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errSplunkRetrying, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case successful(resp):
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return responseError(errSplunkRetrying, resp)
+	default:
+		return responseError(errSplunkFailed, resp)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSplunk_Export(t *testing.T) {
+	t.Parallel()
+	var requests int
+	var events []splunkEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != defaultSplunkPath || r.Header.Get("Authorization") != "Splunk token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// The collector is busy on the first request.
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"text": "Server is busy", "code": 9}`))
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		for {
+			var event splunkEvent
+			if err := decoder.Decode(&event); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			events = append(events, event)
+		}
+		w.Write([]byte(`{"text": "Success", "code": 0}`))
+	}))
+	defer server.Close()
+
+	e := &splunk{
+		client:     server.Client(),
+		now:        func() time.Time { return time.Unix(1646092800, 0) },
+		url:        server.URL + defaultSplunkPath,
+		token:      "token",
+		index:      "security",
+		source:     "scorecard",
+		sourceType: splunkSourceType,
+		batchSize:  1,
+		maxRetries: 1,
+	}
+	if err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Export() sent %d requests, want 3", requests)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Export() sent %d events, want 2", len(events))
+	}
+	got := events[1]
+	if got.Index != "security" || got.SourceType != splunkSourceType || got.Time != 1646092800 ||
+		got.Event.Check.Name != "Branch-Protection" || got.Event.Score != 6.8 ||
+		got.Event.Repo.Name != "github.com/ossf/scorecard-action" {
+		t.Errorf("Export() event = %+v", got)
+	}
+
+	requests = 0
+	e.token = "wrong"
+	err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)})
+	if !errors.Is(err, errSplunkFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errSplunkFailed)
+	}
+	if requests != 1 {
+		t.Errorf("Export() sent %d requests, want the rejected request not to be retried", requests)
+	}
+}
+
+func TestSplunk_ExportRetries(t *testing.T) {
+	t.Parallel()
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e := &splunk{client: server.Client(), now: time.Now, url: server.URL, token: "token", batchSize: 10, maxRetries: 2}
+	err := e.Export(context.Background(), Results{JSON: []byte(bigQueryTestResults)})
+	if !errors.Is(err, errSplunkRetrying) {
+		t.Errorf("Export() error = %v, want %v", err, errSplunkRetrying)
+	}
+	if requests != 3 {
+		t.Errorf("Export() sent %d requests, want 3", requests)
+	}
+}
+
+func Test_newSplunk(t *testing.T) {
+	t.Parallel()
+	u, err := url.Parse("splunk://splunk.example.com:8088?index=security")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	if _, err := newSplunk(u, Options{HTTPClient: http.DefaultClient}); !errors.Is(err, errNoSplunkToken) {
+		t.Errorf("newSplunk() error = %v, want %v", err, errNoSplunkToken)
+	}
+	got, err := newSplunk(u, Options{HTTPClient: http.DefaultClient, SplunkToken: "token"})
+	if err != nil {
+		t.Fatalf("newSplunk() error = %v", err)
+	}
+	s := got.(*splunk)
+	if s.url != "https://splunk.example.com:8088/services/collector/event" || s.index != "security" ||
+		s.source != "scorecard" || s.sourceType != splunkSourceType {
+		t.Errorf("newSplunk() = %+v", s)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	actionsIDTokenRequestURL   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	actionsIDTokenRequestToken = "ACTIONS_ID_TOKEN_REQUEST_TOKEN" //nolint:gosec
+)
+
+var (
+	errNoOIDC     = errors.New("no GitHub OIDC token available, the workflow needs the id-token: write permission")
+	errOIDCFailed = errors.New("GitHub OIDC token request failed")
+	errAuthFailed = errors.New("cloud authentication failed")
+)
+
+// githubOIDCToken is a function to request a GitHub Actions OIDC token for the audience,
+// to exchange it for the credentials of a cloud provider trusting the repository's workflows.
+func githubOIDCToken(ctx context.Context, client *http.Client, audience string) (string, error) {
+	requestURL, requestToken := os.Getenv(actionsIDTokenRequestURL), os.Getenv(actionsIDTokenRequestToken)
+	if requestURL == "" || requestToken == "" {
+		return "", errNoOIDC
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s: %w", actionsIDTokenRequestURL, err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+requestToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting the GitHub OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return "", responseError(errOIDCFailed, resp)
+	}
+	var token struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding the GitHub OIDC token: %w", err)
+	}
+	return token.Value, nil
+}
+
+// postTokenForm is a function to POST an OAuth token request and get the access token of the response.
+func postTokenForm(ctx context.Context, client *http.Client, tokenURL string, form url.Values) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(client, req, &token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// doJSON is a function to send a token request and decode its JSON response.
+func doJSON(client *http.Client, req *http.Request, result interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return responseError(errAuthFailed, resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// maxErrorMessageSize caps how much of an error response is kept in the error message.
+	maxErrorMessageSize = 1024
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the request body,
+	// in the format of GitHub's webhooks: sha256=<hex digest>.
+	SignatureHeader = "X-Scorecard-Signature-256"
+)
+
+var errWebhookFailed = errors.New("webhook rejected the results")
+
+// webhook POSTs the JSON results to a URL, e.g. of an internal dashboard.
+type webhook struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+// newWebhook is a function to create the exporter of an http:// or https:// destination.
+func newWebhook(destination *url.URL, options Options) (Exporter, error) {
+	return &webhook{client: options.HTTPClient, url: destination.String(), secret: options.WebhookSecret}, nil
+}
+
+// Export is a function to POST the JSON results to the URL.
+func (w *webhook) Export(ctx context.Context, results Results) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(results.JSON))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, Signature(w.secret, results.JSON))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending the results to the webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp) {
+		return responseError(errWebhookFailed, resp)
+	}
+	return nil
+}
+
+// Signature is a function to compute the signature header value of a webhook request body,
+// so that receivers can check the results come from a workflow knowing the secret.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
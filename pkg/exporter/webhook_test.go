@@ -0,0 +1,81 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook_Export(t *testing.T) {
+	t.Parallel()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Path == "/reject" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	results := Results{JSON: []byte(`{"score": 7}`)}
+
+	e, err := New(server.URL+"/ingest", Options{HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := e.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if string(body) != `{"score": 7}` {
+		t.Errorf("Export() sent %q", body)
+	}
+
+	e, err = New(server.URL+"/reject", Options{HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := e.Export(context.Background(), results); !errors.Is(err, errWebhookFailed) {
+		t.Errorf("Export() error = %v, want %v", err, errWebhookFailed)
+	}
+}
+
+func TestWebhook_Export_signature(t *testing.T) {
+	t.Parallel()
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+	}))
+	defer server.Close()
+
+	e, err := New(server.URL, Options{HTTPClient: server.Client(), WebhookSecret: "secret"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := e.Export(context.Background(), Results{JSON: []byte(`{"score": 7}`)}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	// printf '{"score": 7}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=4134737a0170a2b60db0617b4ef14774762e8dc06a33935ee3e62e0773f4261c"
+	if signature != want {
+		t.Errorf("Export() signature = %q, want %q", signature, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import "fmt"
+
+// message is the content of a notification, built once from the summary and rendered by every notifier
+// in its own format.
+type message struct {
+	repository string
+	runURL     string
+	score      string
+	// baseline is the score of the baseline, if any.
+	baseline string
+	// regressionStatus tells whether checks regressed, when a baseline was compared.
+	regressionStatus string
+	regressions      []string
+	// failure tells the results failed the policies, if they did.
+	failure string
+}
+
+// newMessage is a function to build the content of the notification of the summary.
+func newMessage(summary Summary) message {
+	m := message{
+		repository: summary.Repository,
+		runURL:     summary.RunURL,
+		score:      fmt.Sprintf("%.1f", summary.Score),
+	}
+	if summary.HasBaseline {
+		m.baseline = fmt.Sprintf("%.1f", summary.BaseScore)
+		m.regressionStatus = "No check regressed."
+	}
+	if len(summary.Regressions) > 0 {
+		m.regressionStatus = fmt.Sprintf("%d check(s) regressed:", len(summary.Regressions))
+	}
+	for _, r := range summary.Regressions {
+		m.regressions = append(m.regressions, fmt.Sprintf("%s: %d → %d", r.Check, r.Base, r.Head))
+	}
+	if summary.Failed {
+		m.failure = "The results failed the policies."
+	}
+	return m
+}
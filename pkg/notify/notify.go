@@ -0,0 +1,148 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify sends a summary of the scorecard results to the chat services teams follow,
+// so that score regressions are noticed without looking at the workflow runs.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// When to notify, as set by the notify_on input.
+const (
+	// OnAlways notifies of every run.
+	OnAlways = "always"
+	// OnRegression notifies when a check scores lower than in the baseline.
+	OnRegression = "regression"
+	// OnFailure notifies when the results fail the policies.
+	OnFailure = "failure"
+)
+
+// The chat services notifications are sent to, as set by the notifier input.
+const (
+	// NotifierSlack posts Slack messages to an incoming webhook.
+	NotifierSlack = "slack"
+	// NotifierTeams posts Adaptive Cards to a Microsoft Teams incoming webhook.
+	NotifierTeams = "teams"
+	// NotifierWebhook posts the summary as JSON, for any other service.
+	NotifierWebhook = "webhook"
+)
+
+// maxErrorMessageSize caps how much of an error response is kept in the error message.
+const maxErrorMessageSize = 1024
+
+var (
+	errInvalidNotifyOn = errors.New("invalid notify_on value")
+	errUnknownNotifier = errors.New("unknown notifier")
+	errNotifyFailed    = errors.New("the chat service rejected the notification")
+)
+
+// Regression is a check whose score dropped from the baseline.
+type Regression struct {
+	Check string
+	Base  int
+	Head  int
+}
+
+// Summary is the outcome of a run the notifications describe.
+type Summary struct {
+	Repository string
+	// RunURL links to the workflow run.
+	RunURL      string
+	Regressions []Regression
+	Score       float64
+	// BaseScore is the score of the baseline, if HasBaseline.
+	BaseScore   float64
+	HasBaseline bool
+	// Failed is whether the results failed the policies.
+	Failed bool
+}
+
+// Notifier sends the summary of a run.
+type Notifier interface {
+	Notify(ctx context.Context, summary Summary) error
+}
+
+// New creates the notifier of the kind posting to the webhook URL.
+func New(kind, webhookURL string, client *http.Client) (Notifier, error) {
+	switch kind {
+	case NotifierSlack:
+		return NewSlack(webhookURL, client), nil
+	case NotifierTeams:
+		return NewTeams(webhookURL, client), nil
+	case NotifierWebhook:
+		return NewWebhook(webhookURL, client), nil
+	default:
+		return nil, fmt.Errorf("%w: %q, want one of %s", errUnknownNotifier, kind,
+			strings.Join([]string{NotifierSlack, NotifierTeams, NotifierWebhook}, ", "))
+	}
+}
+
+// ValidateOn checks the notify_on value is one of OnAlways, OnRegression or OnFailure.
+func ValidateOn(on string) error {
+	switch on {
+	case OnAlways, OnRegression, OnFailure:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, want one of %s", errInvalidNotifyOn, on,
+			strings.Join([]string{OnAlways, OnRegression, OnFailure}, ", "))
+	}
+}
+
+// ShouldNotify reports whether the run warrants a notification.
+func ShouldNotify(on string, summary Summary) bool {
+	switch on {
+	case OnAlways:
+		return true
+	case OnRegression:
+		return len(summary.Regressions) > 0
+	case OnFailure:
+		return summary.Failed
+	default:
+		return false
+	}
+}
+
+// postJSON is a function to POST the JSON payload of a notification to a webhook.
+func postJSON(ctx context.Context, client *http.Client, webhookURL string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshalling the notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending the notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+		return fmt.Errorf("%w: %d %s", errNotifyFailed, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
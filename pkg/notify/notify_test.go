@@ -0,0 +1,72 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	for _, kind := range []string{NotifierSlack, NotifierTeams, NotifierWebhook} {
+		if _, err := New(kind, "https://example.com/hook", http.DefaultClient); err != nil {
+			t.Errorf("New(%q) error = %v", kind, err)
+		}
+	}
+	if _, err := New("irc", "https://example.com/hook", http.DefaultClient); !errors.Is(err, errUnknownNotifier) {
+		t.Errorf("New() error = %v, want %v", err, errUnknownNotifier)
+	}
+}
+
+func TestValidateOn(t *testing.T) {
+	t.Parallel()
+	for _, on := range []string{OnAlways, OnRegression, OnFailure} {
+		if err := ValidateOn(on); err != nil {
+			t.Errorf("ValidateOn(%q) error = %v", on, err)
+		}
+	}
+	if err := ValidateOn("sometimes"); !errors.Is(err, errInvalidNotifyOn) {
+		t.Errorf("ValidateOn() error = %v, want %v", err, errInvalidNotifyOn)
+	}
+}
+
+func TestShouldNotify(t *testing.T) {
+	t.Parallel()
+	regressed := Summary{Regressions: []Regression{{Check: "Branch-Protection", Base: 8, Head: 3}}}
+	failed := Summary{Failed: true}
+	//nolint
+	tests := []struct {
+		name    string
+		on      string
+		summary Summary
+		want    bool
+	}{
+		{name: "Always", on: OnAlways, summary: Summary{}, want: true},
+		{name: "Regression", on: OnRegression, summary: regressed, want: true},
+		{name: "No regression", on: OnRegression, summary: failed, want: false},
+		{name: "Failure", on: OnFailure, summary: failed, want: true},
+		{name: "No failure", on: OnFailure, summary: regressed, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ShouldNotify(tt.on, tt.summary); got != tt.want {
+				t.Errorf("ShouldNotify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
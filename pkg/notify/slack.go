@@ -0,0 +1,64 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Slack posts the summary to a Slack incoming webhook.
+type Slack struct {
+	client *http.Client
+	url    string
+}
+
+// NewSlack creates the notifier of the Slack incoming webhook URL.
+func NewSlack(webhookURL string, client *http.Client) *Slack {
+	return &Slack{client: client, url: webhookURL}
+}
+
+// Notify posts the summary as a Slack message.
+func (s *Slack) Notify(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, s.client, s.url, map[string]string{"text": slackMessage(newMessage(summary))})
+}
+
+// slackMessage is a function to format the message in Slack's mrkdwn.
+func slackMessage(m message) string {
+	var b strings.Builder
+	repository := m.repository
+	if m.runURL != "" {
+		repository = fmt.Sprintf("<%s|%s>", m.runURL, m.repository)
+	}
+	fmt.Fprintf(&b, "*Scorecard* results for %s: score *%s* / 10", repository, m.score)
+	if m.baseline != "" {
+		fmt.Fprintf(&b, " (baseline %s)", m.baseline)
+	}
+	b.WriteString("\n")
+	switch {
+	case len(m.regressions) > 0:
+		fmt.Fprintf(&b, ":warning: %s\n", m.regressionStatus)
+		for _, r := range m.regressions {
+			fmt.Fprintf(&b, "• %s\n", r)
+		}
+	case m.regressionStatus != "":
+		fmt.Fprintf(&b, ":white_check_mark: %s\n", m.regressionStatus)
+	}
+	if m.failure != "" {
+		fmt.Fprintf(&b, ":x: %s\n", m.failure)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
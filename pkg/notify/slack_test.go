@@ -0,0 +1,101 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSlack_Notify(t *testing.T) {
+	t.Parallel()
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/T000/B000/XXXX" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("no_service"))
+			return
+		}
+		var body struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		text = body.Text
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	summary := Summary{Repository: "ossf/scorecard-action", Score: 6.8}
+
+	if err := NewSlack(server.URL+"/services/T000/B000/XXXX", server.Client()).Notify(context.Background(), summary); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if want := "*Scorecard* results for ossf/scorecard-action: score *6.8* / 10"; text != want {
+		t.Errorf("Notify() posted %q, want %q", text, want)
+	}
+
+	err := NewSlack(server.URL+"/services/revoked", server.Client()).Notify(context.Background(), summary)
+	if !errors.Is(err, errNotifyFailed) {
+		t.Errorf("Notify() error = %v, want %v", err, errNotifyFailed)
+	}
+}
+
+func Test_slackMessage(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		summary Summary
+		want    string
+	}{
+		{
+			name: "Regressions",
+			summary: Summary{
+				Repository:  "ossf/scorecard-action",
+				RunURL:      "https://github.com/ossf/scorecard-action/actions/runs/1",
+				Score:       6.1,
+				BaseScore:   6.8,
+				HasBaseline: true,
+				Regressions: []Regression{{Check: "Branch-Protection", Base: 8, Head: 3}},
+				Failed:      true,
+			},
+			want: "*Scorecard* results for <https://github.com/ossf/scorecard-action/actions/runs/1|ossf/scorecard-action>: " +
+				"score *6.1* / 10 (baseline 6.8)\n" +
+				":warning: 1 check(s) regressed:\n" +
+				"• Branch-Protection: 8 → 3\n" +
+				":x: The results failed the policies.",
+		},
+		{
+			name:    "No regression",
+			summary: Summary{Repository: "ossf/scorecard-action", Score: 6.8, BaseScore: 6.8, HasBaseline: true},
+			want: "*Scorecard* results for ossf/scorecard-action: score *6.8* / 10 (baseline 6.8)\n" +
+				":white_check_mark: No check regressed.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := slackMessage(newMessage(tt.summary)); got != tt.want {
+				t.Errorf("slackMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// Teams posts the summary to a Microsoft Teams incoming webhook, as an Adaptive Card.
+type Teams struct {
+	client *http.Client
+	url    string
+}
+
+// NewTeams creates the notifier of the Teams incoming webhook URL.
+func NewTeams(webhookURL string, client *http.Client) *Teams {
+	return &Teams{client: client, url: webhookURL}
+}
+
+// Notify posts the summary as an Adaptive Card.
+func (t *Teams) Notify(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, t.client, t.url, map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": adaptiveCardContentType,
+				"content":     adaptiveCard(newMessage(summary)),
+			},
+		},
+	})
+}
+
+// adaptiveCard is a function to render the message as an Adaptive Card.
+func adaptiveCard(m message) map[string]interface{} {
+	facts := []map[string]string{{"title": "Score", "value": m.score + " / 10"}}
+	if m.baseline != "" {
+		facts = append(facts, map[string]string{"title": "Baseline", "value": m.baseline + " / 10"})
+	}
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "wrap": true,
+			"text": "Scorecard results for " + m.repository},
+		{"type": "FactSet", "facts": facts},
+	}
+	if m.regressionStatus != "" {
+		color := "Good"
+		if len(m.regressions) > 0 {
+			color = "Warning"
+		}
+		body = append(body, map[string]interface{}{"type": "TextBlock", "wrap": true, "color": color,
+			"text": m.regressionStatus})
+	}
+	for _, r := range m.regressions {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "wrap": true, "spacing": "None",
+			"text": "- " + r})
+	}
+	if m.failure != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "wrap": true, "color": "Attention",
+			"text": m.failure})
+	}
+
+	card := map[string]interface{}{
+		"$schema": adaptiveCardSchema,
+		"type":    "AdaptiveCard",
+		"version": adaptiveCardVersion,
+		"body":    body,
+	}
+	if m.runURL != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "View run", "url": m.runURL}}
+	}
+	return card
+}
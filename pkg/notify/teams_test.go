@@ -0,0 +1,90 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTeams_Notify(t *testing.T) {
+	t.Parallel()
+	var card struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string `json:"type"`
+					Text  string `json:"text"`
+					Color string `json:"color"`
+					Facts []struct {
+						Title string `json:"title"`
+						Value string `json:"value"`
+					} `json:"facts"`
+				} `json:"body"`
+				Actions []struct {
+					URL string `json:"url"`
+				} `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	summary := Summary{
+		Repository:  "ossf/scorecard-action",
+		RunURL:      "https://github.com/ossf/scorecard-action/actions/runs/1",
+		Score:       6.1,
+		BaseScore:   6.8,
+		HasBaseline: true,
+		Regressions: []Regression{{Check: "Branch-Protection", Base: 8, Head: 3}},
+	}
+	if err := NewTeams(server.URL, server.Client()).Notify(context.Background(), summary); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if card.Type != "message" || len(card.Attachments) != 1 ||
+		card.Attachments[0].ContentType != adaptiveCardContentType {
+		t.Fatalf("Notify() posted %+v, want a message with an Adaptive Card", card)
+	}
+	content := card.Attachments[0].Content
+	var texts []string
+	for _, element := range content.Body {
+		if element.Type == "TextBlock" {
+			texts = append(texts, element.Text)
+		}
+	}
+	want := []string{"Scorecard results for ossf/scorecard-action", "1 check(s) regressed:", "- Branch-Protection: 8 → 3"}
+	if diff := cmp.Diff(want, texts); diff != "" {
+		t.Errorf("Notify() card text (-want +got):\n%s", diff)
+	}
+	if len(content.Body) < 2 || len(content.Body[1].Facts) != 2 || content.Body[1].Facts[1].Value != "6.8 / 10" {
+		t.Errorf("Notify() card facts = %+v, want the score and the baseline", content.Body)
+	}
+	if len(content.Actions) != 1 || content.Actions[0].URL != summary.RunURL {
+		t.Errorf("Notify() card actions = %+v, want a link to the run", content.Actions)
+	}
+}
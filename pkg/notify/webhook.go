@@ -0,0 +1,89 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Webhook posts the summary as JSON, for chat services and bots the other notifiers do not support.
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// webhookRegression is a regressed check in the JSON payload.
+type webhookRegression struct {
+	Check string `json:"check"`
+	Base  int    `json:"base"`
+	Head  int    `json:"head"`
+}
+
+// webhookPayload is the JSON payload of the generic webhook: the summary, and its plain text rendering.
+type webhookPayload struct {
+	BaseScore   *float64            `json:"base_score,omitempty"`
+	Repository  string              `json:"repository"`
+	RunURL      string              `json:"run_url,omitempty"`
+	Text        string              `json:"text"`
+	Regressions []webhookRegression `json:"regressions"`
+	Score       float64             `json:"score"`
+	Failed      bool                `json:"failed"`
+}
+
+// NewWebhook creates the notifier of the webhook URL.
+func NewWebhook(webhookURL string, client *http.Client) *Webhook {
+	return &Webhook{client: client, url: webhookURL}
+}
+
+// Notify posts the summary as JSON.
+func (w *Webhook) Notify(ctx context.Context, summary Summary) error {
+	payload := webhookPayload{
+		Repository:  summary.Repository,
+		RunURL:      summary.RunURL,
+		Text:        plainText(newMessage(summary)),
+		Regressions: []webhookRegression{},
+		Score:       summary.Score,
+		Failed:      summary.Failed,
+	}
+	if summary.HasBaseline {
+		payload.BaseScore = &summary.BaseScore
+	}
+	for _, r := range summary.Regressions {
+		payload.Regressions = append(payload.Regressions, webhookRegression{Check: r.Check, Base: r.Base, Head: r.Head})
+	}
+	return postJSON(ctx, w.client, w.url, payload)
+}
+
+// plainText is a function to render the message as plain text.
+func plainText(m message) string {
+	lines := []string{"Scorecard results for " + m.repository + ": score " + m.score + " / 10"}
+	if m.baseline != "" {
+		lines[0] += " (baseline " + m.baseline + ")"
+	}
+	if m.regressionStatus != "" {
+		lines = append(lines, m.regressionStatus)
+	}
+	for _, r := range m.regressions {
+		lines = append(lines, "- "+r)
+	}
+	if m.failure != "" {
+		lines = append(lines, m.failure)
+	}
+	if m.runURL != "" {
+		lines = append(lines, m.runURL)
+	}
+	return strings.Join(lines, "\n")
+}
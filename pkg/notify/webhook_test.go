@@ -0,0 +1,73 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	t.Parallel()
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	summary := Summary{
+		Repository:  "ossf/scorecard-action",
+		Score:       6.1,
+		BaseScore:   6.8,
+		HasBaseline: true,
+		Regressions: []Regression{{Check: "Branch-Protection", Base: 8, Head: 3}},
+		Failed:      true,
+	}
+	if err := NewWebhook(server.URL, server.Client()).Notify(context.Background(), summary); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"repository": "ossf/scorecard-action",
+		"score":      6.1,
+		"base_score": 6.8,
+		"failed":     true,
+		"regressions": []interface{}{
+			map[string]interface{}{"check": "Branch-Protection", "base": 8.0, "head": 3.0},
+		},
+		"text": "Scorecard results for ossf/scorecard-action: score 6.1 / 10 (baseline 6.8)\n" +
+			"1 check(s) regressed:\n" +
+			"- Branch-Protection: 8 → 3\n" +
+			"The results failed the policies.",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Notify() posted (-want +got):\n%s", diff)
+	}
+
+	err := NewWebhook(server.URL+"/gone", server.Client()).Notify(context.Background(), summary)
+	if !errors.Is(err, errNotifyFailed) {
+		t.Errorf("Notify() error = %v, want %v", err, errNotifyFailed)
+	}
+}
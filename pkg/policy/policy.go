@@ -0,0 +1,109 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy evaluates scorecard results against score policies, a minimum score per check in the format
+// of scorecard's policy files, and against Rego policies evaluated by the OPA CLI.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// InconclusiveScore is the score of the checks scorecard could not conclude on.
+	InconclusiveScore = -1
+	// ModeEnforced is the mode of the checks whose minimum score is enforced, the default.
+	ModeEnforced = "enforced"
+	// ModeDisabled is the mode of the checks whose minimum score is not enforced.
+	ModeDisabled  = "disabled"
+	scoreVersion  = 1
+	maxCheckScore = 10
+)
+
+// ErrInvalidScorePolicy is returned for score policy files that cannot be evaluated.
+var ErrInvalidScorePolicy = errors.New("invalid score policy")
+
+// ScorePolicy is a minimum score per check, in the format of scorecard's policy files.
+type ScorePolicy struct {
+	Policies map[string]CheckPolicy `yaml:"policies"`
+	Version  int                    `yaml:"version"`
+}
+
+// CheckPolicy is the minimum score of a single check.
+type CheckPolicy struct {
+	Mode  string `yaml:"mode"`
+	Score int    `yaml:"score"`
+}
+
+// Check is the score of a check of the results.
+type Check struct {
+	Name  string
+	Score int
+}
+
+// Violation is a check whose score does not meet its minimum score.
+type Violation struct {
+	Check   string
+	Score   int
+	Minimum int
+}
+
+// ReadScorePolicy reads and validates a score policy file.
+func ReadScorePolicy(path string) (*ScorePolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var policy ScorePolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", path, err)
+	}
+	if policy.Version != scoreVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", ErrInvalidScorePolicy, path, policy.Version)
+	}
+	for check, p := range policy.Policies {
+		if p.Mode != "" && p.Mode != ModeEnforced && p.Mode != ModeDisabled {
+			return nil, fmt.Errorf("%w: %s: invalid mode %q for %s", ErrInvalidScorePolicy, path, p.Mode, check)
+		}
+		if p.Score < 0 || p.Score > maxCheckScore {
+			return nil, fmt.Errorf("%w: %s: invalid score %d for %s", ErrInvalidScorePolicy, path, p.Score, check)
+		}
+	}
+	return &policy, nil
+}
+
+// Violations returns the checks that do not meet their enforced minimum score, sorted by name.
+// Checks missing from the results, e.g. because they do not run on pull requests, are ignored.
+// Inconclusive checks cannot show they meet the minimum and are reported as violations.
+func (p *ScorePolicy) Violations(checks []Check) []Violation {
+	var violations []Violation
+	for _, check := range checks {
+		policy, ok := p.Policies[check.Name]
+		if !ok || policy.Mode == ModeDisabled {
+			continue
+		}
+		if check.Score == InconclusiveScore || check.Score < policy.Score {
+			violations = append(violations, Violation{Check: check.Name, Score: check.Score, Minimum: policy.Score})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Check < violations[j].Check
+	})
+	return violations
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadScorePolicy(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name: "Success - policy file",
+			path: "../../testdata/score-policy.yml",
+		},
+		{
+			name: "Success - scorecard policy template",
+			path: "../../policies/template.yml",
+		},
+		{
+			name:    "Failure - invalid score",
+			path:    "../../testdata/invalid-score-policy.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - non-existent file",
+			path:    "../../testdata/foo.bar.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - not a policy",
+			path:    "../../testdata/results.sarif",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ReadScorePolicy(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("ReadScorePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScorePolicy_Violations(t *testing.T) {
+	t.Parallel()
+	scorePolicy := &ScorePolicy{
+		Version: scoreVersion,
+		Policies: map[string]CheckPolicy{
+			"Branch-Protection":   {Score: 8},
+			"CII-Best-Practices":  {Mode: ModeEnforced, Score: 5},
+			"Pinned-Dependencies": {Mode: ModeDisabled, Score: 10},
+			"License":             {Score: 10},
+			"Token-Permissions":   {Score: 10},
+		},
+	}
+	checks := []Check{
+		{Name: "License", Score: 10},
+		{Name: "CII-Best-Practices", Score: InconclusiveScore},
+		{Name: "Branch-Protection", Score: 3},
+		{Name: "Pinned-Dependencies", Score: 0},
+		{Name: "Fuzzing", Score: 0},
+	}
+	// Pinned-Dependencies is disabled, Fuzzing has no policy and Token-Permissions is not in the results.
+	want := []Violation{
+		{Check: "Branch-Protection", Score: 3, Minimum: 8},
+		{Check: "CII-Best-Practices", Score: InconclusiveScore, Minimum: 5},
+	}
+	if diff := cmp.Diff(want, scorePolicy.Violations(checks)); diff != "" {
+		t.Errorf("Violations() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ErrUnexpectedRegoResult is returned when the Rego query does not evaluate to a set of deny messages.
+var ErrUnexpectedRegoResult = errors.New("Rego query must evaluate to a set of deny messages")
+
+// RegoResult is the outcome of evaluating a single Rego policy file.
+type RegoResult struct {
+	Policy  string
+	Denials []string
+}
+
+// EvaluateRego evaluates a Rego policy file with the JSON results file as input, using the OPA CLI at opaBin,
+// so that the module does not depend on the OPA library. The query must evaluate to a set of deny messages:
+// the results pass the policy when it is empty or undefined.
+func EvaluateRego(ctx context.Context, opaBin, policy, dataFile, query, jsonResultsFile string) (RegoResult, error) {
+	args := []string{"eval", "--format", "json", "--data", policy, "--input", jsonResultsFile}
+	if dataFile != "" {
+		args = append(args, "--data", dataFile)
+	}
+	args = append(args, query)
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, opaBin, args...)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return RegoResult{}, fmt.Errorf("error evaluating Rego policy %s: %w", policy, err)
+	}
+	denials, err := parseOPAOutput(output)
+	if err != nil {
+		return RegoResult{}, fmt.Errorf("error evaluating Rego policy %s: %w", policy, err)
+	}
+	return RegoResult{Policy: policy, Denials: denials}, nil
+}
+
+// parseOPAOutput is a function to get the deny messages from the JSON output of `opa eval`.
+// An undefined query, e.g. when no deny rule matched, has no results and denies nothing.
+func parseOPAOutput(output []byte) ([]string, error) {
+	var eval struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &eval); err != nil {
+		return nil, fmt.Errorf("error unmarshalling opa output: %w", err)
+	}
+
+	var denials []string
+	for _, result := range eval.Result {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: got %v", ErrUnexpectedRegoResult, expression.Value)
+			}
+			for _, value := range values {
+				if msg, ok := value.(string); ok {
+					denials = append(denials, msg)
+					continue
+				}
+				msg, err := json.Marshal(value)
+				if err != nil {
+					return nil, fmt.Errorf("error marshalling deny message: %w", err)
+				}
+				denials = append(denials, string(msg))
+			}
+		}
+	}
+	return denials, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package policy
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeOPA denies the results for policies named deny.rego, and allows them otherwise.
+const fakeOPA = `#!/bin/sh
+case "$5" in
+  *deny.rego) echo '{"result":[{"expressions":[{"value":["Dangerous-Workflow must score 10"],"text":"data.scorecard.deny"}]}]}' ;;
+  *) echo '{}' ;;
+esac
+`
+
+func Test_parseOPAOutput(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		output  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Success - undefined query",
+			output: `{}`,
+		},
+		{
+			name:   "Success - empty deny set",
+			output: `{"result":[{"expressions":[{"value":[],"text":"data.scorecard.deny"}]}]}`,
+		},
+		{
+			name:   "Success - deny messages",
+			output: `{"result":[{"expressions":[{"value":["a", {"check": "b"}],"text":"data.scorecard.deny"}]}]}`,
+			want:   []string{"a", `{"check":"b"}`},
+		},
+		{
+			name:    "Failure - boolean query",
+			output:  `{"result":[{"expressions":[{"value":true,"text":"data.scorecard.allow"}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Failure - not json",
+			output:  `1 error occurred`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseOPAOutput([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseOPAOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseOPAOutput() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateRego(t *testing.T) {
+	t.Parallel()
+	opaBin := filepath.Join(t.TempDir(), "opa")
+	//nolint:gosec
+	if err := ioutil.WriteFile(opaBin, []byte(fakeOPA), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", opaBin, err)
+	}
+
+	got, err := EvaluateRego(context.Background(), opaBin, "deny.rego", "", "data.scorecard.deny", "results.json")
+	if err != nil {
+		t.Fatalf("EvaluateRego() error = %v", err)
+	}
+	want := RegoResult{Policy: "deny.rego", Denials: []string{"Dangerous-Workflow must score 10"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EvaluateRego() mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = EvaluateRego(context.Background(), opaBin, "allow.rego", "", "data.scorecard.deny", "results.json")
+	if err != nil || len(got.Denials) != 0 {
+		t.Errorf("EvaluateRego() = %v, %v, want no denials", got, err)
+	}
+
+	_, err = EvaluateRego(context.Background(), filepath.Join(t.TempDir(), "missing"), "allow.rego", "",
+		"data.scorecard.deny", "results.json")
+	if err == nil || errors.Is(err, ErrUnexpectedRegoResult) {
+		t.Errorf("EvaluateRego() error = %v, want an execution error", err)
+	}
+}
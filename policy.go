@@ -0,0 +1,89 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ossf/scorecard-action/pkg/policy"
+)
+
+const (
+	// policyFailureExitCode is the exit code of the action when the results do not meet the configured policy.
+	// It is distinct from the exit codes of execution errors, see exitcodes.go.
+	policyFailureExitCode = 1
+	// noScoreThreshold disables the fail_on_score threshold.
+	noScoreThreshold = -1
+	// defaultScorePolicyFile is the score policy file used when it exists and score_policy_file is not set.
+	defaultScorePolicyFile = ".github/scorecard-policy.yml"
+)
+
+var (
+	errInvalidScoreThreshold = errors.New("fail_on_score must be a number between 0 and 10")
+	errScoreBelowThreshold   = errors.New("aggregate score is below the fail_on_score threshold")
+	errScorePolicyViolated   = errors.New("checks do not meet the score policy")
+)
+
+// parseScoreThreshold is a function to parse the fail_on_score input.
+func parseScoreThreshold(value string) (float64, error) {
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > maxCheckScore {
+		return 0, fmt.Errorf("%w: %q", errInvalidScoreThreshold, value)
+	}
+	return threshold, nil
+}
+
+// checkScoreThreshold is a function to check that the aggregate score is not below the threshold.
+func checkScoreThreshold(result *scorecardResult, threshold float64) error {
+	if result.Score < threshold {
+		return fmt.Errorf("%w: %.1f < %.1f", errScoreBelowThreshold, result.Score, threshold)
+	}
+	return nil
+}
+
+// policyChecks is a function to get the scores of the checks of the results, to evaluate a score policy.
+func policyChecks(result *scorecardResult) []policy.Check {
+	checks := make([]policy.Check, 0, len(result.Checks))
+	for i := range result.Checks {
+		checks = append(checks, policy.Check{Name: result.Checks[i].Name, Score: result.Checks[i].Score})
+	}
+	return checks
+}
+
+// evaluatePolicies is a function to check the results against the score threshold and the score policy,
+// reporting every violation to the writer. A nil policy or a noScoreThreshold threshold is not evaluated.
+func evaluatePolicies(writer io.Writer, result *scorecardResult, threshold float64,
+	scorePolicy *policy.ScorePolicy) error {
+	var err error
+	if threshold != noScoreThreshold {
+		if err = checkScoreThreshold(result, threshold); err != nil {
+			fmt.Fprintln(writer, err)
+		}
+	}
+	if scorePolicy != nil {
+		if violations := scorePolicy.Violations(policyChecks(result)); len(violations) > 0 {
+			fmt.Fprintf(writer, "The following checks do not meet the score policy:\n")
+			for _, v := range violations {
+				fmt.Fprintf(writer, "  %s: score %s is below the minimum of %d\n", v.Check, formatCheckScore(v.Score), v.Minimum)
+			}
+			if err == nil {
+				err = fmt.Errorf("%w: %d violation(s)", errScorePolicyViolated, len(violations))
+			}
+		}
+	}
+	return err
+}
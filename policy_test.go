@@ -0,0 +1,99 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/pkg/policy"
+)
+
+func Test_parseScoreThreshold(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "7", want: 7},
+		{value: "6.5", want: 6.5},
+		{value: "0", want: 0},
+		{value: "10", want: 10},
+		{value: "10.5", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "high", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseScoreThreshold(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseScoreThreshold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseScoreThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkScoreThreshold(t *testing.T) {
+	t.Parallel()
+	result := newTestResult(6.8)
+	if err := checkScoreThreshold(result, 6.8); err != nil {
+		t.Errorf("checkScoreThreshold() error = %v for a score equal to the threshold", err)
+	}
+	if err := checkScoreThreshold(result, 7); !errors.Is(err, errScoreBelowThreshold) {
+		t.Errorf("checkScoreThreshold() error = %v, want %v", err, errScoreBelowThreshold)
+	}
+}
+
+func Test_evaluatePolicies(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("./testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	scorePolicy, err := policy.ReadScorePolicy("./testdata/score-policy.yml")
+	if err != nil {
+		t.Fatalf("ReadScorePolicy() error = %v", err)
+	}
+
+	// Pinned-Dependencies is disabled and Token-Permissions is not in the results.
+	want := "The following checks do not meet the score policy:\n" +
+		"  Branch-Protection: score 3 is below the minimum of 8\n" +
+		"  CII-Best-Practices: score ? is below the minimum of 5\n"
+	var got bytes.Buffer
+	if err := evaluatePolicies(&got, result, noScoreThreshold, scorePolicy); !errors.Is(err, errScorePolicyViolated) {
+		t.Errorf("evaluatePolicies() error = %v, want %v", err, errScorePolicyViolated)
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("evaluatePolicies() mismatch (-want +got):\n%s", diff)
+	}
+
+	got.Reset()
+	if err := evaluatePolicies(&got, result, 7, nil); !errors.Is(err, errScoreBelowThreshold) {
+		t.Errorf("evaluatePolicies() error = %v, want %v", err, errScoreBelowThreshold)
+	}
+	if err := evaluatePolicies(&got, result, 5, nil); err != nil {
+		t.Errorf("evaluatePolicies() error = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prCommentMarker identifies the comment the action posts, so that later runs update it
+// instead of posting a new one.
+const prCommentMarker = "<!-- scorecard-action:pr-comment -->"
+
+var errNoPullRequestNumber = errors.New("pull request number not found in the event")
+
+// issueComment is a comment on a GitHub issue or pull request.
+type issueComment struct {
+	User *commentAuthor `json:"user,omitempty"`
+	Body string         `json:"body"`
+	ID   int64          `json:"id,omitempty"`
+}
+
+// commentAuthor is the author of a comment.
+type commentAuthor struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// postPRComment is a function to score the default branch and post the score delta
+// of the pull request's head as a comment on the pull request.
+func postPRComment(ctx context.Context, headResultsFile string) error {
+	eventData, err := ioutil.ReadFile(os.Getenv(githubEventPath))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", githubEventPath, err)
+	}
+	number, err := pullRequestNumber(string(eventData))
+	if err != nil {
+		return err
+	}
+
+	head, err := readScorecardResult(headResultsFile)
+	if err != nil {
+		return err
+	}
+	base, err := runBaseScorecard(os.Getenv(githubRepository), resultChecks(head))
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	renderPRComment(&body, base, head)
+	client := newGitHubClient(scorecardGitHubToken)
+	return upsertPRComment(ctx, client, os.Getenv(githubRepository), number, body.String())
+}
+
+// pullRequestNumber is a function to get the pull request number from the GitHub event data.
+func pullRequestNumber(eventData string) (int, error) {
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+		return 0, fmt.Errorf("error unmarshalling event data: %w", err)
+	}
+	if event.PullRequest.Number == 0 {
+		return 0, errNoPullRequestNumber
+	}
+	return event.PullRequest.Number, nil
+}
+
+// runBaseScorecard is a function to score the default branch of the repository with JSON results.
+// Only the checks of the pull request's results are run: the pull request is analyzed locally, which
+// runs fewer checks than a remote run of the default branch.
+func runBaseScorecard(repository string, checks []string) (*scorecardResult, error) {
+	cmd, err := runScorecardSettings("", "", "json", scorecardBin, repository, "", checks)
+	if err != nil {
+		return nil, err
+	}
+	resultsFile := filepath.Join(os.TempDir(), "scorecard-base-results.json")
+	if err := runScorecard(cmd, resultsFile); err != nil {
+		return nil, err
+	}
+	return readScorecardResult(resultsFile)
+}
+
+// resultChecks is a function to get the names of the checks of the results.
+func resultChecks(result *scorecardResult) []string {
+	checks := make([]string, 0, len(result.Checks))
+	for i := range result.Checks {
+		checks = append(checks, result.Checks[i].Name)
+	}
+	return checks
+}
+
+// comparableChecks is a function to get the checks of base and head that both scored, so that their
+// aggregate scores are computed over the same checks.
+func comparableChecks(base, head *scorecardResult) (baseChecks, headChecks []checkResult) {
+	baseScores := make(map[string]int, len(base.Checks))
+	for i := range base.Checks {
+		if base.Checks[i].Score != inconclusiveScore {
+			baseScores[base.Checks[i].Name] = base.Checks[i].Score
+		}
+	}
+	for i := range head.Checks {
+		check := head.Checks[i]
+		baseScore, ok := baseScores[check.Name]
+		if !ok || check.Score == inconclusiveScore {
+			continue
+		}
+		headChecks = append(headChecks, check)
+		baseChecks = append(baseChecks, checkResult{Name: check.Name, Score: baseScore})
+	}
+	return baseChecks, headChecks
+}
+
+// renderPRComment is a function to render the score delta between the default branch and
+// the pull request as markdown.
+func renderPRComment(writer io.Writer, base, head *scorecardResult) {
+	fmt.Fprintf(writer, "%s\n## Scorecard score delta\n\n", prCommentMarker)
+	baseChecks, headChecks := comparableChecks(base, head)
+	baseScore, headScore := aggregateScore(baseChecks), aggregateScore(headChecks)
+	if headScore == inconclusiveScore {
+		fmt.Fprintf(writer, "Aggregate score: no check scored on both the default branch and the pull request.\n\n")
+	} else {
+		fmt.Fprintf(writer, "Aggregate score over the %d check(s) scored on both: **%.1f** (default branch: %.1f, %+.1f)"+
+			"\n\n", len(headChecks), headScore, baseScore, headScore-baseScore)
+	}
+	writeDeltaTable(writer, compareResults(base, head), "Default branch", "Pull request")
+}
+
+// upsertPRComment is a function to update the action's comment on the pull request,
+// or to create it if the action did not comment yet.
+func upsertPRComment(ctx context.Context, client *githubClient, repository string, number int, body string) error {
+	existing, err := findPRComment(ctx, client, repository, number)
+	if err != nil {
+		return err
+	}
+	comment := issueComment{Body: body}
+	if existing != nil {
+		path := fmt.Sprintf("/repos/%s/issues/comments/%d", repository, existing.ID)
+		return client.do(ctx, http.MethodPatch, path, &comment, nil)
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repository, number)
+	return client.do(ctx, http.MethodPost, path, &comment, nil)
+}
+
+// findPRComment is a function to find the comment previously posted by the action on the pull request.
+// The action comments as a bot, e.g. github-actions[bot] or a GitHub App, so that a user quoting the
+// marker cannot get their comment overwritten.
+func findPRComment(ctx context.Context, client *githubClient, repository string, number int) (*issueComment, error) {
+	for page := 1; ; page++ {
+		var comments []issueComment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d",
+			repository, number, githubPageSize, page)
+		if err := client.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if comments[i].User != nil && comments[i].User.Type == "Bot" &&
+				strings.Contains(comments[i].Body, prCommentMarker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < githubPageSize {
+			return nil, nil
+		}
+	}
+}
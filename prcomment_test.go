@@ -0,0 +1,202 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_pullRequestNumber(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name      string
+		eventPath string
+		want      int
+		wantErr   bool
+	}{
+		{
+			name:      "Success - pull request event",
+			eventPath: "./testdata/pull_request.json",
+			want:      42,
+		},
+		{
+			name:      "Failure - push event",
+			eventPath: "./testdata/fork.json",
+			wantErr:   true,
+		},
+		{
+			name:      "Failure - incorrect event",
+			eventPath: "./testdata/incorrect.json",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := ioutil.ReadFile(tt.eventPath)
+			if err != nil {
+				t.Fatalf("Failed to open test data: %v", err)
+			}
+			got, err := pullRequestNumber(string(data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pullRequestNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("pullRequestNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_renderPRComment(t *testing.T) {
+	t.Parallel()
+	base := newTestResult(7.1,
+		checkResult{Name: "Branch-Protection", Score: 5},
+		checkResult{Name: "Fuzzing", Score: inconclusiveScore},
+		checkResult{Name: "Pinned-Dependencies", Score: 6},
+	)
+	head := newTestResult(6.8,
+		checkResult{Name: "Branch-Protection", Score: 3},
+		checkResult{Name: "Fuzzing", Score: 0},
+		checkResult{Name: "Pinned-Dependencies", Score: 7},
+	)
+	want := prCommentMarker + `
+## Scorecard score delta
+
+Aggregate score over the 2 check(s) scored on both: **4.6** (default branch: 5.4, -0.8)
+
+| Check | Default branch | Pull request | Delta |
+| ----- | -------------- | ------------ | ----- |
+| Branch-Protection | 5 | 3 | -2 :warning: |
+| Fuzzing | ? | 0 |  |
+| Pinned-Dependencies | 6 | 7 | +1 |
+
+**1 check(s) regressed:** Branch-Protection
+`
+	var got bytes.Buffer
+	renderPRComment(&got, base, head)
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("renderPRComment() mismatch (-want +got):\n%s", diff)
+	}
+
+	got.Reset()
+	renderPRComment(&got, base, base)
+	if !strings.HasSuffix(got.String(), "No check regressed.\n") {
+		t.Errorf("renderPRComment() = %v, want no regression", got.String())
+	}
+
+	// The aggregate scores of the results are not compared: the default branch ran more checks.
+	got.Reset()
+	base.Checks = append(base.Checks, checkResult{Name: "Code-Review", Score: 10})
+	renderPRComment(&got, base, head)
+	if !strings.Contains(got.String(), "**4.6** (default branch: 5.4, -0.8)") {
+		t.Errorf("renderPRComment() = %v, want the aggregates of the checks scored on both", got.String())
+	}
+
+	got.Reset()
+	renderPRComment(&got, newTestResult(7), head)
+	if !strings.Contains(got.String(), "Aggregate score: no check scored on both") {
+		t.Errorf("renderPRComment() = %v, want no aggregate score", got.String())
+	}
+}
+
+func Test_resultChecks(t *testing.T) {
+	t.Parallel()
+	result := newTestResult(6.8,
+		checkResult{Name: "Binary-Artifacts", Score: 10},
+		checkResult{Name: "Token-Permissions", Score: inconclusiveScore},
+	)
+	want := []string{"Binary-Artifacts", "Token-Permissions"}
+	if diff := cmp.Diff(want, resultChecks(result)); diff != "" {
+		t.Errorf("resultChecks() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_upsertPRComment(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name       string
+		comments   []issueComment
+		wantMethod string
+		wantPath   string
+	}{
+		{
+			name:       "Create comment",
+			comments:   []issueComment{{ID: 1, Body: "LGTM"}},
+			wantMethod: http.MethodPost,
+			wantPath:   "/repos/owner/repo/issues/42/comments",
+		},
+		{
+			name: "Update existing comment",
+			comments: []issueComment{
+				{ID: 1, Body: "LGTM"},
+				{ID: 7, Body: prCommentMarker + "\nold", User: &commentAuthor{Login: "github-actions[bot]", Type: "Bot"}},
+			},
+			wantMethod: http.MethodPatch,
+			wantPath:   "/repos/owner/repo/issues/comments/7",
+		},
+		{
+			name: "Ignore the marker in a user's comment",
+			comments: []issueComment{
+				{ID: 1, Body: "> " + prCommentMarker, User: &commentAuthor{Login: "octocat", Type: "User"}},
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/repos/owner/repo/issues/42/comments",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotMethod, gotPath, gotBody string
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					if err := json.NewEncoder(w).Encode(tt.comments); err != nil {
+						t.Errorf("failed to encode comments: %v", err)
+					}
+					return
+				}
+				var comment issueComment
+				if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+					t.Errorf("failed to decode comment: %v", err)
+				}
+				gotMethod, gotPath, gotBody = r.Method, r.URL.Path, comment.Body
+				fmt.Fprint(w, "{}")
+			}))
+			body := prCommentMarker + "\nnew"
+			if err := upsertPRComment(context.Background(), client, "owner/repo", 42, body); err != nil {
+				t.Fatalf("upsertPRComment() error = %v", err)
+			}
+			if gotMethod != tt.wantMethod || gotPath != tt.wantPath {
+				t.Errorf("upsertPRComment() sent %s %s, want %s %s", gotMethod, gotPath, tt.wantMethod, tt.wantPath)
+			}
+			if gotBody != body {
+				t.Errorf("upsertPRComment() body = %v, want %v", gotBody, body)
+			}
+		})
+	}
+}
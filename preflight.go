@@ -0,0 +1,154 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// permissionDegraded is the status of a check the token lacks a permission for.
+	permissionDegraded = "degraded"
+	// permissionUnverified is the status of a check whose permissions GitHub does not report for the token,
+	// e.g. for fine-grained and GitHub App tokens.
+	permissionUnverified = "unverified"
+)
+
+// checkPermission is a permission the token needs for a check to get a conclusive score.
+type checkPermission struct {
+	check string
+	// scope is the personal access token (classic) scope the check needs.
+	scope string
+	// use is what the check needs the permission for.
+	use   string
+	admin bool
+}
+
+// checkPermissions are the permissions of the checks that need more than read access to the repository.
+var checkPermissions = []checkPermission{
+	{check: "Branch-Protection", scope: "repo", use: "read branch protection rules", admin: true},
+}
+
+// tokenPermissions are the permissions of the token on the repository.
+type tokenPermissions struct {
+	// scopes are the scopes of a personal access token (classic), nil for other tokens.
+	scopes map[string]bool
+	// admin is whether the token has admin access to the repository, nil when GitHub does not report it.
+	admin *bool
+}
+
+// permissionIssue is a check that will be degraded, or may be, by the permissions of the token.
+type permissionIssue struct {
+	check  string
+	status string
+	reason string
+}
+
+// checkTokenPermissions is a function to report the checks the token lacks permissions for,
+// before they silently return inconclusive scores.
+// Failing to get the permissions only skips the report.
+func checkTokenPermissions(ctx context.Context, writer io.Writer, client *githubClient, repository string,
+	checks []string) {
+	permissions, err := fetchTokenPermissions(ctx, client, repository)
+	if err != nil {
+		fmt.Fprintf(writer, "Skipping the token permission check: %v\n", err)
+		return
+	}
+	writePermissionIssues(writer, permissionIssues(permissions, checks))
+}
+
+// fetchTokenPermissions is a function to get the permissions of the token on the repository:
+// the scopes GitHub reports for classic personal access tokens, and the repository permissions
+// it reports for user tokens.
+func fetchTokenPermissions(ctx context.Context, client *githubClient,
+	repository string) (tokenPermissions, error) {
+	resp, err := client.send(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s", client.baseURL, repository), nil)
+	if err != nil {
+		return tokenPermissions{}, err
+	}
+	defer resp.Body.Close()
+
+	var repo struct {
+		Permissions *struct {
+			Admin bool `json:"admin"`
+		} `json:"permissions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return tokenPermissions{}, fmt.Errorf("error decoding response body: %w", err)
+	}
+	var permissions tokenPermissions
+	if repo.Permissions != nil {
+		admin := repo.Permissions.Admin
+		permissions.admin = &admin
+	}
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		permissions.scopes = make(map[string]bool)
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				permissions.scopes[scope] = true
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// permissionIssues is a function to get the checks, among those that will run, the token lacks
+// or may lack permissions for.
+func permissionIssues(permissions tokenPermissions, checks []string) []permissionIssue {
+	running := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		running[check] = true
+	}
+	var issues []permissionIssue
+	for _, required := range checkPermissions {
+		if !running[required.check] {
+			continue
+		}
+		issue := permissionIssue{check: required.check, status: permissionDegraded}
+		switch {
+		case required.scope != "" && permissions.scopes != nil && !permissions.scopes[required.scope]:
+			issue.reason = fmt.Sprintf("the token lacks the `%s` scope needed to %s", required.scope, required.use)
+		case required.admin && permissions.admin != nil && !*permissions.admin:
+			issue.reason = fmt.Sprintf("the token needs admin access to the repository to %s", required.use)
+		case required.admin && permissions.admin == nil:
+			issue.status = permissionUnverified
+			issue.reason = fmt.Sprintf("GitHub does not report whether the token has the admin access needed to %s",
+				required.use)
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// writePermissionIssues is a function to render the permission issues as a markdown table.
+func writePermissionIssues(writer io.Writer, issues []permissionIssue) {
+	if len(issues) == 0 {
+		fmt.Fprintf(writer, "The token has the permissions every check needs.\n")
+		return
+	}
+	fmt.Fprintf(writer, "Some checks may return inconclusive or lower scores because of the token permissions:\n\n")
+	fmt.Fprintf(writer, "| Check | Status | Reason |\n")
+	fmt.Fprintf(writer, "| ----- | ------ | ------ |\n")
+	for _, issue := range issues {
+		fmt.Fprintf(writer, "| %s | %s | %s |\n", issue.check, issue.status, issue.reason)
+	}
+	fmt.Fprintln(writer)
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_permissionIssues(t *testing.T) {
+	t.Parallel()
+	admin, notAdmin := true, false
+	//nolint
+	tests := []struct {
+		name        string
+		permissions tokenPermissions
+		checks      []string
+		want        []permissionIssue
+	}{
+		{
+			name:        "Admin with the repo scope",
+			permissions: tokenPermissions{scopes: map[string]bool{"repo": true}, admin: &admin},
+			checks:      knownChecks,
+		},
+		{
+			name:        "Missing scope",
+			permissions: tokenPermissions{scopes: map[string]bool{"public_repo": true}, admin: &admin},
+			checks:      knownChecks,
+			want: []permissionIssue{{
+				check:  "Branch-Protection",
+				status: permissionDegraded,
+				reason: "the token lacks the `repo` scope needed to read branch protection rules",
+			}},
+		},
+		{
+			name:        "Not an admin",
+			permissions: tokenPermissions{admin: &notAdmin},
+			checks:      knownChecks,
+			want: []permissionIssue{{
+				check:  "Branch-Protection",
+				status: permissionDegraded,
+				reason: "the token needs admin access to the repository to read branch protection rules",
+			}},
+		},
+		{
+			name:        "Unreported permissions",
+			permissions: tokenPermissions{},
+			checks:      knownChecks,
+			want: []permissionIssue{{
+				check:  "Branch-Protection",
+				status: permissionUnverified,
+				reason: "GitHub does not report whether the token has the admin access needed to read branch protection rules",
+			}},
+		},
+		{
+			name:        "Check not running",
+			permissions: tokenPermissions{admin: &notAdmin},
+			checks:      []string{"Code-Review"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := permissionIssues(tt.permissions, tt.checks)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(permissionIssue{})); diff != "" {
+				t.Errorf("permissionIssues() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_checkTokenPermissions(t *testing.T) {
+	t.Parallel()
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "public_repo, read:org")
+		w.Write([]byte(`{"permissions": {"admin": true, "push": true, "pull": true}}`))
+	}))
+
+	var buf bytes.Buffer
+	checkTokenPermissions(context.Background(), &buf, client, "owner/repo", knownChecks)
+	want := "| Branch-Protection | degraded | the token lacks the `repo` scope needed to read branch protection rules |"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("checkTokenPermissions() = %q, want it to contain %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	checkTokenPermissions(context.Background(), &buf, client, "owner/missing", knownChecks)
+	if !strings.HasPrefix(buf.String(), "Skipping the token permission check") {
+		t.Errorf("checkTokenPermissions() = %q, want the check to be skipped", buf.String())
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// probeFormat is scorecard's structured results format, which reports the outcome of each probe
+	// the checks are built from rather than their aggregated score.
+	probeFormat = "probe"
+	// minProbeScorecardVersion is the first scorecard release with the probe format.
+	minProbeScorecardVersion = "v4.13.0"
+)
+
+var errProbeFormatUnsupported = errors.New("the probe results format needs scorecard " + minProbeScorecardVersion +
+	" or later: set scorecard_version or scorecard_bin")
+
+// validateProbeFormat is a function to check that the scorecard that runs supports the probe format,
+// when it is requested. A scorecard_bin is trusted to support it, since its version is unknown.
+func validateProbeFormat(outputs []resultsOutput, version string, customBin bool) error {
+	if !hasResultsFormat(outputs, probeFormat) || customBin {
+		return nil
+	}
+	if version == "" {
+		version = bundledScorecardVersion
+	}
+	if !versionAtLeast(version, minProbeScorecardVersion) {
+		return fmt.Errorf("%w: got %s", errProbeFormatUnsupported, version)
+	}
+	return nil
+}
+
+// versionAtLeast is a function to compare two vMAJOR.MINOR.PATCH versions. Versions that cannot be parsed
+// are never at least another one.
+func versionAtLeast(version, min string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	m, ok := parseVersion(min)
+	if !ok {
+		return false
+	}
+	for i := range v {
+		if v[i] != m[i] {
+			return v[i] > m[i]
+		}
+	}
+	return true
+}
+
+// parseVersion is a function to parse a vMAJOR.MINOR.PATCH version, ignoring any pre-release suffix.
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != len(parsed) {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_validateProbeFormat(t *testing.T) {
+	t.Parallel()
+	probe := []resultsOutput{{format: "json"}, {format: probeFormat}}
+	//nolint
+	tests := []struct {
+		name      string
+		outputs   []resultsOutput
+		version   string
+		customBin bool
+		wantErr   error
+	}{
+		{name: "not requested", outputs: []resultsOutput{{format: "json"}}},
+		{name: "bundled scorecard", outputs: probe, wantErr: errProbeFormatUnsupported},
+		{name: "old version", outputs: probe, version: "v4.12.0", wantErr: errProbeFormatUnsupported},
+		{name: "minimum version", outputs: probe, version: "v4.13.0"},
+		{name: "newer version", outputs: probe, version: "v5.0.0-rc1"},
+		{name: "custom binary", outputs: probe, customBin: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateProbeFormat(tt.outputs, tt.version, tt.customBin)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateProbeFormat() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_versionAtLeast(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		version string
+		min     string
+		want    bool
+	}{
+		{version: "v4.13.0", min: "v4.13.0", want: true},
+		{version: "v4.13.1", min: "v4.13.0", want: true},
+		{version: "v4.2.0", min: "v4.13.0", want: false},
+		{version: "v10.0.0", min: "v4.13.0", want: true},
+		{version: "latest", min: "v4.13.0", want: false},
+	}
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, tt.min); got != tt.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", tt.version, tt.min, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval is how often long runs log that they are still running, so that they do not look hung.
+var heartbeatInterval = time.Minute
+
+// checkProgress logs the progress of checks run in separate scorecard processes. The output of each check
+// is logged in its own group once the check finishes, so that the output of concurrent checks does not interleave.
+type checkProgress struct {
+	start   time.Time
+	writer  io.Writer
+	running map[string]time.Time
+	mu      sync.Mutex
+	total   int
+	done    int
+}
+
+// newCheckProgress is a function to start logging the progress of the checks.
+func newCheckProgress(writer io.Writer, total int) *checkProgress {
+	return &checkProgress{start: time.Now(), writer: writer, running: make(map[string]time.Time), total: total}
+}
+
+// started is a function to log that the check started.
+func (p *checkProgress) started(check string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running[check] = time.Now()
+	fmt.Fprintf(p.writer, "Check %s started.\n", check)
+}
+
+// finished is a function to log the output of the check in a group named after the check and its status,
+// followed by the progress of the run.
+func (p *checkProgress) finished(check, status string, output []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	duration := time.Duration(0)
+	if started, ok := p.running[check]; ok {
+		duration = time.Since(started)
+		delete(p.running, check)
+	}
+	p.done++
+	fmt.Fprintf(p.writer, "::group::Check %s %s in %s\n", check, status, formatElapsed(duration))
+	p.writer.Write(output)
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		fmt.Fprintln(p.writer)
+	}
+	fmt.Fprintln(p.writer, "::endgroup::")
+	fmt.Fprintf(p.writer, "%d of %d checks complete, %s elapsed.\n", p.done, p.total, formatElapsed(time.Since(p.start)))
+}
+
+// heartbeat is a function to log the checks still running.
+func (p *checkProgress) heartbeat() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	running := make([]string, 0, len(p.running))
+	for check := range p.running {
+		running = append(running, check)
+	}
+	sort.Strings(running)
+	fmt.Fprintf(p.writer, "Still running %s: %d of %d checks complete, %s elapsed.\n",
+		strings.Join(running, ", "), p.done, p.total, formatElapsed(time.Since(p.start)))
+}
+
+// startHeartbeat is a function to call beat every heartbeatInterval until the returned function is called.
+// beat is not called once the returned function returns.
+func startHeartbeat(beat func()) func() {
+	ticker := time.NewTicker(heartbeatInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				beat()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+// formatElapsed is a function to format a duration to the second, e.g. 1m30s.
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// elapsedPattern matches the durations of the progress logs.
+var elapsedPattern = regexp.MustCompile(`\d+(\.\d+)?(ns|µs|ms|s)\b`)
+
+func Test_checkProgress(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	progress := newCheckProgress(&out, 3)
+	progress.started("SAST")
+	progress.started("CI-Tests")
+	progress.heartbeat()
+	progress.finished("SAST", "finished", []byte("scorecard output"))
+	progress.finished("CI-Tests", "timed out after 1m0s", nil)
+
+	want := "Check SAST started.\n" +
+		"Check CI-Tests started.\n" +
+		"Still running CI-Tests, SAST: 0 of 3 checks complete, 0s elapsed.\n" +
+		"::group::Check SAST finished in 0s\n" +
+		"scorecard output\n" +
+		"::endgroup::\n" +
+		"1 of 3 checks complete, 0s elapsed.\n" +
+		"::group::Check CI-Tests timed out after 1m0s in 0s\n" +
+		"::endgroup::\n" +
+		"2 of 3 checks complete, 0s elapsed.\n"
+	if got := elapsedPattern.ReplaceAllString(out.String(), "0s"); got != want {
+		t.Errorf("checkProgress logs = %q, want %q", got, want)
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the heartbeat interval
+//nolint
+func Test_startHeartbeat(t *testing.T) {
+	defer func(interval time.Duration) { heartbeatInterval = interval }(heartbeatInterval)
+	heartbeatInterval = time.Millisecond
+
+	var mu sync.Mutex
+	beats := 0
+	stop := startHeartbeat(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		beats++
+	})
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	mu.Lock()
+	got := beats
+	mu.Unlock()
+	if got == 0 {
+		t.Fatal("startHeartbeat() did not beat")
+	}
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if beats != got {
+		t.Errorf("startHeartbeat() beat %d times after being stopped", beats-got)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRateLimitWait = 15 * time.Minute
+	// secondaryRateLimitWait is how long GitHub recommends waiting after hitting a secondary rate limit
+	// whose response has no Retry-After header.
+	secondaryRateLimitWait = time.Minute
+	// minRateLimitWait makes up for the reset time having a one second precision.
+	minRateLimitWait = time.Second
+	// lowRateLimitRatio is the share of the rate limit below which the remaining quota is logged.
+	lowRateLimitRatio = 0.1
+)
+
+var errRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// rateLimit is the GitHub API rate limit quota reported by the headers of a response.
+type rateLimit struct {
+	reset     time.Time
+	limit     int
+	remaining int
+}
+
+// parseRateLimit is a function to read the rate limit quota from the X-RateLimit-* response headers.
+func parseRateLimit(header http.Header) (rateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return rateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rateLimit{}, false
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return rateLimit{}, false
+	}
+	return rateLimit{reset: time.Unix(reset, 0), limit: limit, remaining: remaining}, true
+}
+
+// low is a function to check if less than lowRateLimitRatio of the quota remains.
+func (r rateLimit) low() bool {
+	return float64(r.remaining) < float64(r.limit)*lowRateLimitRatio
+}
+
+// rateLimitWait is a function to check if an unsuccessful response hit a rate limit and get how long
+// to wait before retrying: until the reset of an exhausted primary rate limit, or the Retry-After
+// delay of a secondary rate limit.
+func rateLimitWait(statusCode int, header http.Header, message string, now time.Time) (time.Duration, bool) {
+	if statusCode != http.StatusForbidden && statusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	var wait time.Duration
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err != nil {
+			return 0, false
+		}
+		wait = time.Duration(seconds) * time.Second
+	} else if quota, ok := parseRateLimit(header); ok && quota.remaining == 0 {
+		wait = quota.reset.Sub(now)
+	} else if strings.Contains(strings.ToLower(message), "secondary rate limit") {
+		wait = secondaryRateLimitWait
+	} else {
+		return 0, false
+	}
+	if wait < minRateLimitWait {
+		wait = minRateLimitWait
+	}
+	return wait, true
+}
+
+// logRateLimit is a function to log the remaining quota once it runs low, so that jobs getting close
+// to the rate limit can be told apart from jobs that are slow for other reasons.
+func logRateLimit(writer io.Writer, quota rateLimit) {
+	fmt.Fprintf(writer, "GitHub API rate limit is running low: %d of %d requests remaining until %s\n",
+		quota.remaining, quota.limit, quota.reset.UTC().Format(time.RFC3339))
+}
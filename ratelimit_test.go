@@ -0,0 +1,172 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_rateLimitWait(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1650000000, 0)
+	reset := strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)
+	//nolint
+	tests := []struct {
+		name            string
+		statusCode      int
+		header          http.Header
+		message         string
+		want            time.Duration
+		wantRateLimited bool
+	}{
+		{
+			name:       "Exhausted primary rate limit",
+			statusCode: http.StatusForbidden,
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {reset},
+			},
+			want:            10 * time.Minute,
+			wantRateLimited: true,
+		},
+		{
+			name:            "Secondary rate limit with Retry-After",
+			statusCode:      http.StatusTooManyRequests,
+			header:          http.Header{"Retry-After": {"30"}},
+			want:            30 * time.Second,
+			wantRateLimited: true,
+		},
+		{
+			name:            "Secondary rate limit without Retry-After",
+			statusCode:      http.StatusForbidden,
+			header:          http.Header{},
+			message:         `{"message": "You have exceeded a secondary rate limit."}`,
+			want:            secondaryRateLimitWait,
+			wantRateLimited: true,
+		},
+		{
+			name:       "Reset in the past",
+			statusCode: http.StatusForbidden,
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {"1"},
+			},
+			want:            minRateLimitWait,
+			wantRateLimited: true,
+		},
+		{
+			name:       "Forbidden with quota remaining",
+			statusCode: http.StatusForbidden,
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"4000"},
+				"X-Ratelimit-Reset":     {reset},
+			},
+			message: `{"message": "Resource not accessible by integration"}`,
+		},
+		{
+			name:       "Not a rate limit status",
+			statusCode: http.StatusServiceUnavailable,
+			header:     http.Header{"Retry-After": {"30"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, rateLimited := rateLimitWait(tt.statusCode, tt.header, tt.message, now)
+			if got != tt.want || rateLimited != tt.wantRateLimited {
+				t.Errorf("rateLimitWait() = %v, %v, want %v, %v", got, rateLimited, tt.want, tt.wantRateLimited)
+			}
+		})
+	}
+}
+
+func Test_rateLimit_low(t *testing.T) {
+	t.Parallel()
+	header := http.Header{
+		"X-Ratelimit-Limit":     {"1000"},
+		"X-Ratelimit-Remaining": {"99"},
+		"X-Ratelimit-Reset":     {"1650000000"},
+	}
+	quota, ok := parseRateLimit(header)
+	if !ok || quota.limit != 1000 || quota.remaining != 99 || !quota.reset.Equal(time.Unix(1650000000, 0)) {
+		t.Fatalf("parseRateLimit() = %+v, %v", quota, ok)
+	}
+	if !quota.low() {
+		t.Errorf("low() = false with %d of %d remaining", quota.remaining, quota.limit)
+	}
+	quota.remaining = 100
+	if quota.low() {
+		t.Errorf("low() = true with %d of %d remaining", quota.remaining, quota.limit)
+	}
+	if _, ok := parseRateLimit(http.Header{}); ok {
+		t.Errorf("parseRateLimit() without headers ok = true")
+	}
+}
+
+func Test_githubClient_rateLimit(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name             string
+		maxRateLimitWait time.Duration
+		wantRequests     int
+		wantErr          bool
+	}{
+		{
+			name:             "Waits for the rate limit to reset",
+			maxRateLimitWait: time.Minute,
+			wantRequests:     2,
+		},
+		{
+			name:         "Fails without waiting",
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			requests := 0
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+				}
+			}))
+			client.retry = retryPolicy{maxRateLimitWait: tt.maxRateLimitWait}
+
+			err := client.do(context.Background(), http.MethodGet, "/path", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errRateLimited) {
+				t.Errorf("do() error = %v, want %v", err, errRateLimited)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("do() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
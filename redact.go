@@ -0,0 +1,146 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	redacted = "***"
+	// minSecretLength keeps short values, e.g. "true", from being redacted everywhere they appear.
+	minSecretLength = 8
+)
+
+// secretEnvs are the environment variables holding secrets, redacted wherever their values appear.
+var secretEnvs = []string{
+	githubAuthToken,
+	inputrepotoken,
+	inputgithubtoken,
+	inputappprivatekey,
+	inputwebhooksecret,
+	inputsplunkhectoken,
+	inputdatadogapikey,
+	inputnotifywebhookurl,
+	inputslackwebhookurl,
+	"ACTIONS_ID_TOKEN_REQUEST_TOKEN",
+	"ACTIONS_RUNTIME_TOKEN",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+}
+
+// secretPatterns match secrets the action never saw as values, e.g. refreshed installation tokens
+// or an OIDC token echoed in a response body. Each pattern redacts its last group.
+var secretPatterns = []*regexp.Regexp{
+	// GitHub tokens: personal, OAuth, user-to-server, server-to-server and refresh tokens.
+	regexp.MustCompile(`()\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`),
+	// Credentials of Authorization headers.
+	regexp.MustCompile(`(?i)(\b(?:bearer|basic)\s+)([A-Za-z0-9._~+/=-]{8,})`),
+	// JWTs, e.g. OIDC tokens.
+	regexp.MustCompile(`()\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	// PEM private keys, when they are on a single line, e.g. in JSON.
+	regexp.MustCompile(`()-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+	// flushOutput waits for the redacted output to be written, before the action exits.
+	flushOutput = func() {}
+)
+
+// registerSecrets is a function to redact the values from the output and the errors of the action.
+// The lines of multi-line values, e.g. private keys, are redacted one by one, as output is redacted by line.
+func registerSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		for _, line := range strings.Split(value, "\n") {
+			if line = strings.TrimSpace(line); len(line) >= minSecretLength {
+				secrets = append(secrets, line)
+			}
+		}
+	}
+}
+
+// registerSecretEnvs is a function to redact the values of the secret environment variables.
+func registerSecretEnvs() {
+	for _, env := range secretEnvs {
+		registerSecrets(os.Getenv(env))
+	}
+}
+
+// redactSecrets is a function to mask the registered secrets and anything that looks like a secret.
+func redactSecrets(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	secretsMu.RUnlock()
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
+
+// redactOutput is a function to redact every line written to stdout and stderr, including the output
+// of the scorecard processes, by replacing them with pipes. flushOutput restores them.
+func redactOutput() error {
+	stdout, stderr := os.Stdout, os.Stderr
+	outReader, outWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("error creating pipe: %w", err)
+	}
+	errReader, errWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("error creating pipe: %w", err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go copyRedacted(&wg, stdout, outReader)
+	go copyRedacted(&wg, stderr, errReader)
+	os.Stdout, os.Stderr = outWriter, errWriter
+
+	var once sync.Once
+	flushOutput = func() {
+		once.Do(func() {
+			os.Stdout, os.Stderr = stdout, stderr
+			outWriter.Close()
+			errWriter.Close()
+			wg.Wait()
+		})
+	}
+	return nil
+}
+
+// copyRedacted is a function to copy the lines of the reader to the writer, redacting their secrets.
+func copyRedacted(wg *sync.WaitGroup, writer io.Writer, reader io.Reader) {
+	defer wg.Done()
+	buffered := bufio.NewReader(reader)
+	for {
+		line, err := buffered.ReadString('\n')
+		if line != "" {
+			io.WriteString(writer, redactSecrets(line)) //nolint:errcheck
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var errRefConflict = errors.New("ref analyzes the repository on GitHub, so it cannot be combined with")
+
+// scorecardCommit is the commit scorecard analyzes instead of the head of the default branch,
+// resolved from the ref input.
+var scorecardCommit = ""
+
+// validateRef is a function to check the inputs and event that analyze a checkout, or other repositories,
+// are not combined with a ref, which scorecard analyzes on GitHub.
+func validateRef(eventName string) error {
+	conflicts := []struct {
+		name string
+		set  bool
+	}{
+		{"pull_request events", strings.Contains(eventName, "pull_request")},
+		{"local_path", scorecardLocalPath != ""},
+		{"sub_paths", len(scorecardSubPaths) > 0},
+		{"organization", scorecardOrganization != ""},
+		{"repos_file", scorecardReposFile != ""},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return fmt.Errorf("%w %s", errRefConflict, c.name)
+		}
+	}
+	return nil
+}
+
+// useRef is a function to resolve the branch, tag or commit to analyze and to point GITHUB_SHA,
+// and GITHUB_REF for fully-qualified refs, to it, so that the results are recorded for that commit.
+func useRef(ctx context.Context, client *githubClient, repository, ref string) error {
+	name := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	sha, err := resolveActionRef(ctx, client, repository, name)
+	if err != nil {
+		return fmt.Errorf("error resolving %s in %s: %w", ref, repository, err)
+	}
+	scorecardCommit = sha
+	if err := os.Setenv(githubSHA, sha); err != nil {
+		return fmt.Errorf("error setting %s: %w", githubSHA, err)
+	}
+	if strings.HasPrefix(ref, "refs/") {
+		if err := os.Setenv(githubRef, ref); err != nil {
+			return fmt.Errorf("error setting %s: %w", githubRef, err)
+		}
+	}
+	fmt.Printf("Analyzing %s at %s (%s).\n", repository, ref, sha)
+	return nil
+}
+
+// annotateRef is a function to record the analyzed ref and commit in the metadata of the JSON results,
+// which is published with them.
+func annotateRef(file, ref, sha string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", file, err)
+	}
+	var results map[string]interface{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return fmt.Errorf("error unmarshalling %s: %w", file, err)
+	}
+	metadata, _ := results["metadata"].([]interface{})
+	results["metadata"] = append(metadata, "ref="+ref, "commit="+sha)
+	if data, err = json.Marshal(results); err != nil {
+		return fmt.Errorf("error marshalling %s: %w", file, err)
+	}
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_validateRef(t *testing.T) {
+	subPaths := scorecardSubPaths
+	defer func() { scorecardSubPaths = subPaths }()
+	tests := []struct {
+		name      string
+		eventName string
+		subPaths  []string
+		wantErr   error
+	}{
+		{name: "Release", eventName: "release"},
+		{name: "Pull request", eventName: "pull_request", wantErr: errRefConflict},
+		{name: "Sub-paths", eventName: "push", subPaths: []string{"a"}, wantErr: errRefConflict},
+	}
+	for _, tt := range tests {
+		scorecardSubPaths = tt.subPaths
+		if err := validateRef(tt.eventName); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: validateRef() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_useRef(t *testing.T) {
+	t.Setenv(githubSHA, "workflow-sha")
+	t.Setenv(githubRef, "refs/heads/main")
+	defer func() { scorecardCommit = "" }()
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/v1.2.0" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"sha": "a1b2c3"}`)
+	}))
+
+	if err := useRef(context.Background(), client, "owner/repo", "refs/tags/v1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	if scorecardCommit != "a1b2c3" || os.Getenv(githubSHA) != "a1b2c3" || os.Getenv(githubRef) != "refs/tags/v1.2.0" {
+		t.Errorf("useRef() commit = %s, %s = %s, %s = %s", scorecardCommit, githubSHA, os.Getenv(githubSHA),
+			githubRef, os.Getenv(githubRef))
+	}
+
+	cmd, err := runScorecardSettings("release", "", "json", "scorecard", "owner/repo", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"scorecard", "--repo", "owner/repo", "--commit", "a1b2c3", "--format", "json", "--show-details"}
+	if diff := cmp.Diff(want, cmd.Args); diff != "" {
+		t.Errorf("runScorecardSettings() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_annotateRef(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "results.json")
+	content := `{"repo": {"name": "github.com/owner/repo"}, "score": 7.5, "metadata": ["team=a"]}`
+	if err := ioutil.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := annotateRef(file, "refs/tags/v1.2.0", "a1b2c3"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"repo":     map[string]interface{}{"name": "github.com/owner/repo"},
+		"score":    7.5,
+		"metadata": []interface{}{"team=a", "ref=refs/tags/v1.2.0", "commit=a1b2c3"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("annotateRef() mismatch (-want +got):\n%s", diff)
+	}
+}
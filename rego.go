@@ -0,0 +1,71 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/ossf/scorecard-action/pkg/policy"
+)
+
+const (
+	// defaultRegoQuery is the rule Rego policies define to deny results, as a set of messages.
+	defaultRegoQuery = "data.scorecard.deny"
+	defaultOPABin    = "opa"
+)
+
+var (
+	errRegoPolicyFailed = errors.New("results are denied by Rego policies")
+	errOPANotFound      = errors.New("rego_policies requires the OPA CLI")
+)
+
+// validateOPABin is a function to check the OPA CLI can be run before scorecard is, so that
+// a missing binary fails the inputs rather than the policy evaluation at the end of the run.
+func validateOPABin(opaBin string) error {
+	if _, err := exec.LookPath(opaBin); err != nil {
+		return fmt.Errorf("%w: %s was not found, set opa_bin to the path of the OPA binary: %v",
+			errOPANotFound, opaBin, err)
+	}
+	return nil
+}
+
+// evaluateRegoPolicies is a function to evaluate every Rego policy against the JSON results,
+// reporting which policies passed and failed to the writer.
+func evaluateRegoPolicies(ctx context.Context, writer io.Writer, opaBin string, policies []string,
+	dataFile, query, jsonResultsFile string) error {
+	failed := 0
+	for _, regoPolicy := range policies {
+		result, err := policy.EvaluateRego(ctx, opaBin, regoPolicy, dataFile, query, jsonResultsFile)
+		if err != nil {
+			return err
+		}
+		if len(result.Denials) == 0 {
+			fmt.Fprintf(writer, "Rego policy %s: passed\n", result.Policy)
+			continue
+		}
+		failed++
+		fmt.Fprintf(writer, "Rego policy %s: failed\n", result.Policy)
+		for _, denial := range result.Denials {
+			fmt.Fprintf(writer, "  %s\n", denial)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d policies failed", errRegoPolicyFailed, failed, len(policies))
+	}
+	return nil
+}
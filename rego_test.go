@@ -0,0 +1,83 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeOPA denies the results for policies named deny.rego, and allows them otherwise.
+const fakeOPA = `#!/bin/sh
+case "$5" in
+  *deny.rego) echo '{"result":[{"expressions":[{"value":["Dangerous-Workflow must score 10"],"text":"data.scorecard.deny"}]}]}' ;;
+  *) echo '{}' ;;
+esac
+`
+
+func Test_evaluateRegoPolicies(t *testing.T) {
+	t.Parallel()
+	opaBin := filepath.Join(t.TempDir(), "opa")
+	//nolint:gosec
+	if err := ioutil.WriteFile(opaBin, []byte(fakeOPA), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", opaBin, err)
+	}
+
+	var got bytes.Buffer
+	err := evaluateRegoPolicies(context.Background(), &got, opaBin, []string{"allow.rego", "deny.rego"},
+		"", defaultRegoQuery, "./testdata/results.json")
+	if !errors.Is(err, errRegoPolicyFailed) {
+		t.Errorf("evaluateRegoPolicies() error = %v, want %v", err, errRegoPolicyFailed)
+	}
+	want := "Rego policy allow.rego: passed\n" +
+		"Rego policy deny.rego: failed\n" +
+		"  Dangerous-Workflow must score 10\n"
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("evaluateRegoPolicies() mismatch (-want +got):\n%s", diff)
+	}
+
+	err = evaluateRegoPolicies(context.Background(), &got, opaBin, []string{"allow.rego"},
+		"", defaultRegoQuery, "./testdata/results.json")
+	if err != nil {
+		t.Errorf("evaluateRegoPolicies() error = %v", err)
+	}
+
+	err = evaluateRegoPolicies(context.Background(), &got, filepath.Join(t.TempDir(), "missing"),
+		[]string{"allow.rego"}, "", defaultRegoQuery, "./testdata/results.json")
+	if err == nil || errors.Is(err, errRegoPolicyFailed) {
+		t.Errorf("evaluateRegoPolicies() error = %v, want an execution error", err)
+	}
+}
+
+func Test_validateOPABin(t *testing.T) {
+	t.Parallel()
+	opaBin := filepath.Join(t.TempDir(), "opa")
+	//nolint:gosec
+	if err := ioutil.WriteFile(opaBin, []byte(fakeOPA), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", opaBin, err)
+	}
+	if err := validateOPABin(opaBin); err != nil {
+		t.Errorf("validateOPABin() error = %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "opa")
+	if err := validateOPABin(missing); !errors.Is(err, errOPANotFound) {
+		t.Errorf("validateOPABin() error = %v, want %v", err, errOPANotFound)
+	}
+}
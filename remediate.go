@@ -0,0 +1,303 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const remediationPinActions = "pin-actions"
+
+var (
+	errUnknownRemediation = errors.New("unknown remediation")
+	errInvalidAction      = errors.New("invalid action")
+	// usesPattern matches the uses: line of a workflow step or job, capturing the text before the action,
+	// the opening quote, the action, its ref and the rest of the line.
+	usesPattern = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s*)(["']?)([^"'\s@#]+)@([^"'\s#]+)["']?(.*)$`)
+	// commitSHAPattern matches the full commit hashes actions are pinned to.
+	commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// remediation is a mechanical fix of the findings of a check, proposed in a pull request.
+type remediation struct {
+	// check is the check whose failure the remediation fixes.
+	check string
+	// branch is the branch of the pull request, reused by later runs.
+	branch string
+	title  string
+	body   string
+	// fix returns the new content of the files of the workspace it changes, by slash separated path.
+	fix func(ctx context.Context, client *githubClient, workspace string) (map[string][]byte, error)
+}
+
+// remediations are the remediations the remediate input can enable, by name.
+var remediations = map[string]remediation{
+	remediationPinActions: {
+		check:  "Pinned-Dependencies",
+		branch: "scorecard/pin-actions",
+		title:  "Pin GitHub Actions to commit hashes",
+		body: "Scorecard's Pinned-Dependencies check found GitHub Actions referenced by tag or branch, which " +
+			"their owners can move to different code. This pull request pins them to the full commit hash " +
+			"the ref points to now, keeping the ref as a comment so that update tools can still bump them.",
+		fix: pinActions,
+	},
+}
+
+// validateRemediations is a function to check every remediation of the remediate input exists.
+func validateRemediations(names []string) error {
+	for _, name := range names {
+		if _, ok := remediations[name]; !ok {
+			known := make([]string, 0, len(remediations))
+			for k := range remediations {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("%w: %q, want one of %s", errUnknownRemediation, name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// proposeRemediations is a function to open or update a pull request for every enabled remediation
+// of a failing check that changes files.
+func proposeRemediations(ctx context.Context, writer io.Writer, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	failing := failingChecks(result)
+	client := newGitHubClient(scorecardGitHubToken)
+	repository := os.Getenv(githubRepository)
+	base := strings.TrimPrefix(scorecardDefaultBranch, "refs/heads/")
+	for _, name := range scorecardRemediations {
+		r := remediations[name]
+		if !failing[r.check] {
+			continue
+		}
+		files, err := r.fix(ctx, client, os.Getenv(githubWorkspace))
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			fmt.Fprintf(writer, "Remediation %s: nothing to fix.\n", name)
+			continue
+		}
+		number, err := openRemediationPR(ctx, client, repository, base, &r, files)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Remediation %s: proposed the fix of %d file(s) in pull request #%d.\n",
+			name, len(files), number)
+	}
+	return nil
+}
+
+// pinActions is a function to pin the actions of the workspace's workflows to the commit their ref
+// points to.
+func pinActions(ctx context.Context, client *githubClient, workspace string) (map[string][]byte, error) {
+	var workflows []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(workspace, ".github", "workflows", pattern))
+		if err != nil {
+			return nil, fmt.Errorf("error listing the workflows: %w", err)
+		}
+		workflows = append(workflows, matches...)
+	}
+
+	commits := make(map[string]string)
+	resolve := func(action, ref string) (string, error) {
+		key := action + "@" + ref
+		if sha, ok := commits[key]; ok {
+			return sha, nil
+		}
+		sha, err := resolveActionRef(ctx, client, action, ref)
+		if err != nil {
+			return "", err
+		}
+		commits[key] = sha
+		return sha, nil
+	}
+	files := make(map[string][]byte)
+	for _, workflow := range workflows {
+		content, err := ioutil.ReadFile(workflow)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", workflow, err)
+		}
+		pinned, changed, err := pinWorkflowActions(content, resolve)
+		if err != nil {
+			return nil, fmt.Errorf("error pinning the actions of %s: %w", workflow, err)
+		}
+		if !changed {
+			continue
+		}
+		rel, err := filepath.Rel(workspace, workflow)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the path of %s: %w", workflow, err)
+		}
+		files[filepath.ToSlash(rel)] = pinned
+	}
+	return files, nil
+}
+
+// pinWorkflowActions is a function to rewrite the uses: lines of a workflow referencing an action
+// by tag or branch to its commit hash, followed by the ref as a comment. Local actions and Docker
+// images are left as they are.
+func pinWorkflowActions(content []byte, resolve func(action, ref string) (string, error)) ([]byte, bool, error) {
+	lines := strings.Split(string(content), "\n")
+	changed := false
+	for i, line := range lines {
+		match := usesPattern.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+		if match == nil {
+			continue
+		}
+		prefix, quote, action, ref, rest := match[1], match[2], match[3], match[4], match[5]
+		if strings.HasPrefix(action, ".") || strings.HasPrefix(action, "docker://") || commitSHAPattern.MatchString(ref) {
+			continue
+		}
+		sha, err := resolve(action, ref)
+		if err != nil {
+			return nil, false, err
+		}
+		if strings.TrimSpace(rest) == "" {
+			rest = " # " + ref
+		}
+		lines[i] = prefix + quote + action + "@" + sha + quote + rest
+		if strings.HasSuffix(line, "\r") {
+			lines[i] += "\r"
+		}
+		changed = true
+	}
+	return []byte(strings.Join(lines, "\n")), changed, nil
+}
+
+// resolveActionRef is a function to get the commit a tag or branch of an action's repository points to.
+// Actions in a subdirectory, e.g. github/codeql-action/analyze, resolve in their repository.
+func resolveActionRef(ctx context.Context, client *githubClient, action, ref string) (string, error) {
+	parts := strings.SplitN(action, "/", 3)
+	if len(parts) < 2 {
+		return "", fmt.Errorf("%w: %q", errInvalidAction, action)
+	}
+	var commit gitObject
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s", parts[0], parts[1], url.PathEscape(ref))
+	if err := client.do(ctx, http.MethodGet, path, nil, &commit); err != nil {
+		return "", err
+	}
+	return commit.SHA, nil
+}
+
+// openRemediationPR is a function to commit the fixed files on top of the base branch to the remediation's
+// branch, force-updating it if a previous run created it, and to open its pull request unless one is open.
+// It returns the number of the pull request.
+func openRemediationPR(ctx context.Context, client *githubClient, repository, base string, r *remediation,
+	files map[string][]byte) (int, error) {
+	var baseRef struct {
+		Object gitObject `json:"object"`
+	}
+	if err := client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/git/ref/heads/%s", repository, base),
+		nil, &baseRef); err != nil {
+		return 0, err
+	}
+	var baseCommit struct {
+		Tree gitObject `json:"tree"`
+	}
+	if err := client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/git/commits/%s", repository, baseRef.Object.SHA),
+		nil, &baseCommit); err != nil {
+		return 0, err
+	}
+
+	tree := struct {
+		BaseTree string         `json:"base_tree"`
+		Tree     []gitTreeEntry `json:"tree"`
+	}{BaseTree: baseCommit.Tree.SHA}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		tree.Tree = append(tree.Tree, gitTreeEntry{Path: path, Mode: "100644", Type: "blob", Content: string(files[path])})
+	}
+	var createdTree gitObject
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/trees", repository),
+		&tree, &createdTree); err != nil {
+		return 0, err
+	}
+	commit := struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}{
+		Message: r.title,
+		Tree:    createdTree.SHA,
+		Parents: []string{baseRef.Object.SHA},
+	}
+	var createdCommit gitObject
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/commits", repository),
+		&commit, &createdCommit); err != nil {
+		return 0, err
+	}
+
+	refPath := fmt.Sprintf("/repos/%s/git/refs/heads/%s", repository, r.branch)
+	update := struct {
+		SHA   string `json:"sha"`
+		Force bool   `json:"force"`
+	}{SHA: createdCommit.SHA, Force: true}
+	err := client.do(ctx, http.MethodPatch, refPath, &update, nil)
+	if isGitHubNotFound(err) || isGitHubUnprocessable(err) {
+		ref := struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		}{Ref: "refs/heads/" + r.branch, SHA: createdCommit.SHA}
+		err = client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/refs", repository), &ref, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var pulls []struct {
+		Number int `json:"number"`
+	}
+	owner := strings.SplitN(repository, "/", 2)[0]
+	listPath := fmt.Sprintf("/repos/%s/pulls?state=open&head=%s", repository, url.QueryEscape(owner+":"+r.branch))
+	if err := client.do(ctx, http.MethodGet, listPath, nil, &pulls); err != nil {
+		return 0, err
+	}
+	if len(pulls) > 0 {
+		return pulls[0].Number, nil
+	}
+	pull := struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+	}{Title: r.title, Head: r.branch, Base: base, Body: r.body}
+	var created struct {
+		Number int `json:"number"`
+	}
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", repository), &pull, &created); err != nil {
+		return 0, err
+	}
+	return created.Number, nil
+}
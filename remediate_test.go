@@ -0,0 +1,248 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testCommitSHA = "8e5e7e5ab8b370d6c329ec480221332ada57f0ab"
+
+func Test_pinWorkflowActions(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "Tag",
+			content:     "    steps:\n      - uses: actions/checkout@v3\n",
+			want:        "    steps:\n      - uses: actions/checkout@" + testCommitSHA + " # v3\n",
+			wantChanged: true,
+		},
+		{
+			name:        "Job workflow in a subdirectory",
+			content:     "    uses: octo-org/workflows/.github/workflows/ci.yml@main\n",
+			want:        "    uses: octo-org/workflows/.github/workflows/ci.yml@" + testCommitSHA + " # main\n",
+			wantChanged: true,
+		},
+		{
+			name:        "Quoted with a comment",
+			content:     "      - uses: 'actions/checkout@v3' # checkout\r\n",
+			want:        "      - uses: 'actions/checkout@" + testCommitSHA + "' # checkout\r\n",
+			wantChanged: true,
+		},
+		{
+			name:    "Pinned",
+			content: "      - uses: actions/checkout@" + testCommitSHA + " # v3\n",
+			want:    "      - uses: actions/checkout@" + testCommitSHA + " # v3\n",
+		},
+		{
+			name:    "Local action",
+			content: "      - uses: ./.github/actions/build\n",
+			want:    "      - uses: ./.github/actions/build\n",
+		},
+		{
+			name:    "Docker image",
+			content: "      - uses: docker://alpine@3.16\n",
+			want:    "      - uses: docker://alpine@3.16\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resolve := func(action, ref string) (string, error) {
+				return testCommitSHA, nil
+			}
+			got, changed, err := pinWorkflowActions([]byte(tt.content), resolve)
+			if err != nil {
+				t.Fatalf("pinWorkflowActions() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("pinWorkflowActions() mismatch (-want +got):\n%s", diff)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("pinWorkflowActions() changed = %t, want %t", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func Test_validateRemediations(t *testing.T) {
+	t.Parallel()
+	if err := validateRemediations([]string{remediationPinActions}); err != nil {
+		t.Errorf("validateRemediations() error = %v", err)
+	}
+	if err := validateRemediations([]string{"add-security-policy"}); !errors.Is(err, errUnknownRemediation) {
+		t.Errorf("validateRemediations() error = %v, want %v", err, errUnknownRemediation)
+	}
+}
+
+func Test_pinActions(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	workflows := filepath.Join(workspace, ".github", "workflows")
+	if err := os.MkdirAll(workflows, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"ci.yml":      "steps:\n  - uses: actions/checkout@v3\n  - uses: actions/setup-go@v3\n",
+		"release.yml": "steps:\n  - uses: actions/checkout@v3\n",
+		"pinned.yaml": "steps:\n  - uses: actions/checkout@" + testCommitSHA + "\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(workflows, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		fmt.Fprintf(w, `{"sha": %q}`, testCommitSHA)
+	}))
+
+	got, err := pinActions(context.Background(), client, workspace)
+	if err != nil {
+		t.Fatalf("pinActions() error = %v", err)
+	}
+	want := map[string][]byte{
+		".github/workflows/ci.yml": []byte("steps:\n  - uses: actions/checkout@" + testCommitSHA + " # v3\n" +
+			"  - uses: actions/setup-go@" + testCommitSHA + " # v3\n"),
+		".github/workflows/release.yml": []byte("steps:\n  - uses: actions/checkout@" + testCommitSHA + " # v3\n"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("pinActions() mismatch (-want +got):\n%s", diff)
+	}
+	wantRequests := map[string]int{
+		"/repos/actions/checkout/commits/v3": 1,
+		"/repos/actions/setup-go/commits/v3": 1,
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("pinActions() requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_openRemediationPR(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name       string
+		branch     bool
+		pulls      string
+		want       int
+		wantCreate []string
+	}{
+		{
+			name:       "New branch",
+			pulls:      `[]`,
+			want:       7,
+			wantCreate: []string{"/repos/owner/repo/git/refs", "/repos/owner/repo/pulls"},
+		},
+		{
+			name:   "Existing pull request",
+			branch: true,
+			pulls:  `[{"number": 3}]`,
+			want:   3,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var created []string
+			var tree string
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/git/ref/heads/main":
+					fmt.Fprint(w, `{"object": {"sha": "base"}}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/git/commits/base":
+					fmt.Fprint(w, `{"tree": {"sha": "basetree"}}`)
+				case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/trees":
+					body, _ := ioutil.ReadAll(r.Body)
+					mu.Lock()
+					tree = string(body)
+					mu.Unlock()
+					fmt.Fprint(w, `{"sha": "tree"}`)
+				case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/commits":
+					fmt.Fprint(w, `{"sha": "commit"}`)
+				case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/git/refs/heads/scorecard/pin-actions":
+					if !tt.branch {
+						w.WriteHeader(http.StatusUnprocessableEntity)
+						fmt.Fprint(w, `{"message": "Reference does not exist"}`)
+						return
+					}
+					fmt.Fprint(w, `{}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/pulls":
+					if got := r.URL.Query().Get("head"); got != "owner:scorecard/pin-actions" {
+						t.Errorf("head = %q", got)
+					}
+					fmt.Fprint(w, tt.pulls)
+				case r.Method == http.MethodPost:
+					mu.Lock()
+					created = append(created, r.URL.Path)
+					mu.Unlock()
+					fmt.Fprint(w, `{"number": 7}`)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			r := remediations[remediationPinActions]
+			files := map[string][]byte{".github/workflows/ci.yml": []byte("pinned")}
+			got, err := openRemediationPR(context.Background(), client, "owner/repo", "main", &r, files)
+			if err != nil {
+				t.Fatalf("openRemediationPR() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("openRemediationPR() = %d, want %d", got, tt.want)
+			}
+			if diff := cmp.Diff(tt.wantCreate, created); diff != "" {
+				t.Errorf("openRemediationPR() created mismatch (-want +got):\n%s", diff)
+			}
+			var gotTree struct {
+				BaseTree string         `json:"base_tree"`
+				Tree     []gitTreeEntry `json:"tree"`
+			}
+			if err := json.Unmarshal([]byte(tree), &gotTree); err != nil {
+				t.Fatal(err)
+			}
+			if gotTree.BaseTree != "basetree" || len(gotTree.Tree) != 1 ||
+				!strings.HasSuffix(gotTree.Tree[0].Path, "ci.yml") {
+				t.Errorf("openRemediationPR() tree = %s", tree)
+			}
+		})
+	}
+}
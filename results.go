@@ -0,0 +1,152 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// inconclusiveScore is the score scorecard reports for checks that could not be evaluated.
+const inconclusiveScore = -1
+
+// resultsRenderers are the results formats scorecard does not support, which the action renders
+// from the JSON results of one or more repositories.
+var resultsRenderers = map[string]func(writer io.Writer, results []*scorecardResult) error{
+	htmlFormat:       writeHTMLReport,
+	junitFormat:      writeJUnitReport,
+	defectDojoFormat: writeDefectDojoFindings,
+	sonarQubeFormat:  writeSonarQubeIssues,
+}
+
+// scorecardResult is the subset of scorecard's JSON results used by the action.
+type scorecardResult struct {
+	Repo struct {
+		Name   string `json:"name"`
+		Commit string `json:"commit"`
+	} `json:"repo"`
+	Scorecard struct {
+		Version string `json:"version"`
+		Commit  string `json:"commit"`
+	} `json:"scorecard"`
+	Date     string        `json:"date"`
+	Checks   []checkResult `json:"checks"`
+	Metadata []string      `json:"metadata"`
+	Score    float64       `json:"score"`
+}
+
+// checkResult is the result of a single scorecard check.
+type checkResult struct {
+	Documentation struct {
+		URL   string `json:"url"`
+		Short string `json:"short"`
+	} `json:"documentation"`
+	Name    string   `json:"name"`
+	Reason  string   `json:"reason"`
+	Details []string `json:"details"`
+	Score   int      `json:"score"`
+	// Annotations are the explanations of the maintainer annotations of the check.
+	Annotations []string `json:"annotations,omitempty"`
+	// Metrics are only recorded when the check runs in its own scorecard process.
+	Metrics *checkMetrics `json:"metrics,omitempty"`
+}
+
+// readScorecardResult is a function to read scorecard's JSON results from a file.
+func readScorecardResult(path string) (*scorecardResult, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var r scorecardResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// jsonResultsFile is a function to find the results file scorecard writes JSON results to, if any.
+func jsonResultsFile(outputs []resultsOutput) (string, bool) {
+	for _, output := range outputs {
+		if output.format == "json" {
+			return output.file, true
+		}
+	}
+	return "", false
+}
+
+// hasResultsFormat is a function to check if one of the outputs is in the format.
+func hasResultsFormat(outputs []resultsOutput, format string) bool {
+	for _, output := range outputs {
+		if output.format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureJSONOutput is a function to make sure scorecard writes JSON results, adding an internal
+// results file when json is not one of the requested formats. It returns the JSON results file.
+func ensureJSONOutput(outputs []resultsOutput) ([]resultsOutput, string) {
+	if file, ok := jsonResultsFile(outputs); ok {
+		return outputs, file
+	}
+	file := filepath.Join(os.TempDir(), "scorecard-results.json")
+	return append(outputs, resultsOutput{format: "json", file: file}), file
+}
+
+// renderedOutputs is a function to check if any of the outputs is in a format the action renders.
+func renderedOutputs(outputs []resultsOutput) bool {
+	for _, output := range outputs {
+		if _, ok := resultsRenderers[output.format]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderResults is a function to write the outputs in a format the action renders from the JSON results.
+func renderResults(outputs []resultsOutput, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	for _, output := range outputs {
+		render, ok := resultsRenderers[output.format]
+		if !ok {
+			continue
+		}
+		if err := writeRenderedResults(output.file, render, []*scorecardResult{result}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRenderedResults is a function to render the results into a file.
+func writeRenderedResults(file string, render func(io.Writer, []*scorecardResult) error,
+	results []*scorecardResult) error {
+	var buf bytes.Buffer
+	if err := render(&buf, results); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"testing"
+)
+
+func Test_readScorecardResult(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name       string
+		path       string
+		wantScore  float64
+		wantChecks int
+		wantErr    bool
+	}{
+		{
+			name:       "Success - results file",
+			path:       "./testdata/results.json",
+			wantScore:  6.8,
+			wantChecks: 4,
+		},
+		{
+			name:    "Failure - non-existent file",
+			path:    "./testdata/foo.bar.json",
+			wantErr: true,
+		},
+		{
+			name:    "Failure - incorrect json",
+			path:    "./testdata/incorrect.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := readScorecardResult(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readScorecardResult() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Score != tt.wantScore {
+				t.Errorf("readScorecardResult() score = %v, want %v", got.Score, tt.wantScore)
+			}
+			if len(got.Checks) != tt.wantChecks {
+				t.Errorf("readScorecardResult() checks = %v, want %v", len(got.Checks), tt.wantChecks)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = time.Second
+)
+
+var errInvalidMaxRetries = errors.New("max_retries must be a non-negative integer")
+
+// retryPolicy is how many times, and how long after, failed GitHub API requests and scorecard runs are retried.
+type retryPolicy struct {
+	maxRetries int
+	// backoff is the delay before the first retry, doubled for every further retry.
+	backoff time.Duration
+	// maxRateLimitWait caps the total time a GitHub API request waits for rate limits to reset.
+	// Requests hitting a rate limit fail right away when it is zero.
+	maxRateLimitWait time.Duration
+}
+
+// delay is a function to get the exponential backoff, with jitter, before the retry following the attempt.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	backoff := p.backoff << attempt
+	if p.backoff <= 0 {
+		return 0
+	}
+	//nolint:gosec
+	return backoff + time.Duration(rand.Int63n(int64(p.backoff)))
+}
+
+// parseMaxRetries is a function to parse the number of retries.
+func parseMaxRetries(value string) (int, error) {
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidMaxRetries, value)
+	}
+	return retries, nil
+}
+
+// isRetryableStatus is a function to check if a GitHub API response status is likely transient.
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext is a function to wait for the duration, unless the context is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting to retry: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
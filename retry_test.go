@@ -0,0 +1,96 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_retryPolicy_delay(t *testing.T) {
+	t.Parallel()
+	policy := retryPolicy{maxRetries: 3, backoff: 100 * time.Millisecond}
+	for attempt := 0; attempt < 3; attempt++ {
+		lower := policy.backoff << attempt
+		if got := policy.delay(attempt); got < lower || got >= lower+policy.backoff {
+			t.Errorf("delay(%d) = %v, want in [%v, %v)", attempt, got, lower, lower+policy.backoff)
+		}
+	}
+	if got := (retryPolicy{}).delay(2); got != 0 {
+		t.Errorf("delay() without backoff = %v, want 0", got)
+	}
+}
+
+func Test_parseMaxRetries(t *testing.T) {
+	t.Parallel()
+	if got, err := parseMaxRetries(" 5 "); err != nil || got != 5 {
+		t.Errorf("parseMaxRetries() = %v, %v", got, err)
+	}
+	for _, value := range []string{"-1", "many"} {
+		if _, err := parseMaxRetries(value); err == nil {
+			t.Errorf("parseMaxRetries(%q) error = nil", value)
+		}
+	}
+}
+
+func Test_githubClient_retry(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "Retries transient failures",
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantRequests: 3,
+		},
+		{
+			name:         "Gives up after max retries",
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "Does not retry client errors",
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			requests := 0
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[requests])
+				requests++
+			}))
+			client.retry = retryPolicy{maxRetries: 2, backoff: time.Millisecond}
+
+			err := client.do(context.Background(), http.MethodGet, "/path", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("do() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultSARIFCategory is the category of the runs the action names itself, e.g. those of sub-paths.
+	defaultSARIFCategory = "scorecard"
+	// sarifFingerprintKey is the partialFingerprints key of the fingerprint the action adds to results.
+	sarifFingerprintKey = "scorecardFingerprint/v1"
+)
+
+var (
+	// sarifLineNumbers matches the line and column numbers following a path in finding messages.
+	sarifLineNumbers = regexp.MustCompile(`(\S):\d+(:\d+)?\b`)
+	// sarifScorePrefix matches the score that prefixes the message of a check's findings.
+	sarifScorePrefix = regexp.MustCompile(`^score is (-?\d+): `)
+)
+
+// sarifLog is the subset of a SARIF log produced by scorecard that is read by the action.
+type sarifLog struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Rules []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+						EndLine   int `json:"endLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// sarifFinding is a single location reported by a scorecard check in SARIF results.
+type sarifFinding struct {
+	check     string
+	level     string
+	message   string
+	path      string
+	startLine int
+	endLine   int
+}
+
+// readSARIFFindings is a function to read the findings of every check from a SARIF results file.
+// Findings without a location are reported with an empty path.
+func readSARIFFindings(path string) ([]sarifFinding, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", path, err)
+	}
+
+	var findings []sarifFinding
+	for i := range log.Runs {
+		run := &log.Runs[i]
+		checkNames := make(map[string]string, len(run.Tool.Driver.Rules))
+		for _, rule := range run.Tool.Driver.Rules {
+			checkNames[rule.ID] = rule.Name
+		}
+		for j := range run.Results {
+			result := &run.Results[j]
+			finding := sarifFinding{
+				check:   checkNames[result.RuleID],
+				level:   result.Level,
+				message: result.Message.Text,
+			}
+			if finding.check == "" {
+				finding.check = result.RuleID
+			}
+			if len(result.Locations) > 0 {
+				location := &result.Locations[0].PhysicalLocation
+				finding.path = location.ArtifactLocation.URI
+				finding.startLine = location.Region.StartLine
+				finding.endLine = location.Region.EndLine
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// sarifResultsFile is a function to find the results file scorecard writes SARIF results to, if any.
+func sarifResultsFile(outputs []resultsOutput) (string, bool) {
+	for _, output := range outputs {
+		if output.format == sarif {
+			return output.file, true
+		}
+	}
+	return "", false
+}
+
+// sarifAutomationID is a function to get the automationDetails.id of the runs of a category. The trailing
+// slash makes code scanning treat the ID as a category, so that runs of the category replace each other.
+func sarifAutomationID(category string) string {
+	return strings.TrimSuffix(category, "/") + "/"
+}
+
+// sarifCategory is a function to get the category of the runs of a repository or sub-path scanned
+// by the action, nested in the sarif_category input.
+func sarifCategory(name string) string {
+	category := scorecardSARIFCategory
+	if category == "" {
+		category = defaultSARIFCategory
+	}
+	return strings.TrimSuffix(category, "/") + "/" + name
+}
+
+// enhanceSARIF is a function to add stable fingerprints to the results of a SARIF results file,
+// to set the category of its runs if one is given, and to map the level of the results
+// with the SARIF levels file, if any.
+func enhanceSARIF(file, category string) error {
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return err
+	}
+	runs, _ := log["runs"].([]interface{})
+	for _, r := range runs {
+		run, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if category != "" {
+			run["automationDetails"] = map[string]string{"id": sarifAutomationID(category)}
+		}
+		addSARIFFingerprints(run)
+		if scorecardSARIFLevels != nil {
+			applySARIFLevels(run, scorecardSARIFLevels)
+		}
+	}
+	return writeJSONFile(file, log)
+}
+
+// addSARIFFingerprints is a function to add a partial fingerprint to the results of a run, so that
+// code scanning tracks a finding as the same alert across branches and commits. The fingerprint
+// hashes the rule, the file and the message without line numbers or score, which change as the code
+// around the finding does; identical findings are told apart by their occurrence in the run.
+func addSARIFFingerprints(run map[string]interface{}) {
+	results, _ := run["results"].([]interface{})
+	occurrences := make(map[string]int)
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key := sarifFingerprintSource(result)
+		occurrences[key]++
+		fingerprints, _ := result["partialFingerprints"].(map[string]interface{})
+		if fingerprints == nil {
+			fingerprints = make(map[string]interface{})
+		}
+		if _, ok := fingerprints[sarifFingerprintKey]; ok {
+			continue
+		}
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, occurrences[key])))
+		fingerprints[sarifFingerprintKey] = hex.EncodeToString(sum[:])
+		result["partialFingerprints"] = fingerprints
+	}
+}
+
+// sarifFingerprintSource is a function to get the parts of a result that identify its finding.
+func sarifFingerprintSource(result map[string]interface{}) string {
+	ruleID, _ := result["ruleId"].(string)
+	var text string
+	if message, ok := result["message"].(map[string]interface{}); ok {
+		text, _ = message["text"].(string)
+	}
+	uri := sarifResultURI(result)
+	text = sarifScorePrefix.ReplaceAllString(text, "")
+	text = sarifLineNumbers.ReplaceAllString(text, "$1")
+	return strings.Join([]string{ruleID, uri, text}, "\x00")
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_readSARIFFindings(t *testing.T) {
+	t.Parallel()
+	//nolint:lll
+	want := []sarifFinding{
+		{
+			check:     "Branch-Protection",
+			level:     "error",
+			message:   "score is 3: branch protection is not maximal on development and all release branches:\nWarn: no status checks found to merge onto branch 'main'",
+			path:      "no file associated with this alert",
+			startLine: 1,
+		},
+		{
+			check:     "Pinned-Dependencies",
+			level:     "error",
+			message:   "score is 7: dependency not pinned by hash detected -- score normalized to 7:\nWarn: non-pinned dependency",
+			path:      ".github/workflows/tests.yaml",
+			startLine: 12,
+			endLine:   12,
+		},
+	}
+	got, err := readSARIFFindings("./testdata/results.sarif")
+	if err != nil {
+		t.Fatalf("readSARIFFindings() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(sarifFinding{})); diff != "" {
+		t.Errorf("readSARIFFindings() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := readSARIFFindings("./testdata/incorrect.json"); err == nil {
+		t.Errorf("readSARIFFindings() expected an error for incorrect json")
+	}
+}
+
+func Test_sarifResultsFile(t *testing.T) {
+	t.Parallel()
+	outputs := []resultsOutput{{format: "json", file: "results.json"}, {format: "sarif", file: "results.sarif"}}
+	if got, ok := sarifResultsFile(outputs); !ok || got != "results.sarif" {
+		t.Errorf("sarifResultsFile() = %v, %v", got, ok)
+	}
+	if _, ok := sarifResultsFile(outputs[:1]); ok {
+		t.Errorf("sarifResultsFile() found a SARIF file in json-only outputs")
+	}
+}
+
+func Test_addSARIFFingerprints(t *testing.T) {
+	t.Parallel()
+	result := func(ruleID, uri, text string) interface{} {
+		return map[string]interface{}{
+			"ruleId":  ruleID,
+			"message": map[string]interface{}{"text": text},
+			"locations": []interface{}{
+				map[string]interface{}{
+					"physicalLocation": map[string]interface{}{
+						"artifactLocation": map[string]interface{}{"uri": uri},
+					},
+				},
+			},
+		}
+	}
+	fingerprints := func(results ...interface{}) []string {
+		run := map[string]interface{}{"results": results}
+		addSARIFFingerprints(run)
+		var got []string
+		for _, r := range results {
+			fingerprint, _ := r.(map[string]interface{})["partialFingerprints"].(map[string]interface{})[sarifFingerprintKey].(string)
+			got = append(got, fingerprint)
+		}
+		return got
+	}
+
+	base := fingerprints(
+		result("PinnedDependenciesID", "ci.yml", "score is 3: dependency not pinned by hash: ci.yml:12"),
+		result("PinnedDependenciesID", "ci.yml", "score is 3: dependency not pinned by hash: ci.yml:20"),
+		result("TokenPermissionsID", "ci.yml", "score is 0: topLevel 'contents' permission set to 'write': ci.yml:3"),
+	)
+	// The lines and the score changed, which must not create new alerts.
+	head := fingerprints(
+		result("PinnedDependenciesID", "ci.yml", "score is 5: dependency not pinned by hash: ci.yml:14"),
+		result("PinnedDependenciesID", "ci.yml", "score is 5: dependency not pinned by hash: ci.yml:22"),
+		result("TokenPermissionsID", "ci.yml", "score is 0: topLevel 'contents' permission set to 'write': ci.yml:3"),
+	)
+	if diff := cmp.Diff(base, head); diff != "" {
+		t.Errorf("addSARIFFingerprints() fingerprints changed (-base +head):\n%s", diff)
+	}
+	if base[0] == base[1] || base[0] == base[2] || base[0] == "" {
+		t.Errorf("addSARIFFingerprints() fingerprints are not unique: %v", base)
+	}
+
+	existing := result("TokenPermissionsID", "ci.yml", "message").(map[string]interface{})
+	existing["partialFingerprints"] = map[string]interface{}{sarifFingerprintKey: "kept", "other": "value"}
+	addSARIFFingerprints(map[string]interface{}{"results": []interface{}{existing}})
+	want := map[string]interface{}{sarifFingerprintKey: "kept", "other": "value"}
+	if diff := cmp.Diff(want, existing["partialFingerprints"]); diff != "" {
+		t.Errorf("addSARIFFingerprints() existing fingerprints mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_enhanceSARIF(t *testing.T) {
+	t.Parallel()
+	data, err := ioutil.ReadFile("./testdata/results.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "results.sarif")
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := enhanceSARIF(file, "scorecard-nightly"); err != nil {
+		t.Fatalf("enhanceSARIF() error = %v", err)
+	}
+
+	var log struct {
+		Runs []struct {
+			AutomationDetails struct {
+				ID string `json:"id"`
+			} `json:"automationDetails"`
+			Results []struct {
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := readJSONFile(file, &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) == 0 || log.Runs[0].AutomationDetails.ID != "scorecard-nightly/" {
+		t.Fatalf("enhanceSARIF() runs = %+v", log.Runs)
+	}
+	for _, result := range log.Runs[0].Results {
+		if result.PartialFingerprints[sarifFingerprintKey] == "" {
+			t.Errorf("enhanceSARIF() result without fingerprint: %+v", result)
+		}
+	}
+	// The rest of the log is kept as is.
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(data, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := readJSONFile(file, &after); err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != len(after) {
+		t.Errorf("enhanceSARIF() top-level keys = %d, want %d", len(after), len(before))
+	}
+}
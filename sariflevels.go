@@ -0,0 +1,159 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const sarifLevelsVersion = 1
+
+var errInvalidSARIFLevels = errors.New("invalid SARIF levels")
+
+// sarifLevelNames are the SARIF result levels.
+var sarifLevelNames = map[string]bool{"error": true, "warning": true, "note": true, "none": true}
+
+// checkRisks are the risk levels of the checks, named after their weight in the aggregate score.
+var checkRisks = map[float64]string{
+	10:  "Critical",
+	7.5: "High",
+	5:   "Medium",
+	2.5: "Low",
+}
+
+// sarifLevels maps the risk and the score of the checks to the level of their SARIF results.
+type sarifLevels struct {
+	// Levels are matched in order, the first matching rule sets the level.
+	Levels  []sarifLevelRule `yaml:"levels"`
+	Version int              `yaml:"version"`
+}
+
+// sarifLevelRule is the level of the results of the checks it matches. Empty conditions match any check.
+type sarifLevelRule struct {
+	// Below matches the checks scoring below it.
+	Below  *int     `yaml:"below"`
+	Level  string   `yaml:"level"`
+	Checks []string `yaml:"checks"`
+	Risks  []string `yaml:"risks"`
+}
+
+// readSARIFLevels is a function to read and validate a SARIF levels file.
+func readSARIFLevels(path string) (*sarifLevels, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var levels sarifLevels
+	if err := yaml.Unmarshal(data, &levels); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", path, err)
+	}
+	if levels.Version != sarifLevelsVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", errInvalidSARIFLevels, path, levels.Version)
+	}
+	if len(levels.Levels) == 0 {
+		return nil, fmt.Errorf("%w: %s: no levels", errInvalidSARIFLevels, path)
+	}
+	for i, rule := range levels.Levels {
+		if !sarifLevelNames[rule.Level] {
+			return nil, fmt.Errorf("%w: %s: invalid level %q in rule %d", errInvalidSARIFLevels, path, rule.Level, i+1)
+		}
+		if rule.Below != nil && (*rule.Below < 0 || *rule.Below > maxCheckScore) {
+			return nil, fmt.Errorf("%w: %s: invalid score %d in rule %d", errInvalidSARIFLevels, path, *rule.Below, i+1)
+		}
+		for _, risk := range rule.Risks {
+			if !knownRisk(risk) {
+				return nil, fmt.Errorf("%w: %s: invalid risk %q in rule %d", errInvalidSARIFLevels, path, risk, i+1)
+			}
+		}
+	}
+	return &levels, nil
+}
+
+// knownRisk is a function to check if risk is one of the risk levels of the checks.
+func knownRisk(risk string) bool {
+	for _, r := range checkRisks {
+		if strings.EqualFold(r, risk) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRisk is a function to get the risk level of a check. Custom checks have no risk level.
+func checkRisk(check string) string {
+	return checkRisks[checkRiskWeights[check]]
+}
+
+// level is a function to get the level of the results of a check with the given score,
+// and whether a rule matched.
+func (l *sarifLevels) level(check string, score int) (string, bool) {
+	for _, rule := range l.Levels {
+		if rule.Below != nil && score >= *rule.Below {
+			continue
+		}
+		if len(rule.Checks) > 0 && !containsFold(rule.Checks, check) {
+			continue
+		}
+		if len(rule.Risks) > 0 && !containsFold(rule.Risks, checkRisk(check)) {
+			continue
+		}
+		return rule.Level, true
+	}
+	return "", false
+}
+
+// containsFold is a function to check if values contain value, case-insensitively.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySARIFLevels is a function to set the level of the results of a run from the check they belong to
+// and its score, as reported in their message. Results no rule matches keep their level.
+func applySARIFLevels(run map[string]interface{}, levels *sarifLevels) {
+	checkNames := sarifCheckNames(run)
+	results, _ := run["results"].([]interface{})
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ruleID, _ := result["ruleId"].(string)
+		var text string
+		if message, ok := result["message"].(map[string]interface{}); ok {
+			text, _ = message["text"].(string)
+		}
+		match := sarifScorePrefix.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		score, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if level, ok := levels.level(checkNames[ruleID], score); ok {
+			result["level"] = level
+		}
+	}
+}
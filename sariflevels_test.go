@@ -0,0 +1,110 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_readSARIFLevels(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name      string
+		path      string
+		wantRules int
+		wantErr   error
+	}{
+		{name: "Example", path: "./policies/sarif-levels.yml", wantRules: 6},
+		{name: "Invalid level", path: "./testdata/invalid-sarif-levels.yml", wantErr: errInvalidSARIFLevels},
+		{name: "Score policy", path: "./policies/template.yml", wantErr: errInvalidSARIFLevels},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := readSARIFLevels(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readSARIFLevels() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(got.Levels) != tt.wantRules {
+				t.Errorf("readSARIFLevels() rules = %d, want %d", len(got.Levels), tt.wantRules)
+			}
+		})
+	}
+}
+
+func Test_sarifLevels_level(t *testing.T) {
+	t.Parallel()
+	levels, err := readSARIFLevels("./policies/sarif-levels.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//nolint
+	tests := []struct {
+		check string
+		score int
+		want  string
+	}{
+		{check: "Dangerous-Workflow", score: 9, want: "error"},
+		{check: "Branch-Protection", score: 3, want: "error"},
+		{check: "Branch-Protection", score: 7, want: "note"},
+		{check: "Code-Review", score: 4, want: "error"},
+		{check: "Code-Review", score: 7, want: "warning"},
+		{check: "License", score: 9, want: "note"},
+		{check: "Custom-Check", score: 2, want: "warning"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.check, func(t *testing.T) {
+			t.Parallel()
+			if got, _ := levels.level(tt.check, tt.score); got != tt.want {
+				t.Errorf("level(%s, %d) = %q, want %q", tt.check, tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applySARIFLevels(t *testing.T) {
+	t.Parallel()
+	below := 5
+	levels := &sarifLevels{Levels: []sarifLevelRule{{Below: &below, Level: "error"}}}
+	result := func(ruleID, text string) map[string]interface{} {
+		return map[string]interface{}{
+			"ruleId":  ruleID,
+			"level":   "error",
+			"message": map[string]interface{}{"text": text},
+		}
+	}
+	low := result("CodeReviewID", "score is 3: found unreviewed changesets")
+	high := result("CodeReviewID", "score is 7: found unreviewed changesets")
+	run := map[string]interface{}{
+		"tool": map[string]interface{}{
+			"driver": map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"id": "CodeReviewID", "name": "Code-Review"}},
+			},
+		},
+		"results": []interface{}{low, high},
+	}
+	applySARIFLevels(run, levels)
+	if low["level"] != "error" || high["level"] != "error" {
+		t.Errorf("applySARIFLevels() levels = %v, %v, want error, error", low["level"], high["level"])
+	}
+	levels.Levels = append(levels.Levels, sarifLevelRule{Level: "note"})
+	applySARIFLevels(run, levels)
+	if low["level"] != "error" || high["level"] != "note" {
+		t.Errorf("applySARIFLevels() levels = %v, %v, want error, note", low["level"], high["level"])
+	}
+}
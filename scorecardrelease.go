@@ -0,0 +1,189 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+const (
+	// bundledScorecardVersion is the scorecard release copied into the action's image by the Dockerfile.
+	bundledScorecardVersion = "v4.1.0"
+	// scorecardReleasesRepository is the repository scorecard releases are downloaded from.
+	scorecardReleasesRepository = "ossf/scorecard"
+)
+
+var (
+	errScorecardChecksumRequired = errors.New("scorecard_sha256 is required to download a scorecard release")
+	errInvalidScorecardChecksum  = errors.New("scorecard_sha256 must be a hex-encoded SHA-256 checksum")
+	errScorecardChecksumMismatch = errors.New("scorecard release checksum mismatch")
+	errScorecardAssetNotFound    = errors.New("no scorecard release archive for the platform")
+	errScorecardBinaryNotFound   = errors.New("no scorecard binary in the release archive")
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// scorecardRelease is the part of a GitHub release the action reads.
+type scorecardRelease struct {
+	Assets []releaseAsset `json:"assets"`
+}
+
+// releaseAsset is a file attached to a GitHub release.
+type releaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// downloadsScorecard is a function to check if the action runs a scorecard release other than the bundled one.
+func downloadsScorecard() bool {
+	return scorecardVersion != "" && scorecardVersion != bundledScorecardVersion
+}
+
+// validateScorecardVersion is a function to check a scorecard release other than the bundled one
+// is pinned to the checksum of its archive, so that the binary the action runs is reproducible.
+func validateScorecardVersion(version, checksum string) error {
+	if version == "" || version == bundledScorecardVersion {
+		return nil
+	}
+	if checksum == "" {
+		return fmt.Errorf("%w: %s", errScorecardChecksumRequired, version)
+	}
+	if !sha256Pattern.MatchString(checksum) {
+		return fmt.Errorf("%w: %q", errInvalidScorecardChecksum, checksum)
+	}
+	return nil
+}
+
+// installScorecard is a function to download the archive of a scorecard release for the current platform,
+// verify its checksum and extract the scorecard binary. It returns the path of the binary.
+func installScorecard(ctx context.Context, writer io.Writer, client *githubClient, version,
+	checksum string) (string, error) {
+	var release scorecardRelease
+	p := fmt.Sprintf("/repos/%s/releases/tags/%s", scorecardReleasesRepository, url.PathEscape(version))
+	if err := client.do(ctx, http.MethodGet, p, nil, &release); err != nil {
+		return "", fmt.Errorf("error getting scorecard release %s: %w", version, err)
+	}
+	asset, err := scorecardAsset(release.Assets, runtime.GOOS, runnerGOARCH(os.Getenv(runnerArch)))
+	if err != nil {
+		return "", fmt.Errorf("scorecard release %s: %w", version, err)
+	}
+	archive, err := client.download(ctx, asset.BrowserDownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("error downloading %s: %w", asset.Name, err)
+	}
+	if err := verifyChecksum(archive, checksum); err != nil {
+		return "", fmt.Errorf("%s: %w", asset.Name, err)
+	}
+
+	dir, err := ioutil.TempDir("", "scorecard-")
+	if err != nil {
+		return "", fmt.Errorf("error creating directory: %w", err)
+	}
+	bin := filepath.Join(dir, "scorecard")
+	if err := extractScorecardBinary(archive, bin); err != nil {
+		return "", fmt.Errorf("%s: %w", asset.Name, err)
+	}
+	fmt.Fprintf(writer, "Using scorecard %s from %s (sha256 %s).\n", version, asset.Name, strings.ToLower(checksum))
+	return bin, nil
+}
+
+// scorecardAsset is a function to find the .tar.gz archive of the release for the platform,
+// e.g. scorecard_4.10.2_linux_amd64.tar.gz.
+func scorecardAsset(assets []releaseAsset, goos, goarch string) (releaseAsset, error) {
+	suffix := fmt.Sprintf("_%s_%s.tar.gz", goos, goarch)
+	for _, asset := range assets {
+		if strings.HasSuffix(asset.Name, suffix) {
+			return asset, nil
+		}
+	}
+	return releaseAsset{}, fmt.Errorf("%w: %s/%s", errScorecardAssetNotFound, goos, goarch)
+}
+
+// verifyChecksum is a function to check the SHA-256 checksum of the data is the expected one.
+func verifyChecksum(data []byte, expected string) error {
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: got %s, want %s", errScorecardChecksumMismatch, actual, strings.ToLower(expected))
+	}
+	return nil
+}
+
+// extractScorecardBinary is a function to write the executable scorecard file of a .tar.gz archive to dest.
+func extractScorecardBinary(archive []byte, dest string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return fmt.Errorf("error opening archive: %w", err)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return errScorecardBinaryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasPrefix(path.Base(header.Name), "scorecard") ||
+			header.FileInfo().Mode()&0o111 == 0 {
+			continue
+		}
+		//nolint:gosec
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %w", dest, err)
+		}
+		//nolint:gosec
+		if _, err := io.Copy(f, reader); err != nil {
+			f.Close()
+			return fmt.Errorf("error extracting %s: %w", header.Name, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("error closing %s: %w", dest, err)
+		}
+		return nil
+	}
+}
+
+// newReleaseClient is a function to create a client for the github.com API, where scorecard is released,
+// even when the action runs on GitHub Enterprise Server. The token is only sent to the instance it belongs to.
+func newReleaseClient(token string) *githubClient {
+	client := &githubClient{
+		httpClient: scorecardHTTPClient,
+		baseURL:    defaultGitHubAPIURL,
+		retry:      scorecardRetryPolicy,
+	}
+	if githubAPIBaseURL() == defaultGitHubAPIURL {
+		client.token = token
+	}
+	return client
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newTestArchive is a function to create a .tar.gz archive of the files, with their modes.
+func newTestArchive(t *testing.T, files map[string]int64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, mode := range files {
+		content := []byte("content of " + name)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_validateScorecardVersion(t *testing.T) {
+	t.Parallel()
+	checksum := strings.Repeat("a1", 32)
+	//nolint
+	tests := []struct {
+		name     string
+		version  string
+		checksum string
+		wantErr  error
+	}{
+		{name: "Bundled by default"},
+		{name: "Bundled release", version: bundledScorecardVersion},
+		{name: "Pinned release", version: "v4.10.2", checksum: checksum},
+		{name: "Uppercase checksum", version: "v4.10.2", checksum: strings.ToUpper(checksum)},
+		{name: "Unpinned release", version: "v4.10.2", wantErr: errScorecardChecksumRequired},
+		{name: "Short checksum", version: "v4.10.2", checksum: "a1b2", wantErr: errInvalidScorecardChecksum},
+		{name: "Not hex", version: "v4.10.2", checksum: strings.Repeat("zz", 32), wantErr: errInvalidScorecardChecksum},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateScorecardVersion(tt.version, tt.checksum); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateScorecardVersion() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_scorecardAsset(t *testing.T) {
+	t.Parallel()
+	assets := []releaseAsset{
+		{Name: "scorecard_4.10.2_checksums.txt"},
+		{Name: "scorecard_4.10.2_darwin_amd64.tar.gz"},
+		{Name: "scorecard_4.10.2_linux_amd64.tar.gz"},
+		{Name: "scorecard_4.10.2_linux_arm64.tar.gz"},
+	}
+	asset, err := scorecardAsset(assets, "linux", "arm64")
+	if err != nil || asset.Name != "scorecard_4.10.2_linux_arm64.tar.gz" {
+		t.Errorf("scorecardAsset() = %v, %v", asset.Name, err)
+	}
+	if _, err := scorecardAsset(assets, "windows", "amd64"); !errors.Is(err, errScorecardAssetNotFound) {
+		t.Errorf("scorecardAsset() error = %v, want %v", err, errScorecardAssetNotFound)
+	}
+}
+
+func Test_extractScorecardBinary(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		files   map[string]int64
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Binary",
+			files: map[string]int64{"LICENSE": 0o644, "README.md": 0o644, "scorecard-linux-amd64": 0o755},
+			want:  "content of scorecard-linux-amd64",
+		},
+		{
+			name:    "No executable",
+			files:   map[string]int64{"LICENSE": 0o644, "scorecard.md": 0o644},
+			wantErr: errScorecardBinaryNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dest := filepath.Join(t.TempDir(), "scorecard")
+			err := extractScorecardBinary(newTestArchive(t, tt.files), dest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("extractScorecardBinary() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			data, err := ioutil.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("extractScorecardBinary() wrote %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func Test_installScorecard(t *testing.T) {
+	t.Parallel()
+	archive := newTestArchive(t, map[string]int64{"scorecard": 0o755})
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	name := fmt.Sprintf("scorecard_4.10.2_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	//nolint
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  error
+	}{
+		{name: "Verified", checksum: checksum},
+		{name: "Mismatch", checksum: strings.Repeat("0", 64), wantErr: errScorecardChecksumMismatch},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/repos/ossf/scorecard/releases/tags/v4.10.2":
+					fmt.Fprintf(w, `{"assets": [{"name": %q, "browser_download_url": "http://%s/download/%s"}]}`,
+						name, r.Host, name)
+				case "/download/" + name:
+					w.Write(archive) //nolint:errcheck
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			var out bytes.Buffer
+			bin, err := installScorecard(context.Background(), &out, client, "v4.10.2", tt.checksum)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("installScorecard() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if data, err := ioutil.ReadFile(bin); err != nil || string(data) != "content of scorecard" {
+				t.Errorf("installScorecard() binary = %q, %v", data, err)
+			}
+			if !strings.Contains(out.String(), "Using scorecard v4.10.2") {
+				t.Errorf("installScorecard() output = %q", out.String())
+			}
+		})
+	}
+}
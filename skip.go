@@ -0,0 +1,215 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runStateFile is the file the state of the last analyzed run is stored in, in the cache directory
+// or the history directory of the history branch.
+const runStateFile = "last-run.json"
+
+var errSkipNeedsState = errors.New("skip_unchanged requires cache_dir or save_history to store the last analyzed state")
+
+// volatileRepositoryFields are the fields of the repository that change without its settings changing,
+// besides the counters and timestamps.
+var volatileRepositoryFields = map[string]bool{
+	"forks":            true,
+	"open_issues":      true,
+	"watchers":         true,
+	"size":             true,
+	"temp_clone_token": true,
+}
+
+// secretInputMarkers mark the inputs left out of the state, since their value may change every run,
+// like the GITHUB_TOKEN, or must not be stored.
+var secretInputMarkers = []string{"TOKEN", "KEY", "SECRET", "URL"}
+
+// runState is the state of the repository a run analyzed.
+type runState struct {
+	Commit string `json:"commit"`
+	// Hash covers the commit, the settings of the repository and the inputs of the action.
+	Hash string `json:"hash"`
+	Date string `json:"date"`
+}
+
+// currentRunState is a function to get the state of the repository the run analyzes: the head of the default
+// branch, the settings of the repository and its default branch, and the inputs of the action.
+func currentRunState(ctx context.Context, client *githubClient, repository, branch string) (runState, error) {
+	var settings json.RawMessage
+	if err := client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s", repository), nil, &settings); err != nil {
+		return runState{}, err
+	}
+	var head struct {
+		Commit    gitObject       `json:"commit"`
+		Protected bool            `json:"protected"`
+		Rules     json.RawMessage `json:"protection"`
+	}
+	if err := client.do(ctx, http.MethodGet,
+		fmt.Sprintf("/repos/%s/branches/%s", repository, url.PathEscape(branch)), nil, &head); err != nil {
+		return runState{}, err
+	}
+	var repo map[string]interface{}
+	if err := json.Unmarshal(settings, &repo); err != nil {
+		return runState{}, fmt.Errorf("error unmarshalling the repository: %w", err)
+	}
+	for key := range repo {
+		if strings.HasSuffix(key, "_count") || strings.HasSuffix(key, "_at") || volatileRepositoryFields[key] {
+			delete(repo, key)
+		}
+	}
+	parts := []string{head.Commit.SHA, fmt.Sprint(head.Protected), string(head.Rules)}
+	data, err := json.Marshal(repo)
+	if err != nil {
+		return runState{}, fmt.Errorf("error marshalling the repository: %w", err)
+	}
+	parts = append(parts, string(data))
+	parts = append(parts, actionInputs(os.Environ())...)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return runState{
+		Commit: head.Commit.SHA,
+		Hash:   hex.EncodeToString(sum[:]),
+		Date:   time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// actionInputs is a function to get the sorted inputs of the action, but the secret ones.
+func actionInputs(environ []string) []string {
+	var inputs []string
+	for _, env := range environ {
+		name := strings.SplitN(env, "=", 2)[0]
+		if !strings.HasPrefix(name, "INPUT_") || containsAny(name, secretInputMarkers) {
+			continue
+		}
+		inputs = append(inputs, env)
+	}
+	sort.Strings(inputs)
+	return inputs
+}
+
+// containsAny is a function to check if s contains any of the substrings.
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// readRunState is a function to read the state of the last analyzed run from the cache directory, or else
+// from the history branch. It returns nil if no run was analyzed yet.
+func readRunState(ctx context.Context, client *githubClient, repository string) (*runState, error) {
+	var data []byte
+	if scorecardCacheDir != "" {
+		var err error
+		data, err = ioutil.ReadFile(filepath.Join(scorecardCacheDir, runStateFile))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the last run state: %w", err)
+		}
+	} else {
+		var content fileContent
+		p := fmt.Sprintf("/repos/%s/contents/%s?ref=%s", repository, path.Join(scorecardHistoryPath, runStateFile),
+			url.QueryEscape(scorecardHistoryBranch))
+		err := client.do(ctx, http.MethodGet, p, nil, &content)
+		if isGitHubNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if data, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", "")); err != nil {
+			return nil, fmt.Errorf("error decoding the last run state: %w", err)
+		}
+	}
+	var state runState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the last run state: %w", err)
+	}
+	return &state, nil
+}
+
+// writeRunState is a function to store the state of the analyzed run in the cache directory, or else
+// in the history branch.
+func writeRunState(ctx context.Context, client *githubClient, repository string, state runState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the run state: %w", err)
+	}
+	if scorecardCacheDir != "" {
+		if err := os.MkdirAll(scorecardCacheDir, 0o755); err != nil {
+			return fmt.Errorf("error creating %s: %w", scorecardCacheDir, err)
+		}
+		file := filepath.Join(scorecardCacheDir, runStateFile)
+		if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+			return fmt.Errorf("error writing %s: %w", file, err)
+		}
+		return nil
+	}
+	if err := ensureHistoryBranch(ctx, client, repository, scorecardHistoryBranch); err != nil {
+		return err
+	}
+	return commitFile(ctx, client, repository, scorecardHistoryBranch, path.Join(scorecardHistoryPath, runStateFile),
+		fmt.Sprintf("Analyze %s", state.Commit), data)
+}
+
+// skipUnchanged is a function to check if the repository is in the state the last run analyzed, in which case
+// the run is skipped, reporting it in the skipped output. It returns the current state, to store once the run
+// succeeded.
+func skipUnchanged(ctx context.Context, writer io.Writer, token string) (runState, bool, error) {
+	repository := os.Getenv(githubRepository)
+	state, err := currentRunState(ctx, newGitHubClient(token), repository,
+		strings.TrimPrefix(scorecardDefaultBranch, "refs/heads/"))
+	if err != nil {
+		return runState{}, false, err
+	}
+	last, err := readRunState(ctx, newGitHubClient(scorecardGitHubToken), repository)
+	if err != nil {
+		return runState{}, false, err
+	}
+	if last == nil || last.Hash != state.Hash {
+		return state, false, nil
+	}
+	fmt.Fprintf(writer, "Nothing changed since the run of %s analyzing %s, skipping the run.\n", last.Date, last.Commit)
+	if outputFile := os.Getenv(githubOutput); outputFile != "" {
+		//nolint:gosec
+		f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return runState{}, false, fmt.Errorf("error opening %s: %w", outputFile, err)
+		}
+		defer f.Close()
+		fmt.Fprintf(f, "skipped=true\n")
+	}
+	return state, true, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_actionInputs(t *testing.T) {
+	t.Parallel()
+	environ := []string{
+		"INPUT_RESULTS_FORMAT=sarif",
+		"PATH=/usr/bin",
+		"INPUT_REPO_TOKEN=secret",
+		"INPUT_APP_PRIVATE_KEY=secret",
+		"INPUT_NOTIFY_WEBHOOK_URL=https://hooks.example.com/secret",
+		"INPUT_CHECKS=Pinned-Dependencies",
+	}
+	want := []string{"INPUT_CHECKS=Pinned-Dependencies", "INPUT_RESULTS_FORMAT=sarif"}
+	if diff := cmp.Diff(want, actionInputs(environ)); diff != "" {
+		t.Errorf("actionInputs() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_currentRunState(t *testing.T) {
+	t.Parallel()
+	state := func(repository, commit string) runState {
+		client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/repos/owner/repo":
+				fmt.Fprint(w, repository)
+			case "/repos/owner/repo/branches/main":
+				fmt.Fprintf(w, `{"commit": {"sha": %q}, "protected": true, "protection": {"enabled": true}}`, commit)
+			default:
+				t.Errorf("unexpected request %s", r.URL.Path)
+			}
+		}))
+		got, err := currentRunState(context.Background(), client, "owner/repo", "main")
+		if err != nil {
+			t.Fatalf("currentRunState() error = %v", err)
+		}
+		return got
+	}
+
+	base := state(`{"private": false, "stargazers_count": 10, "pushed_at": "2022-08-01T00:00:00Z", "forks": 2}`, "abc")
+	if base.Commit != "abc" || base.Hash == "" {
+		t.Errorf("currentRunState() = %+v", base)
+	}
+	starred := state(`{"private": false, "stargazers_count": 11, "pushed_at": "2022-08-02T00:00:00Z", "forks": 3}`, "abc")
+	if starred.Hash != base.Hash {
+		t.Errorf("currentRunState() hash changed with the counters and timestamps")
+	}
+	if pushed := state(`{"private": false, "stargazers_count": 10}`, "def"); pushed.Hash == base.Hash {
+		t.Errorf("currentRunState() hash did not change with the commit")
+	}
+	if private := state(`{"private": true, "stargazers_count": 10}`, "abc"); private.Hash == base.Hash {
+		t.Errorf("currentRunState() hash did not change with the settings")
+	}
+}
+
+func Test_runState_cacheDir(t *testing.T) {
+	// not setting t.Parallel() here because we are mutating the env variables
+	//nolint
+	dir := scorecardCacheDir
+	defer func() { scorecardCacheDir = dir }()
+	scorecardCacheDir = t.TempDir()
+
+	ctx := context.Background()
+	last, err := readRunState(ctx, nil, "owner/repo")
+	if err != nil || last != nil {
+		t.Fatalf("readRunState() = %v, %v, want no state", last, err)
+	}
+	state := runState{Commit: "abc", Hash: "hash", Date: "2022-08-01T00:00:00Z"}
+	if err := writeRunState(ctx, nil, "owner/repo", state); err != nil {
+		t.Fatalf("writeRunState() error = %v", err)
+	}
+	last, err = readRunState(ctx, nil, "owner/repo")
+	if err != nil {
+		t.Fatalf("readRunState() error = %v", err)
+	}
+	if diff := cmp.Diff(&state, last); diff != "" {
+		t.Errorf("readRunState() mismatch (-want +got):\n%s", diff)
+	}
+}
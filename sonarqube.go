@@ -0,0 +1,156 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	sonarQubeFormat = "sonarqube"
+	// sonarQubeEngineID is the engine the issues are reported by in SonarQube.
+	sonarQubeEngineID = "scorecard"
+	// defaultSonarQubeFile is the file of the issues of checks without a location, when the action
+	// does not know its own workflow file.
+	defaultSonarQubeFile = "README.md"
+)
+
+// sonarQubeLocation matches the path and line that end a finding, e.g. "...: .github/workflows/ci.yml:10".
+var sonarQubeLocation = regexp.MustCompile(`^(.*): ([^\s:]+)(?::(\d+))?(?::\d+)?$`)
+
+// sonarQubeIssues is the root of a report in SonarQube's generic external issues format.
+type sonarQubeIssues struct {
+	Issues []sonarQubeIssue `json:"issues"`
+}
+
+// sonarQubeIssue is an external issue, located at the file of a finding.
+type sonarQubeIssue struct {
+	EngineID        string                 `json:"engineId"`
+	RuleID          string                 `json:"ruleId"`
+	Severity        string                 `json:"severity"`
+	Type            string                 `json:"type"`
+	PrimaryLocation sonarQubeIssueLocation `json:"primaryLocation"`
+}
+
+// sonarQubeIssueLocation is the message and file of an issue.
+type sonarQubeIssueLocation struct {
+	TextRange *sonarQubeTextRange `json:"textRange,omitempty"`
+	Message   string              `json:"message"`
+	FilePath  string              `json:"filePath"`
+}
+
+// sonarQubeTextRange is the line of an issue.
+type sonarQubeTextRange struct {
+	StartLine int `json:"startLine"`
+}
+
+// writeSonarQubeIssues is a function to render the results as SonarQube generic external issues,
+// for the sonar.externalIssuesReportPaths analysis parameter.
+func writeSonarQubeIssues(writer io.Writer, results []*scorecardResult) error {
+	data, err := json.MarshalIndent(newSonarQubeIssues(results, sonarQubeWorkflowFile()), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the SonarQube issues: %w", err)
+	}
+	if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
+		return fmt.Errorf("error writing the SonarQube issues: %w", err)
+	}
+	return nil
+}
+
+// newSonarQubeIssues is a function to convert the results to SonarQube issues: an issue per finding
+// of a check scoring below the maximum, located at the file and line the finding names. SonarQube
+// needs a file for every issue, so the checks without located findings, e.g. Branch-Protection,
+// have a single issue on the given file instead.
+func newSonarQubeIssues(results []*scorecardResult, file string) sonarQubeIssues {
+	report := sonarQubeIssues{Issues: []sonarQubeIssue{}}
+	for _, result := range results {
+		for i := range result.Checks {
+			check := &result.Checks[i]
+			if check.Score == inconclusiveScore || check.Score == maxCheckScore {
+				continue
+			}
+			issue := sonarQubeIssue{
+				EngineID: sonarQubeEngineID,
+				RuleID:   check.Name,
+				Severity: sonarQubeSeverity(check.Score),
+				Type:     "VULNERABILITY",
+			}
+			located := false
+			for _, detail := range check.Details {
+				if !strings.HasPrefix(detail, findingPrefix) {
+					continue
+				}
+				location, ok := sonarQubeFindingLocation(strings.TrimPrefix(detail, findingPrefix))
+				if !ok {
+					continue
+				}
+				location.Message = fmt.Sprintf("%s: %s", check.Name, location.Message)
+				issue.PrimaryLocation = location
+				report.Issues = append(report.Issues, issue)
+				located = true
+			}
+			if !located {
+				issue.PrimaryLocation = sonarQubeIssueLocation{
+					Message:  fmt.Sprintf("%s scored %d/%d: %s", check.Name, check.Score, maxCheckScore, check.Reason),
+					FilePath: file,
+				}
+				report.Issues = append(report.Issues, issue)
+			}
+		}
+	}
+	return report
+}
+
+// sonarQubeFindingLocation is a function to split a finding into its message and the file and line
+// it ends with. Findings that do not end with a path are not located.
+func sonarQubeFindingLocation(finding string) (sonarQubeIssueLocation, bool) {
+	match := sonarQubeLocation.FindStringSubmatch(finding)
+	if match == nil || !strings.ContainsAny(match[2], "./") || strings.Contains(match[2], "://") {
+		return sonarQubeIssueLocation{}, false
+	}
+	location := sonarQubeIssueLocation{Message: match[1], FilePath: strings.TrimPrefix(match[2], "./")}
+	if line, err := strconv.Atoi(match[3]); err == nil && line > 0 {
+		location.TextRange = &sonarQubeTextRange{StartLine: line}
+	}
+	return location, true
+}
+
+// sonarQubeWorkflowFile is a function to get the path of the workflow running the action, which is in
+// the analyzed repository, from GITHUB_WORKFLOW_REF, e.g. owner/repo/.github/workflows/scorecard.yml@refs/heads/main.
+func sonarQubeWorkflowFile() string {
+	ref := os.Getenv(githubWorkflowRef)
+	if i := strings.Index(ref, "/.github/workflows/"); i >= 0 {
+		return strings.SplitN(ref[i+1:], "@", 2)[0]
+	}
+	return defaultSonarQubeFile
+}
+
+// sonarQubeSeverity is a function to map the score of a failing check to a SonarQube severity:
+// the lower the score, the higher the severity.
+func sonarQubeSeverity(score int) string {
+	switch {
+	case score < 3:
+		return "CRITICAL"
+	case score < 6:
+		return "MAJOR"
+	default:
+		return "MINOR"
+	}
+}
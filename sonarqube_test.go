@@ -0,0 +1,146 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newSonarQubeIssues(t *testing.T) {
+	t.Parallel()
+	result := newTestResult(6,
+		checkResult{Name: "Binary-Artifacts", Reason: "no binaries found", Score: 10},
+		checkResult{
+			Name:   "Pinned-Dependencies",
+			Reason: "dependency not pinned by hash detected",
+			Details: []string{
+				"Warn: third-party GitHubAction not pinned by hash: .github/workflows/ci.yml:10",
+				"Warn: pipCommand not pinned by hash: ./Dockerfile:3:5",
+				"Info: GitHub-owned GitHubActions are pinned",
+			},
+			Score: 7,
+		},
+		checkResult{
+			Name:    "Branch-Protection",
+			Reason:  "branch protection not enabled on development/release branches",
+			Details: []string{"Warn: branch protection not enabled for branch 'main'"},
+			Score:   0,
+		},
+		checkResult{Name: "Fuzzing", Reason: "internal error", Score: inconclusiveScore},
+	)
+
+	got := newSonarQubeIssues([]*scorecardResult{result}, ".github/workflows/scorecard.yml")
+	want := sonarQubeIssues{Issues: []sonarQubeIssue{
+		{
+			EngineID: sonarQubeEngineID,
+			RuleID:   "Pinned-Dependencies",
+			Severity: "MINOR",
+			Type:     "VULNERABILITY",
+			PrimaryLocation: sonarQubeIssueLocation{
+				Message:   "Pinned-Dependencies: third-party GitHubAction not pinned by hash",
+				FilePath:  ".github/workflows/ci.yml",
+				TextRange: &sonarQubeTextRange{StartLine: 10},
+			},
+		},
+		{
+			EngineID: sonarQubeEngineID,
+			RuleID:   "Pinned-Dependencies",
+			Severity: "MINOR",
+			Type:     "VULNERABILITY",
+			PrimaryLocation: sonarQubeIssueLocation{
+				Message:   "Pinned-Dependencies: pipCommand not pinned by hash",
+				FilePath:  "Dockerfile",
+				TextRange: &sonarQubeTextRange{StartLine: 3},
+			},
+		},
+		{
+			EngineID: sonarQubeEngineID,
+			RuleID:   "Branch-Protection",
+			Severity: "CRITICAL",
+			Type:     "VULNERABILITY",
+			PrimaryLocation: sonarQubeIssueLocation{
+				Message:  "Branch-Protection scored 0/10: branch protection not enabled on development/release branches",
+				FilePath: ".github/workflows/scorecard.yml",
+			},
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newSonarQubeIssues() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_sonarQubeFindingLocation(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		finding string
+		want    sonarQubeIssueLocation
+		wantOK  bool
+	}{
+		{
+			finding: "binary detected: bin/tool",
+			want:    sonarQubeIssueLocation{Message: "binary detected", FilePath: "bin/tool"},
+			wantOK:  true,
+		},
+		{
+			finding: "dangerous workflow pattern: .github/workflows/pr.yml:0",
+			want:    sonarQubeIssueLocation{Message: "dangerous workflow pattern", FilePath: ".github/workflows/pr.yml"},
+			wantOK:  true,
+		},
+		{finding: "no SAST tool detected"},
+		{finding: "number of required reviewers is only 1"},
+		{finding: "project is not fuzzed: see https://example.com"},
+		{finding: "token permissions: write-all"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.finding, func(t *testing.T) {
+			t.Parallel()
+			got, ok := sonarQubeFindingLocation(tt.finding)
+			if ok != tt.wantOK {
+				t.Fatalf("sonarQubeFindingLocation() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("sonarQubeFindingLocation() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// not setting t.Parallel() here because we are mutating GITHUB_WORKFLOW_REF
+//nolint
+func Test_writeSonarQubeIssues(t *testing.T) {
+	t.Setenv(githubWorkflowRef, "ossf/scorecard-action/.github/workflows/scorecard.yml@refs/heads/main")
+	result := newTestResult(6, checkResult{Name: "Code-Review", Reason: "no reviews", Score: 0})
+	var buf bytes.Buffer
+	if err := writeSonarQubeIssues(&buf, []*scorecardResult{result}); err != nil {
+		t.Fatalf("writeSonarQubeIssues() error = %v", err)
+	}
+	var report sonarQubeIssues
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("writeSonarQubeIssues() wrote invalid JSON: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].PrimaryLocation.FilePath != ".github/workflows/scorecard.yml" {
+		t.Errorf("writeSonarQubeIssues() = %s", buf.String())
+	}
+
+	t.Setenv(githubWorkflowRef, "")
+	if got := sonarQubeWorkflowFile(); got != defaultSonarQubeFile {
+		t.Errorf("sonarQubeWorkflowFile() = %v, want %v", got, defaultSonarQubeFile)
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errInvalidCheckTimeout = errors.New("invalid timeout")
+	errAllChecksTimedOut   = errors.New("every check timed out")
+)
+
+// localChecks are the checks scorecard can run on a local folder.
+var localChecks = []string{
+	"Binary-Artifacts",
+	"Dangerous-Workflow",
+	"Dependency-Update-Tool",
+	"License",
+	"Pinned-Dependencies",
+	"Token-Permissions",
+}
+
+// checkRiskWeights are the weights of scorecard's aggregate score, by the risk of each check.
+var checkRiskWeights = map[string]float64{
+	"Binary-Artifacts":       7.5,
+	"Branch-Protection":      7.5,
+	"CI-Tests":               2.5,
+	"CII-Best-Practices":     2.5,
+	"Code-Review":            7.5,
+	"Contributors":           2.5,
+	"Dangerous-Workflow":     10,
+	"Dependency-Update-Tool": 7.5,
+	"Fuzzing":                5,
+	"License":                2.5,
+	"Maintained":             7.5,
+	"Packaging":              5,
+	"Pinned-Dependencies":    5,
+	"SAST":                   5,
+	"Security-Policy":        5,
+	"Signed-Releases":        7.5,
+	"Token-Permissions":      7.5,
+	"Vulnerabilities":        7.5,
+}
+
+// checkTimeouts are the timeouts of checks run in separate scorecard processes.
+type checkTimeouts struct {
+	perCheck map[string]time.Duration
+	// timeout applies to the checks without their own timeout. Zero means no timeout.
+	timeout time.Duration
+}
+
+// of is a function to get the timeout of a check.
+func (t checkTimeouts) of(check string) time.Duration {
+	if timeout, ok := t.perCheck[check]; ok {
+		return timeout
+	}
+	return t.timeout
+}
+
+// checkOutcome is the results file of a check run in its own scorecard process.
+type checkOutcome struct {
+	check   string
+	file    string
+	metrics *checkMetrics
+	// err is the error of a check that failed, when the run continues on check errors.
+	err     error
+	timeout time.Duration
+	// incomplete is whether the check has no results: it timed out or failed.
+	incomplete bool
+	// canceled is whether the run timeout stopped the check, or the check did not start before it.
+	canceled bool
+}
+
+// incompleteReason is a function to explain why a check has no results.
+func (o *checkOutcome) incompleteReason() string {
+	if o.err != nil {
+		return fmt.Sprintf("check failed: %v", o.err)
+	}
+	if o.canceled {
+		return "check did not complete before the run timed out"
+	}
+	return fmt.Sprintf("check timed out after %s", o.timeout)
+}
+
+// parseCheckTimeouts is a function to parse comma-separated check=duration timeouts, e.g. CI-Tests=30m.
+func parseCheckTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range splitList(value) {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q is not check=duration", errInvalidCheckTimeout, entry)
+		}
+		check, err := canonicalCheckName(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := parseTimeout(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		timeouts[check] = timeout
+	}
+	return timeouts, nil
+}
+
+// parseTimeout is a function to parse a positive duration, e.g. 10m.
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidCheckTimeout, value)
+	}
+	return timeout, nil
+}
+
+// commandChecks is a function to get the checks a scorecard command runs: the checks it selects,
+// or every check scorecard can run on its target.
+func commandChecks(args []string) []string {
+	for i := range args {
+		if args[i] == "--checks" && i+1 < len(args) {
+			return strings.Split(args[i+1], ",")
+		}
+	}
+	if isLocalRun(args) {
+		return localChecks
+	}
+	return knownChecks
+}
+
+// isLocalRun is a function to check if a scorecard command runs on a local folder.
+func isLocalRun(args []string) bool {
+	for _, arg := range args {
+		if arg == "--local" {
+			return true
+		}
+	}
+	return false
+}
+
+// withCheck is a function to get the arguments of a scorecard command that only runs the check.
+func withCheck(args []string, check string) []string {
+	result := make([]string, 0, len(args)+2)
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--checks" {
+			i++
+			continue
+		}
+		result = append(result, args[i])
+	}
+	return append(result, "--checks", check)
+}
+
+// runScorecardPerCheck is a function to run the scorecard command once per check, so that each check
+// gets its own timeout, with at most parallelism checks running at once, and merge the results of the checks
+// into the results file. A check that times out is reported as inconclusive instead of failing the run.
+// The JSON results record the duration of each check and, with a client and a parallelism of 1,
+// the GitHub API quota it used. When ctx is done, e.g. the run timed out, the checks that did not complete
+// are reported as inconclusive, so that the results computed so far are kept. With continueOnError,
+// so are the checks that fail, instead of failing the run.
+func runScorecardPerCheck(ctx context.Context, cmd *exec.Cmd, output resultsOutput, timeouts checkTimeouts,
+	parallelism int, client *githubClient, continueOnError bool) error {
+	checks := commandChecks(cmd.Args)
+	meter := checkMeter{client: client, countsAPICalls: parallelism == 1}
+	outcomes := make([]checkOutcome, len(checks))
+	errs := make([]error, len(checks))
+	progress := newCheckProgress(os.Stderr, len(checks))
+	stopHeartbeat := startHeartbeat(progress.heartbeat)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outcomes[i] = checkOutcome{
+					check:   checks[i],
+					file:    filepath.Join(os.TempDir(), fmt.Sprintf("scorecard-%s.%s", checks[i], output.format)),
+					timeout: timeouts.of(checks[i]),
+				}
+				if ctx.Err() != nil {
+					outcomes[i].incomplete, outcomes[i].canceled = true, true
+					progress.finished(checks[i], "skipped", nil)
+					continue
+				}
+				progress.started(checks[i])
+				var log bytes.Buffer
+				cacheFile, cacheable := checkCacheFile(cmd, checks[i], output.format, os.Getenv(githubSHA))
+				if cacheable && copyFile(cacheFile, outcomes[i].file) == nil {
+					progress.finished(checks[i], "reused the cached results of the commit", nil)
+					continue
+				}
+				outcomes[i].metrics = meter.measure(ctx, func() {
+					outcomes[i].incomplete, errs[i] = runCheck(ctx, cmd, checks[i], outcomes[i].file, outcomes[i].timeout, &log)
+				})
+				outcomes[i].canceled = outcomes[i].incomplete && ctx.Err() != nil
+				if errs[i] != nil && continueOnError {
+					outcomes[i].err, outcomes[i].incomplete = errs[i], true
+					errs[i] = nil
+				}
+				status := checkStatus(&outcomes[i], errs[i])
+				if status == checkFinished && cacheable {
+					if err := copyFile(outcomes[i].file, cacheFile); err != nil {
+						fmt.Fprintf(&log, "error caching the results: %v\n", err)
+					}
+				}
+				progress.finished(checks[i], status, log.Bytes())
+			}
+		}()
+	}
+	for i := range checks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	stopHeartbeat()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	switch output.format {
+	case "json":
+		return mergeCheckJSON(output.file, outcomes)
+	case sarif:
+		return mergeCheckSARIF(output.file, outcomes)
+	default:
+		return mergeCheckText(output.file, outcomes)
+	}
+}
+
+// checkFinished is the status of a check that completed.
+const checkFinished = "finished"
+
+// checkStatus is a function to get the status of a check to log once it ran. A check that failed
+// with continue-on-error is also incomplete, so failures are reported before timeouts.
+func checkStatus(outcome *checkOutcome, err error) string {
+	switch {
+	case outcome.canceled:
+		return "stopped by the run timeout"
+	case err != nil || outcome.err != nil:
+		return "failed"
+	case outcome.incomplete:
+		return fmt.Sprintf("timed out after %s", outcome.timeout)
+	}
+	return checkFinished
+}
+
+// runCheck is a function to run a single check of the scorecard command into the results file,
+// logging the output of scorecard to stderr. It returns whether the check timed out.
+func runCheck(ctx context.Context, base *exec.Cmd, check, file string, timeout time.Duration,
+	stderr io.Writer) (bool, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	for attempt := 0; ; attempt++ {
+		//nolint:gosec
+		cmd := exec.CommandContext(ctx, base.Path, withCheck(base.Args[1:], check)...)
+		cmd.Dir = base.Dir
+		cmd.Stderr = stderr
+		err := runScorecardOnce(cmd, file)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return true, nil
+		}
+		if err == nil || attempt >= scorecardRetryPolicy.maxRetries {
+			return false, err
+		}
+		if err := sleepContext(ctx, scorecardRetryPolicy.delay(attempt)); err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+}
+
+// aggregateScore is a function to compute scorecard's aggregate score: the average of the conclusive check
+// scores, weighted by the risk of each check. Checks with an unknown risk do not count.
+func aggregateScore(checks []checkResult) float64 {
+	var total, weights float64
+	for i := range checks {
+		weight, ok := checkRiskWeights[checks[i].Name]
+		if !ok || checks[i].Score == inconclusiveScore {
+			continue
+		}
+		total += weight * float64(checks[i].Score)
+		weights += weight
+	}
+	if weights == 0 {
+		return inconclusiveScore
+	}
+	return math.Round(total/weights*10) / 10
+}
+
+// mergeCheckJSON is a function to merge the JSON results of the checks, recomputing the aggregate score.
+func mergeCheckJSON(file string, outcomes []checkOutcome) error {
+	merged := map[string]interface{}{}
+	var rawChecks []interface{}
+	var checks []checkResult
+	for i := range outcomes {
+		outcome := &outcomes[i]
+		if outcome.incomplete {
+			check := checkResult{
+				Name:    outcome.check,
+				Reason:  outcome.incompleteReason(),
+				Score:   inconclusiveScore,
+				Metrics: outcome.metrics,
+			}
+			checks = append(checks, check)
+			rawChecks = append(rawChecks, check)
+			continue
+		}
+		var results map[string]interface{}
+		if err := readJSONFile(outcome.file, &results); err != nil {
+			return err
+		}
+		result, err := readScorecardResult(outcome.file)
+		if err != nil {
+			return err
+		}
+		if len(merged) == 0 {
+			merged = results
+		}
+		checks = append(checks, result.Checks...)
+		raw, _ := results["checks"].([]interface{})
+		for _, r := range raw {
+			if check, ok := r.(map[string]interface{}); ok && outcome.metrics != nil {
+				check["metrics"] = outcome.metrics
+			}
+		}
+		for j := range result.Checks {
+			result.Checks[j].Metrics = outcome.metrics
+		}
+		rawChecks = append(rawChecks, raw...)
+	}
+	merged["checks"] = rawChecks
+	merged["score"] = aggregateScore(checks)
+	return writeJSONFile(file, merged)
+}
+
+// mergeCheckSARIF is a function to merge the rules and results of the checks into the first SARIF run.
+func mergeCheckSARIF(file string, outcomes []checkOutcome) error {
+	var merged, run, driver map[string]interface{}
+	var rules, results []interface{}
+	for i := range outcomes {
+		if outcomes[i].incomplete {
+			continue
+		}
+		var log map[string]interface{}
+		if err := readJSONFile(outcomes[i].file, &log); err != nil {
+			return err
+		}
+		runs, _ := log["runs"].([]interface{})
+		if len(runs) == 0 {
+			continue
+		}
+		checkRun, _ := runs[0].(map[string]interface{})
+		tool, _ := checkRun["tool"].(map[string]interface{})
+		checkDriver, _ := tool["driver"].(map[string]interface{})
+		if merged == nil {
+			merged, run, driver = log, checkRun, checkDriver
+		}
+		offset := len(rules)
+		checkRules, _ := checkDriver["rules"].([]interface{})
+		rules = append(rules, checkRules...)
+		checkResults, _ := checkRun["results"].([]interface{})
+		for _, r := range checkResults {
+			if result, ok := r.(map[string]interface{}); ok {
+				if index, ok := result["ruleIndex"].(float64); ok {
+					result["ruleIndex"] = int(index) + offset
+				}
+			}
+			results = append(results, r)
+		}
+	}
+	if merged == nil {
+		return fmt.Errorf("error merging the SARIF results: %w", errAllChecksTimedOut)
+	}
+	rules, results = appendCheckErrorNotes(rules, results, outcomes)
+	if driver != nil {
+		driver["rules"] = rules
+	}
+	run["results"] = results
+	return writeJSONFile(file, merged)
+}
+
+// appendCheckErrorNotes is a function to add a note per failed check to the SARIF rules and results,
+// so that code scanning shows that the check did not run rather than silently dropping its alerts.
+func appendCheckErrorNotes(rules, results []interface{}, outcomes []checkOutcome) ([]interface{}, []interface{}) {
+	for i := range outcomes {
+		outcome := &outcomes[i]
+		if outcome.err == nil {
+			continue
+		}
+		ruleID := strings.ReplaceAll(outcome.check, "-", "") + "ErrorID"
+		rules = append(rules, map[string]interface{}{
+			"id":                   ruleID,
+			"name":                 outcome.check + "-Error",
+			"shortDescription":     map[string]string{"text": outcome.check + " failed"},
+			"defaultConfiguration": map[string]string{"level": "note"},
+		})
+		results = append(results, map[string]interface{}{
+			"ruleId":    ruleID,
+			"ruleIndex": len(rules) - 1,
+			"level":     "note",
+			"message":   map[string]string{"text": outcome.incompleteReason()},
+			"locations": []interface{}{
+				map[string]interface{}{
+					"physicalLocation": map[string]interface{}{
+						"artifactLocation": map[string]string{"uri": sarifNoFileURI},
+						"region":           map[string]int{"startLine": 1},
+					},
+				},
+			},
+		})
+	}
+	return rules, results
+}
+
+// mergeCheckText is a function to concatenate the default results of the checks.
+func mergeCheckText(file string, outcomes []checkOutcome) error {
+	var merged bytes.Buffer
+	for i := range outcomes {
+		if outcomes[i].incomplete {
+			fmt.Fprintf(&merged, "Check %s: %s.\n\n", outcomes[i].check, outcomes[i].incompleteReason())
+			continue
+		}
+		data, err := ioutil.ReadFile(outcomes[i].file)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", outcomes[i].file, err)
+		}
+		merged.Write(data)
+		merged.WriteString("\n")
+	}
+	if err := ioutil.WriteFile(file, merged.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	return nil
+}
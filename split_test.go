@@ -0,0 +1,317 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeSplitScorecard scores each check 8, except CI-Tests which hangs.
+const fakeSplitScorecard = `#!/bin/sh
+for last; do :; done
+case "$last" in
+  CI-Tests) exec sleep 5 ;;
+  Fuzzing) echo "panic: crashed" >&2; exit 2 ;;
+  *) echo "{\"repo\":{\"name\":\"github.com/foo/bar\"},\"score\":8,\"checks\":[{\"name\":\"$last\",\"score\":8}]}" ;;
+esac
+`
+
+func Test_parseCheckTimeouts(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]time.Duration
+		wantErr error
+	}{
+		{
+			name: "Empty",
+			want: map[string]time.Duration{},
+		},
+		{
+			name:  "Timeouts",
+			value: "ci-tests=30m, Fuzzing=90s",
+			want:  map[string]time.Duration{"CI-Tests": 30 * time.Minute, "Fuzzing": 90 * time.Second},
+		},
+		{
+			name:    "Missing duration",
+			value:   "CI-Tests",
+			wantErr: errInvalidCheckTimeout,
+		},
+		{
+			name:    "Negative duration",
+			value:   "CI-Tests=-1m",
+			wantErr: errInvalidCheckTimeout,
+		},
+		{
+			name:    "Unknown check",
+			value:   "Typo=1m",
+			wantErr: errUnknownCheck,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseCheckTimeouts(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseCheckTimeouts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !cmp.Equal(got, tt.want) {
+				t.Errorf("parseCheckTimeouts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandChecks(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "Selected checks",
+			args: []string{"scorecard", "--repo", "foo/bar", "--checks", "SAST,Fuzzing", "--format", "json"},
+			want: []string{"SAST", "Fuzzing"},
+		},
+		{
+			name: "Local checks",
+			args: []string{"scorecard", "--local", ".", "--format", "json"},
+			want: localChecks,
+		},
+		{
+			name: "All checks",
+			args: []string{"scorecard", "--repo", "foo/bar", "--format", "json"},
+			want: knownChecks,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, commandChecks(tt.args)); diff != "" {
+				t.Errorf("commandChecks() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_withCheck(t *testing.T) {
+	t.Parallel()
+	got := withCheck([]string{"--repo", "foo/bar", "--checks", "SAST,Fuzzing", "--format", "json"}, "SAST")
+	want := []string{"--repo", "foo/bar", "--format", "json", "--checks", "SAST"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("withCheck() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_aggregateScore(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("testdata/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (10*7.5 + 3*7.5 + 7*5) / (7.5 + 7.5 + 5), without the inconclusive CII-Best-Practices.
+	if got := aggregateScore(result.Checks); got != 6.6 {
+		t.Errorf("aggregateScore() = %v, want %v", got, 6.6)
+	}
+	if got := aggregateScore([]checkResult{{Name: "SAST", Score: inconclusiveScore}}); got != inconclusiveScore {
+		t.Errorf("aggregateScore() = %v, want %v", got, inconclusiveScore)
+	}
+}
+
+func Test_runScorecardPerCheck(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "scorecard")
+	//nolint:gosec
+	if err := ioutil.WriteFile(bin, []byte(fakeSplitScorecard), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", bin, err)
+	}
+	for _, parallelism := range []int{1, 2} {
+		cmd := &exec.Cmd{
+			Path: bin,
+			Args: []string{bin, "--repo", "foo/bar", "--checks", "SAST,CI-Tests", "--format", "json"},
+			Dir:  dir,
+		}
+		output := resultsOutput{format: "json", file: filepath.Join(dir, "results.json")}
+		timeouts := checkTimeouts{timeout: time.Minute, perCheck: map[string]time.Duration{"CI-Tests": 100 * time.Millisecond}}
+
+		if err := runScorecardPerCheck(context.Background(), cmd, output, timeouts, parallelism, nil, false); err != nil {
+			t.Fatalf("runScorecardPerCheck() error = %v", err)
+		}
+		result, err := readScorecardResult(output.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Repo.Name != "github.com/foo/bar" || result.Score != 8 || len(result.Checks) != 2 {
+			t.Fatalf("runScorecardPerCheck() results = %+v", result)
+		}
+		if ci := result.Checks[1]; ci.Name != "CI-Tests" || ci.Score != inconclusiveScore ||
+			ci.Reason != "check timed out after 100ms" {
+			t.Errorf("runScorecardPerCheck() timed out check = %+v", ci)
+		}
+		for _, check := range result.Checks {
+			if check.Metrics == nil || check.Metrics.APICalls != unknownAPICalls {
+				t.Errorf("runScorecardPerCheck() %s metrics = %+v", check.Name, check.Metrics)
+			}
+		}
+	}
+}
+
+func Test_runScorecardPerCheck_runTimeout(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "scorecard")
+	//nolint:gosec
+	if err := ioutil.WriteFile(bin, []byte(fakeSplitScorecard), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", bin, err)
+	}
+	cmd := &exec.Cmd{
+		Path: bin,
+		Args: []string{bin, "--repo", "foo/bar", "--checks", "SAST,CI-Tests,License", "--format", "json"},
+		Dir:  dir,
+	}
+	output := resultsOutput{format: "json", file: filepath.Join(dir, "results.json")}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if err := runScorecardPerCheck(ctx, cmd, output, checkTimeouts{}, 1, nil, false); err != nil {
+		t.Fatalf("runScorecardPerCheck() error = %v", err)
+	}
+	result, err := readScorecardResult(output.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Checks) != 3 || result.Checks[0].Score != 8 || result.Score != 8 {
+		t.Fatalf("runScorecardPerCheck() results = %+v", result)
+	}
+	for _, check := range result.Checks[1:] {
+		if check.Score != inconclusiveScore || check.Reason != "check did not complete before the run timed out" {
+			t.Errorf("runScorecardPerCheck() incomplete check = %+v", check)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the retry policy
+//nolint
+func Test_runScorecardPerCheck_continueOnError(t *testing.T) {
+	defer func(policy retryPolicy) { scorecardRetryPolicy = policy }(scorecardRetryPolicy)
+	scorecardRetryPolicy = retryPolicy{}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "scorecard")
+	//nolint:gosec
+	if err := ioutil.WriteFile(bin, []byte(fakeSplitScorecard), 0o700); err != nil {
+		t.Fatalf("failed to write %s: %v", bin, err)
+	}
+	cmd := &exec.Cmd{
+		Path: bin,
+		Args: []string{bin, "--repo", "foo/bar", "--checks", "SAST,Fuzzing", "--format", "json"},
+		Dir:  dir,
+	}
+	output := resultsOutput{format: "json", file: filepath.Join(dir, "results.json")}
+
+	if err := runScorecardPerCheck(context.Background(), cmd, output, checkTimeouts{}, 1, nil, false); err == nil {
+		t.Fatal("runScorecardPerCheck() without continueOnError succeeded, want an error")
+	}
+	if err := runScorecardPerCheck(context.Background(), cmd, output, checkTimeouts{}, 1, nil, true); err != nil {
+		t.Fatalf("runScorecardPerCheck() error = %v", err)
+	}
+	result, err := readScorecardResult(output.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Checks) != 2 || result.Score != 8 {
+		t.Fatalf("runScorecardPerCheck() results = %+v", result)
+	}
+	if fuzzing := result.Checks[1]; fuzzing.Score != inconclusiveScore ||
+		fuzzing.Reason != "check failed: error running scorecard: exit status 2" {
+		t.Errorf("runScorecardPerCheck() failed check = %+v", fuzzing)
+	}
+}
+
+func Test_checkStatus(t *testing.T) {
+	t.Parallel()
+	errFailed := errors.New("exit status 2")
+	//nolint
+	tests := []struct {
+		name    string
+		outcome checkOutcome
+		err     error
+		want    string
+	}{
+		{
+			name: "Finished",
+			want: "finished",
+		},
+		{
+			name:    "Timed out",
+			outcome: checkOutcome{incomplete: true, timeout: 100 * time.Millisecond},
+			want:    "timed out after 100ms",
+		},
+		{
+			name:    "Stopped by the run timeout",
+			outcome: checkOutcome{incomplete: true, canceled: true},
+			want:    "stopped by the run timeout",
+		},
+		{
+			name: "Failed",
+			err:  errFailed,
+			want: "failed",
+		},
+		{
+			name:    "Failed with continue-on-error",
+			outcome: checkOutcome{err: errFailed, incomplete: true},
+			want:    "failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := checkStatus(&tt.outcome, tt.err); got != tt.want {
+				t.Errorf("checkStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_appendCheckErrorNotes(t *testing.T) {
+	t.Parallel()
+	outcomes := []checkOutcome{
+		{check: "SAST"},
+		{check: "Fuzzing", incomplete: true, err: errAllChecksTimedOut},
+	}
+	rules, results := appendCheckErrorNotes([]interface{}{"SASTID"}, nil, outcomes)
+	if len(rules) != 2 || len(results) != 1 {
+		t.Fatalf("appendCheckErrorNotes() = %v, %v", rules, results)
+	}
+	//nolint:forcetypeassert
+	result := results[0].(map[string]interface{})
+	if result["ruleId"] != "FuzzingErrorID" || result["ruleIndex"] != 1 || result["level"] != "note" {
+		t.Errorf("appendCheckErrorNotes() result = %v", result)
+	}
+}
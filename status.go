@@ -0,0 +1,85 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const commitStatusContext = "scorecard/score"
+
+// scorecardViewerURL is the viewer of the published results of the api_environment, if it has one.
+var scorecardViewerURL = apiEnvironments[defaultAPIEnvironment].viewer
+
+// commitStatus is a GitHub commit status.
+type commitStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// postCommitStatus is a function to set the scorecard/score status of the analyzed commit to the aggregate score.
+func postCommitStatus(ctx context.Context, jsonResultsFile string) error {
+	result, err := readScorecardResult(jsonResultsFile)
+	if err != nil {
+		return err
+	}
+	eventData, err := ioutil.ReadFile(os.Getenv(githubEventPath))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", githubEventPath, err)
+	}
+	status := newCommitStatus(result, scorecardFailOnScore, commitStatusTargetURL())
+	client := newGitHubClient(scorecardGitHubToken)
+	return createCommitStatus(ctx, client, os.Getenv(githubRepository), headSHA(string(eventData)), &status)
+}
+
+// newCommitStatus is a function to create the commit status of the results. The status fails
+// when the aggregate score is below the fail_on_score threshold, if any.
+func newCommitStatus(result *scorecardResult, threshold float64, targetURL string) commitStatus {
+	state := "success"
+	if threshold != noScoreThreshold && result.Score < threshold {
+		state = "failure"
+	}
+	return commitStatus{
+		State:       state,
+		TargetURL:   targetURL,
+		Description: fmt.Sprintf("Aggregate score: %.1f / 10", result.Score),
+		Context:     commitStatusContext,
+	}
+}
+
+// commitStatusTargetURL is a function to get the page the commit status links to: the commit_status_url input,
+// the scorecard viewer for published results of the default branch, or the workflow run.
+func commitStatusTargetURL() string {
+	if scorecardCommitStatusURL != "" {
+		return scorecardCommitStatusURL
+	}
+	if scorecardPublishResults == "true" && scorecardViewerURL != "" &&
+		!strings.Contains(os.Getenv(githubEventName), "pull_request") {
+		return scorecardViewerURL + "?uri=" + url.QueryEscape("github.com/"+os.Getenv(githubRepository))
+	}
+	return workflowRunURL()
+}
+
+// createCommitStatus is a function to create a status on a commit.
+func createCommitStatus(ctx context.Context, client *githubClient, repository, sha string, status *commitStatus) error {
+	return client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/statuses/%s", repository, sha), status, nil)
+}
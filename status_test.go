@@ -0,0 +1,113 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newCommitStatus(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name      string
+		score     float64
+		threshold float64
+		wantState string
+	}{
+		{name: "No threshold", score: 2, threshold: noScoreThreshold, wantState: "success"},
+		{name: "Above threshold", score: 7.5, threshold: 7, wantState: "success"},
+		{name: "Below threshold", score: 6.5, threshold: 7, wantState: "failure"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := newCommitStatus(newTestResult(tt.score), tt.threshold, "https://example.com/report")
+			want := commitStatus{
+				State:       tt.wantState,
+				TargetURL:   "https://example.com/report",
+				Description: got.Description,
+				Context:     commitStatusContext,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("newCommitStatus() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_commitStatusTargetURL(t *testing.T) {
+	// not setting t.Parallel() here because we are mutating the env variables
+	//nolint
+	publish, url := scorecardPublishResults, scorecardCommitStatusURL
+	defer func() { scorecardPublishResults, scorecardCommitStatusURL = publish, url }()
+	for _, env := range []string{githubEventName, githubRepository, githubServerURL, githubRunID} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv(githubRepository, "owner/repo")
+	os.Setenv(githubServerURL, "https://github.com")
+	os.Setenv(githubRunID, "42")
+
+	//nolint
+	tests := []struct {
+		name    string
+		event   string
+		publish string
+		url     string
+		want    string
+	}{
+		{name: "Input", event: "schedule", publish: "true", url: "https://example.com/report", want: "https://example.com/report"},
+		{name: "Published", event: "schedule", publish: "true", want: scorecardViewerURL + "?uri=github.com%2Fowner%2Frepo"},
+		{name: "Pull request", event: "pull_request", publish: "true", want: "https://github.com/owner/repo/actions/runs/42"},
+		{name: "Not published", event: "push", publish: "false", want: "https://github.com/owner/repo/actions/runs/42"},
+	}
+	for _, tt := range tests {
+		os.Setenv(githubEventName, tt.event)
+		scorecardPublishResults, scorecardCommitStatusURL = tt.publish, tt.url
+		if got := commitStatusTargetURL(); got != tt.want {
+			t.Errorf("%s: commitStatusTargetURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_createCommitStatus(t *testing.T) {
+	t.Parallel()
+	var got commitStatus
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/statuses/abc123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("error decoding status: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	status := newCommitStatus(newTestResult(8.2), noScoreThreshold, "")
+	if err := createCommitStatus(context.Background(), client, "owner/repo", "abc123", &status); err != nil {
+		t.Fatalf("createCommitStatus() error = %v", err)
+	}
+	if diff := cmp.Diff(status, got); diff != "" {
+		t.Errorf("createCommitStatus() mismatch (-want +got):\n%s", diff)
+	}
+	if got.Description != "Aggregate score: 8.2 / 10" {
+		t.Errorf("createCommitStatus() description = %q", got.Description)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// writeStepSummary is a function to render the scorecard results as a markdown table.
+func writeStepSummary(writer io.Writer, result *scorecardResult) {
+	fmt.Fprintf(writer, "## Scorecard results for %s\n\n", result.Repo.Name)
+	fmt.Fprintf(writer, "Aggregate score: **%.1f** / 10\n\n", result.Score)
+	fmt.Fprintf(writer, "| Check | Score | Reason |\n")
+	fmt.Fprintf(writer, "| ----- | ----- | ------ |\n")
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		name := check.Name
+		if check.Documentation.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", check.Name, check.Documentation.URL)
+		}
+		reason := markdownCell(check.Reason)
+		if len(check.Annotations) > 0 {
+			reason += fmt.Sprintf(" _(Maintainer annotation: %s)_", markdownCell(strings.Join(check.Annotations, " ")))
+		}
+		fmt.Fprintf(writer, "| %s | %s | %s |\n", name, formatCheckScore(check.Score), reason)
+	}
+	fmt.Fprintln(writer)
+	writeCheckMetrics(writer, result.Checks)
+}
+
+// appendStepSummary is a function to append the scorecard results to the GITHUB_STEP_SUMMARY file.
+func appendStepSummary(summaryFile string, result *scorecardResult) error {
+	//nolint:gosec
+	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", summaryFile, err)
+	}
+	defer f.Close()
+
+	writeStepSummary(f, result)
+	return nil
+}
+
+// formatCheckScore is a function to format a check score, using "?" for inconclusive checks.
+func formatCheckScore(score int) string {
+	if score == inconclusiveScore {
+		return "?"
+	}
+	return strconv.Itoa(score)
+}
+
+// markdownCell is a function to escape text so it fits in a single markdown table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
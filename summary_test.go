@@ -0,0 +1,83 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//nolint:lll
+const wantSummary = `## Scorecard results for github.com/ossf/scorecard-action
+
+Aggregate score: **6.8** / 10
+
+| Check | Score | Reason |
+| ----- | ----- | ------ |
+| [Binary-Artifacts](https://github.com/ossf/scorecard/blob/main/docs/checks.md#binary-artifacts) | 10 | no binaries found in the repo |
+| [Branch-Protection](https://github.com/ossf/scorecard/blob/main/docs/checks.md#branch-protection) | 3 | branch protection is not maximal on development and all release branches |
+| [CII-Best-Practices](https://github.com/ossf/scorecard/blob/main/docs/checks.md#cii-best-practices) | ? | internal error: repo unreachable |
+| [Pinned-Dependencies](https://github.com/ossf/scorecard/blob/main/docs/checks.md#pinned-dependencies) | 7 | dependency not pinned by hash detected -- score normalized to 7 |
+
+`
+
+func Test_writeStepSummary(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("./testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	writer := &bytes.Buffer{}
+	writeStepSummary(writer, result)
+	if diff := cmp.Diff(wantSummary, writer.String()); diff != "" {
+		t.Errorf("writeStepSummary() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_appendStepSummary(t *testing.T) {
+	t.Parallel()
+	result, err := readScorecardResult("./testdata/results.json")
+	if err != nil {
+		t.Fatalf("readScorecardResult() error = %v", err)
+	}
+	summaryFile := filepath.Join(t.TempDir(), "summary.md")
+	if err := ioutil.WriteFile(summaryFile, []byte("previous step\n"), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", summaryFile, err)
+	}
+	if err := appendStepSummary(summaryFile, result); err != nil {
+		t.Fatalf("appendStepSummary() error = %v", err)
+	}
+	got, err := ioutil.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", summaryFile, err)
+	}
+	if !strings.HasPrefix(string(got), "previous step\n") {
+		t.Errorf("appendStepSummary() overwrote the existing summary")
+	}
+	if !strings.HasSuffix(string(got), wantSummary) {
+		t.Errorf("appendStepSummary() did not append the results summary")
+	}
+}
+
+func Test_markdownCell(t *testing.T) {
+	t.Parallel()
+	if got := markdownCell("a | b\nc"); got != "a \\| b c" {
+		t.Errorf("markdownCell() = %q", got)
+	}
+}
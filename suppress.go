@@ -0,0 +1,291 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultSuppressionsFile is the suppressions file read from the workspace when ignore_file is not set.
+	defaultSuppressionsFile = ".scorecard-ignore.yml"
+	suppressionsVersion     = 1
+	suppressionDateLayout   = "2006-01-02"
+)
+
+var errInvalidSuppressions = errors.New("invalid suppressions")
+
+// suppressions are the findings accepted by the maintainers, which are left out of the SARIF results.
+type suppressions struct {
+	Suppressions []suppression `yaml:"suppressions"`
+	Version      int           `yaml:"version"`
+}
+
+// suppression suppresses the findings of a check. Empty conditions match any finding of the check.
+type suppression struct {
+	Check string `yaml:"check"`
+	// Path is a glob matching the file of the finding, e.g. .github/workflows/*.yml.
+	Path string `yaml:"path"`
+	// Finding matches the findings whose message contains it.
+	Finding       string `yaml:"finding"`
+	Justification string `yaml:"justification"`
+	// Expires is the last day the suppression applies, as YYYY-MM-DD.
+	Expires string `yaml:"expires"`
+	// Level downgrades the findings to a SARIF level instead of removing them.
+	Level string `yaml:"level"`
+}
+
+// suppressedFinding is a SARIF finding a suppression matched.
+type suppressedFinding struct {
+	check         string
+	path          string
+	message       string
+	justification string
+	level         string
+}
+
+// readSuppressions is a function to read and validate a suppressions file.
+func readSuppressions(file string) (*suppressions, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+	var s suppressions
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", file, err)
+	}
+	if s.Version != suppressionsVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", errInvalidSuppressions, file, s.Version)
+	}
+	for i := range s.Suppressions {
+		rule := &s.Suppressions[i]
+		if rule.Check == "" {
+			return nil, fmt.Errorf("%w: %s: suppression %d has no check", errInvalidSuppressions, file, i+1)
+		}
+		// Custom checks are not known checks, so only the names of the known checks are canonicalized.
+		if check, err := canonicalCheckName(rule.Check); err == nil {
+			rule.Check = check
+		}
+		if strings.TrimSpace(rule.Justification) == "" {
+			return nil, fmt.Errorf("%w: %s: suppression %d has no justification", errInvalidSuppressions, file, i+1)
+		}
+		if _, err := path.Match(rule.Path, ""); err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid path %q in suppression %d", errInvalidSuppressions, file, rule.Path, i+1)
+		}
+		if _, err := rule.expiry(); err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid expiry date %q in suppression %d", errInvalidSuppressions, file,
+				rule.Expires, i+1)
+		}
+		if rule.Level != "" && !sarifLevelNames[rule.Level] {
+			return nil, fmt.Errorf("%w: %s: invalid level %q in suppression %d", errInvalidSuppressions, file, rule.Level,
+				i+1)
+		}
+	}
+	return &s, nil
+}
+
+// loadSuppressions is a function to read the suppressions file of the input, relative to the workspace,
+// or the default suppressions file of the workspace if it exists. It returns nil when there is none.
+func loadSuppressions(file, workspace string) (*suppressions, error) {
+	optional := file == ""
+	if optional {
+		file = defaultSuppressionsFile
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(workspace, file)
+	}
+	if _, err := os.Stat(file); optional && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return readSuppressions(file)
+}
+
+// expiry is a function to get the time the suppression stops applying, i.e. the day after its expiry date,
+// or the zero time when it does not expire.
+func (s *suppression) expiry() (time.Time, error) {
+	if s.Expires == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(suppressionDateLayout, s.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing %q: %w", s.Expires, err)
+	}
+	return date.AddDate(0, 0, 1), nil
+}
+
+// active is a function to get the suppressions that have not expired at now, warning about the expired ones
+// so that the accepted findings get reviewed again.
+func (s *suppressions) active(writer io.Writer, now time.Time) []suppression {
+	var active []suppression
+	for i := range s.Suppressions {
+		rule := s.Suppressions[i]
+		if expiry, _ := rule.expiry(); !expiry.IsZero() && !now.Before(expiry) {
+			fmt.Fprintf(writer, "::warning::The suppression of %s findings expired on %s: %s\n", rule.Check,
+				rule.Expires, rule.Justification)
+			continue
+		}
+		active = append(active, rule)
+	}
+	return active
+}
+
+// matches is a function to check if the suppression matches a finding of the check at the path.
+func (s *suppression) matches(check, file, message string) bool {
+	if !strings.EqualFold(s.Check, check) {
+		return false
+	}
+	if s.Path != "" {
+		if ok, _ := path.Match(s.Path, file); !ok {
+			return false
+		}
+	}
+	return strings.Contains(message, s.Finding)
+}
+
+// suppressSARIF is a function to remove the findings of the SARIF results the active suppressions match,
+// or downgrade them to the level of the suppression. It returns the suppressed findings.
+func suppressSARIF(writer io.Writer, file string, s *suppressions, now time.Time) ([]suppressedFinding, error) {
+	active := s.active(writer, now)
+	if len(active) == 0 {
+		return nil, nil
+	}
+	var log map[string]interface{}
+	if err := readJSONFile(file, &log); err != nil {
+		return nil, err
+	}
+	var suppressed []suppressedFinding
+	runs, _ := log["runs"].([]interface{})
+	for _, r := range runs {
+		run, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		checkNames := sarifCheckNames(run)
+		results, _ := run["results"].([]interface{})
+		kept := make([]interface{}, 0, len(results))
+		for _, res := range results {
+			result, ok := res.(map[string]interface{})
+			if !ok {
+				kept = append(kept, res)
+				continue
+			}
+			ruleID, _ := result["ruleId"].(string)
+			finding := suppressedFinding{check: checkNames[ruleID], path: sarifResultURI(result)}
+			if message, ok := result["message"].(map[string]interface{}); ok {
+				text, _ := message["text"].(string)
+				finding.message = sarifScorePrefix.ReplaceAllString(text, "")
+			}
+			matched := false
+			for i := range active {
+				if active[i].matches(finding.check, finding.path, finding.message) {
+					finding.justification, finding.level = active[i].Justification, active[i].Level
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				kept = append(kept, res)
+				continue
+			}
+			suppressed = append(suppressed, finding)
+			if finding.level != "" {
+				result["level"] = finding.level
+				kept = append(kept, res)
+			}
+		}
+		run["results"] = kept
+	}
+	if err := writeJSONFile(file, log); err != nil {
+		return nil, err
+	}
+	return suppressed, nil
+}
+
+// sarifCheckNames is a function to map the rule IDs of a SARIF run to the names of their checks.
+func sarifCheckNames(run map[string]interface{}) map[string]string {
+	tool, _ := run["tool"].(map[string]interface{})
+	driver, _ := tool["driver"].(map[string]interface{})
+	rules, _ := driver["rules"].([]interface{})
+	names := make(map[string]string, len(rules))
+	for _, r := range rules {
+		if rule, ok := r.(map[string]interface{}); ok {
+			id, _ := rule["id"].(string)
+			name, _ := rule["name"].(string)
+			names[id] = name
+		}
+	}
+	return names
+}
+
+// sarifResultURI is a function to get the file of the first location of a SARIF result.
+func sarifResultURI(result map[string]interface{}) string {
+	locations, _ := result["locations"].([]interface{})
+	if len(locations) == 0 {
+		return ""
+	}
+	location, _ := locations[0].(map[string]interface{})
+	physical, _ := location["physicalLocation"].(map[string]interface{})
+	artifact, _ := physical["artifactLocation"].(map[string]interface{})
+	uri, _ := artifact["uri"].(string)
+	return uri
+}
+
+// writeSuppressedFindings is a function to render the suppressed findings as a markdown table.
+func writeSuppressedFindings(writer io.Writer, findings []suppressedFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "### Suppressed findings\n\n")
+	fmt.Fprintf(writer, "| Check | Finding | Location | Justification |\n")
+	fmt.Fprintf(writer, "| ----- | ------- | -------- | ------------- |\n")
+	for _, f := range findings {
+		location := f.path
+		if location == sarifNoFileURI {
+			location = ""
+		}
+		justification := markdownCell(f.justification)
+		if f.level != "" {
+			justification = fmt.Sprintf("%s (downgraded to %s)", justification, f.level)
+		}
+		fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", f.check, markdownCell(f.message), markdownCell(location),
+			justification)
+	}
+	fmt.Fprintln(writer)
+}
+
+// appendSuppressedFindings is a function to append the suppressed findings to the GITHUB_STEP_SUMMARY file.
+func appendSuppressedFindings(summaryFile string, findings []suppressedFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	//nolint:gosec
+	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", summaryFile, err)
+	}
+	defer f.Close()
+
+	writeSuppressedFindings(f, findings)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_readSuppressions(t *testing.T) {
+	t.Parallel()
+	//nolint
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			content: "version: 1\nsuppressions:\n" +
+				"  - check: pinned-dependencies\n    path: .github/*.yml\n    justification: accepted\n" +
+				"    expires: 2026-12-31\n    level: note\n",
+		},
+		{
+			name:    "unsupported version",
+			content: "version: 2\n",
+			wantErr: errInvalidSuppressions,
+		},
+		{
+			name:    "no justification",
+			content: "version: 1\nsuppressions:\n  - check: SAST\n",
+			wantErr: errInvalidSuppressions,
+		},
+		{
+			name:    "invalid expiry",
+			content: "version: 1\nsuppressions:\n  - check: SAST\n    justification: accepted\n    expires: soon\n",
+			wantErr: errInvalidSuppressions,
+		},
+		{
+			name:    "invalid level",
+			content: "version: 1\nsuppressions:\n  - check: SAST\n    justification: accepted\n    level: info\n",
+			wantErr: errInvalidSuppressions,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			file := filepath.Join(t.TempDir(), defaultSuppressionsFile)
+			if err := ioutil.WriteFile(file, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			s, err := readSuppressions(file)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readSuppressions() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && s.Suppressions[0].Check != "Pinned-Dependencies" {
+				t.Errorf("readSuppressions() check = %q, want the canonical name", s.Suppressions[0].Check)
+			}
+		})
+	}
+}
+
+func Test_loadSuppressions(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	if s, err := loadSuppressions("", workspace); s != nil || err != nil {
+		t.Errorf("loadSuppressions() without a file = %v, %v, want nil", s, err)
+	}
+	if _, err := loadSuppressions("missing.yml", workspace); err == nil {
+		t.Error("loadSuppressions() with a missing file succeeded, want an error")
+	}
+	if s, err := loadSuppressions("policies/scorecard-ignore.yml", "."); err != nil || len(s.Suppressions) != 2 {
+		t.Errorf("loadSuppressions() example = %v, %v", s, err)
+	}
+}
+
+func Test_suppressSARIF(t *testing.T) {
+	t.Parallel()
+	data, err := ioutil.ReadFile("testdata/results.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "results.sarif")
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &suppressions{Version: 1, Suppressions: []suppression{
+		{Check: "Pinned-Dependencies", Path: ".github/workflows/*.yaml", Justification: "accepted"},
+		{Check: "Branch-Protection", Justification: "expired", Expires: "2026-01-31"},
+	}}
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	suppressed, err := suppressSARIF(&out, file, s, now)
+	if err != nil {
+		t.Fatalf("suppressSARIF() error = %v", err)
+	}
+	if len(suppressed) != 1 || suppressed[0].check != "Pinned-Dependencies" ||
+		suppressed[0].path != ".github/workflows/tests.yaml" {
+		t.Fatalf("suppressSARIF() = %+v", suppressed)
+	}
+	if !strings.Contains(out.String(), "::warning::The suppression of Branch-Protection findings expired on 2026-01-31") {
+		t.Errorf("suppressSARIF() logs = %q, want a warning about the expired suppression", out.String())
+	}
+	findings, err := readSARIFFindings(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].check != "Branch-Protection" {
+		t.Errorf("suppressSARIF() kept %+v, want the Branch-Protection finding", findings)
+	}
+
+	var summary bytes.Buffer
+	writeSuppressedFindings(&summary, suppressed)
+	if !strings.Contains(summary.String(), "| Pinned-Dependencies | dependency not pinned by hash detected") {
+		t.Errorf("writeSuppressedFindings() = %q", summary.String())
+	}
+}
+
+func Test_suppression_matches(t *testing.T) {
+	t.Parallel()
+	rule := suppression{Check: "Pinned-Dependencies", Path: "*.yml", Finding: "pip"}
+	//nolint
+	tests := []struct {
+		name    string
+		check   string
+		file    string
+		message string
+		want    bool
+	}{
+		{name: "match", check: "Pinned-Dependencies", file: "a.yml", message: "pip not pinned", want: true},
+		{name: "other check", check: "SAST", file: "a.yml", message: "pip not pinned"},
+		{name: "other path", check: "Pinned-Dependencies", file: "dir/a.yml", message: "pip not pinned"},
+		{name: "other finding", check: "Pinned-Dependencies", file: "a.yml", message: "npm not pinned"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := rule.matches(tt.check, tt.file, tt.message); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	errInvalidRepository  = errors.New("repository must be the full name of a repository, e.g. owner/name")
+	errRepositoryConflict = errors.New("repository analyzes another repository, so it cannot be combined with")
+)
+
+// repositoryPattern matches the full name of a GitHub repository.
+var repositoryPattern = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+// isRepositoryName is a function to check a repository is the full name of a GitHub repository, e.g.
+// owner/name. GitHub reserves the names . and .., which would otherwise turn into other API paths.
+func isRepositoryName(repository string) bool {
+	if !repositoryPattern.MatchString(repository) {
+		return false
+	}
+	name := repository[strings.Index(repository, "/")+1:]
+	return name != "." && name != ".."
+}
+
+// validateRepository is a function to check the repository to analyze instead of the repository
+// of the workflow. The workflow's checkout and event are not the other repository's, so the inputs
+// and events that analyze them cannot be combined with it.
+func validateRepository(repository, eventName string) error {
+	if !isRepositoryName(repository) {
+		return fmt.Errorf("%w: %q", errInvalidRepository, repository)
+	}
+	conflicts := []struct {
+		name string
+		set  bool
+	}{
+		{"pull_request events", strings.Contains(eventName, "pull_request")},
+		{"local_path", scorecardLocalPath != ""},
+		{"sub_paths", len(scorecardSubPaths) > 0},
+		{"organization", scorecardOrganization != ""},
+		{"repos_file", scorecardReposFile != ""},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return fmt.Errorf("%w %s", errRepositoryConflict, c.name)
+		}
+	}
+	return nil
+}
+
+// useTargetRepository is a function to analyze the repository in place of the repository of the workflow,
+// e.g. from a scanner repository on workflow_dispatch. Everything that reads GITHUB_REPOSITORY, from
+// running scorecard to publishing the results, then works on that repository.
+func useTargetRepository(writer io.Writer, repository string) error {
+	if err := os.Setenv(githubRepository, repository); err != nil {
+		return fmt.Errorf("error setting %s: %w", githubRepository, err)
+	}
+	fmt.Fprintf(writer, "Analyzing %s instead of the repository of the workflow.\n", repository)
+	return nil
+}
+
+// useTargetCommit is a function to point GITHUB_SHA and GITHUB_REF to the head of the default branch
+// of the analyzed repository, so that the commit of the results, e.g. in the history, check runs
+// and code scanning, is the analyzed one rather than the workflow's.
+func useTargetCommit(ctx context.Context, client *githubClient, repository, defaultBranch string) error {
+	branch := strings.TrimPrefix(defaultBranch, "refs/heads/")
+	sha, err := resolveActionRef(ctx, client, repository, branch)
+	if err != nil {
+		return fmt.Errorf("error getting the head of %s in %s: %w", branch, repository, err)
+	}
+	if err := os.Setenv(githubSHA, sha); err != nil {
+		return fmt.Errorf("error setting %s: %w", githubSHA, err)
+	}
+	if err := os.Setenv(githubRef, defaultBranch); err != nil {
+		return fmt.Errorf("error setting %s: %w", githubRef, err)
+	}
+	return nil
+}
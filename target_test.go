@@ -0,0 +1,84 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_validateRepository(t *testing.T) {
+	localPath := scorecardLocalPath
+	defer func() { scorecardLocalPath = localPath }()
+	tests := []struct {
+		name       string
+		repository string
+		eventName  string
+		localPath  string
+		wantErr    error
+	}{
+		{name: "Repository", repository: "ossf/scorecard", eventName: "workflow_dispatch"},
+		{name: "Dots and dashes", repository: "my-org/repo.name_x", eventName: "schedule"},
+		{name: "No owner", repository: "scorecard", wantErr: errInvalidRepository},
+		{name: "URL", repository: "https://github.com/ossf/scorecard", wantErr: errInvalidRepository},
+		{name: "Dot", repository: "ossf/.", wantErr: errInvalidRepository},
+		{name: "Dot dot", repository: "ossf/..", wantErr: errInvalidRepository},
+		{name: "Leading dots", repository: "ossf/..scorecard", eventName: "schedule"},
+		{name: "Pull request", repository: "ossf/scorecard", eventName: "pull_request", wantErr: errRepositoryConflict},
+		{name: "Local path", repository: "ossf/scorecard", localPath: ".", wantErr: errRepositoryConflict},
+	}
+	for _, tt := range tests {
+		scorecardLocalPath = tt.localPath
+		if err := validateRepository(tt.repository, tt.eventName); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: validateRepository() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the env variables
+//nolint
+func Test_useTargetRepository(t *testing.T) {
+	t.Setenv(githubRepository, "scanner/repo")
+	t.Setenv(githubSHA, "workflow-sha")
+	t.Setenv(githubRef, "refs/heads/scanner")
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/ossf/scorecard/commits/main" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"sha": "a1b2c3"}`)
+	}))
+
+	if err := useTargetRepository(ioutil.Discard, "ossf/scorecard"); err != nil {
+		t.Fatal(err)
+	}
+	if err := useTargetCommit(context.Background(), client, os.Getenv(githubRepository), "refs/heads/main"); err != nil {
+		t.Fatal(err)
+	}
+	for env, want := range map[string]string{
+		githubRepository: "ossf/scorecard",
+		githubSHA:        "a1b2c3",
+		githubRef:        "refs/heads/main",
+	} {
+		if got := os.Getenv(env); got != want {
+			t.Errorf("%s = %q, want %q", env, got, want)
+		}
+	}
+}
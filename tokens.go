@@ -0,0 +1,83 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// tokenUses is a function to list what the read token, repo_token, and the write token, github_token,
+// are used for in the run, by input.
+func tokenUses() (read, write []string) {
+	read = []string{"running scorecard", "reading the repository information"}
+	if scorecardRepository != "" || scorecardRef != "" {
+		read = append(read, "resolving the analyzed commit")
+	}
+	if scorecardSkipUnchanged == "true" {
+		read = append(read, "skip_unchanged")
+	}
+	//nolint
+	writes := []struct {
+		input string
+		set   bool
+	}{
+		{inputprcomment, scorecardPRComment == "true"},
+		{inputcheckrun, scorecardCheckRun == "true"},
+		{inputcommitstatus, scorecardCommitStatus == "true"},
+		{inputuploadsarif, scorecardUploadSARIF == "true"},
+		{inputbaselinesource, scorecardBaselineSource == baselineSourceArtifact},
+		{inputsavehistory, scorecardSaveHistory == "true"},
+		{inputbadgebranch, scorecardBadgeBranch != ""},
+		{inputbadgegist, scorecardBadgeGist != ""},
+		{inputopenissues, scorecardOpenIssues == "true"},
+		{inputremediate, len(scorecardRemediations) > 0},
+	}
+	for _, w := range writes {
+		if w.set {
+			write = append(write, inputName(w.input))
+		}
+	}
+	return read, write
+}
+
+// reportTokens is a function to print which token is used for what, so that each token can be given
+// only the permissions it needs, and to warn when a single token is used for both.
+func reportTokens(writer io.Writer) {
+	read, write := tokenUses()
+	readToken, writeToken := "repo_token", "github_token"
+	if scorecardAppID != 0 && os.Getenv(inputrepotoken) == "" {
+		readToken = "the GitHub App installation token"
+	}
+	if scorecardAppID != 0 && scorecardGitHubToken == "" {
+		writeToken = "the GitHub App installation token"
+	}
+	if scorecardWorkflowToken {
+		readToken = "github_token"
+	}
+	fmt.Fprintf(writer, "Tokens:\n  %s (read): %s\n", readToken, strings.Join(read, ", "))
+	if len(write) > 0 {
+		fmt.Fprintf(writer, "  %s (write): %s\n", writeToken, strings.Join(write, ", "))
+	}
+
+	// Pull requests from forks and public repositories without repo_token use the workflow token for both.
+	if len(write) > 0 && !scorecardForkPullRequest && !scorecardWorkflowToken && scorecardGitHubToken != "" &&
+		os.Getenv(githubAuthToken) == scorecardGitHubToken {
+		fmt.Fprintf(writer, "::warning::repo_token and github_token are the same token, which then needs the "+
+			"permissions of both. Pass a read-only token as repo_token and a token scoped to %s as github_token.\n",
+			strings.Join(write, ", "))
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_reportTokens(t *testing.T) {
+	comment, checkRun, token := scorecardPRComment, scorecardCheckRun, scorecardGitHubToken
+	defer func() { scorecardPRComment, scorecardCheckRun, scorecardGitHubToken = comment, checkRun, token }()
+	scorecardPRComment, scorecardCheckRun = "true", "true"
+
+	tests := []struct {
+		name        string
+		readToken   string
+		writeToken  string
+		wantWarning bool
+	}{
+		{name: "Separate tokens", readToken: "read-pat", writeToken: "ghs_workflow"},
+		{name: "Same token", readToken: "union-pat", writeToken: "union-pat", wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Setenv(githubAuthToken, tt.readToken)
+		scorecardGitHubToken = tt.writeToken
+
+		var out bytes.Buffer
+		reportTokens(&out)
+		if want := "repo_token (read): running scorecard"; !strings.Contains(out.String(), want) {
+			t.Errorf("%s: reportTokens() = %q, want %q", tt.name, out.String(), want)
+		}
+		if want := "github_token (write): pr_comment, check_run\n"; !strings.Contains(out.String(), want) {
+			t.Errorf("%s: reportTokens() = %q, want %q", tt.name, out.String(), want)
+		}
+		if got := strings.Contains(out.String(), "::warning::"); got != tt.wantWarning {
+			t.Errorf("%s: reportTokens() warned = %v, want %v", tt.name, got, tt.wantWarning)
+		}
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_tokenUses(t *testing.T) {
+	history, ref := scorecardSaveHistory, scorecardRef
+	defer func() { scorecardSaveHistory, scorecardRef = history, ref }()
+	scorecardSaveHistory, scorecardRef = "true", "v1.0.0"
+
+	read, write := tokenUses()
+	if diff := cmp.Diff([]string{"running scorecard", "reading the repository information",
+		"resolving the analyzed commit"}, read); diff != "" {
+		t.Errorf("tokenUses() read mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"save_history"}, write); diff != "" {
+		t.Errorf("tokenUses() write mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// sslCertDir lists the directories Go programs, scorecard included, load additional root certificates from.
+const sslCertDir = "SSL_CERT_DIR"
+
+var errInvalidCABundle = errors.New("ca_bundle has no PEM encoded certificate")
+
+// newHTTPClient is a function to create an HTTP client trusting the PEM encoded CA bundle on top of
+// the system roots. Like http.DefaultClient, it honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func newHTTPClient(caBundle []byte) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, errInvalidCABundle
+	}
+	//nolint:forcetypeassert
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// useCABundle is a function to trust the CA bundle file in the requests of the action and of scorecard,
+// e.g. behind a proxy intercepting TLS. scorecard gets the bundle through SSL_CERT_DIR, which adds
+// to the system roots rather than replacing them.
+func useCABundle(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", file, err)
+	}
+	client, err := newHTTPClient(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, file)
+	}
+
+	// The directory only holds the bundle, since every file of SSL_CERT_DIR is loaded.
+	dir, err := ioutil.TempDir("", "scorecard-ca-")
+	if err != nil {
+		return fmt.Errorf("error creating the CA bundle directory: %w", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "ca-bundle.pem"), data, 0o600); err != nil {
+		return fmt.Errorf("error writing the CA bundle: %w", err)
+	}
+	if existing := os.Getenv(sslCertDir); existing != "" {
+		dir = dir + string(os.PathListSeparator) + existing
+	}
+	if err := os.Setenv(sslCertDir, dir); err != nil {
+		return fmt.Errorf("error setting %s: %w", sslCertDir, err)
+	}
+	scorecardHTTPClient = client
+	return nil
+}
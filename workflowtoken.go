@@ -0,0 +1,79 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// scorecardWorkflowToken is true when scorecard runs with the workflow's GITHUB_TOKEN.
+var scorecardWorkflowToken = false
+
+// usesWorkflowToken is a function to check if the run falls back to the workflow's GITHUB_TOKEN
+// to run scorecard: public repositories can be analyzed without a personal access token.
+func usesWorkflowToken() bool {
+	return os.Getenv(githubAuthToken) == "" && scorecardAppID == 0 && scorecardGitHubToken != "" &&
+		scorecardPrivateRepository != "true" && !offlineLocal()
+}
+
+// repositoryInformationToken is a function to get the token the repository information is fetched with.
+// Without a repo_token, it is the workflow's GITHUB_TOKEN, since the information tells whether
+// the repository is public and can be analyzed with that token.
+func repositoryInformationToken(token string) string {
+	if token == "" {
+		return scorecardGitHubToken
+	}
+	return token
+}
+
+// useWorkflowToken is a function to run scorecard with the workflow's GITHUB_TOKEN. The token cannot
+// have admin access to the repository, so the checks that need it are skipped and reported,
+// rather than returning inconclusive scores.
+func useWorkflowToken(writer io.Writer) error {
+	if err := os.Setenv(githubAuthToken, scorecardGitHubToken); err != nil {
+		return fmt.Errorf("error setting %s: %w", githubAuthToken, err)
+	}
+	scorecardWorkflowToken = true
+	admin := make(map[string]string)
+	for _, p := range checkPermissions {
+		if p.admin {
+			admin[p.check] = p.use
+		}
+	}
+	checks := scorecardChecks
+	if len(checks) == 0 {
+		checks = knownChecks
+	}
+	var selected, skipped []string
+	for _, check := range checks {
+		if use, ok := admin[check]; ok {
+			skipped = append(skipped, fmt.Sprintf("%s (needs admin access to %s)", check, use))
+			continue
+		}
+		selected = append(selected, check)
+	}
+	scorecardChecks = selected
+
+	fmt.Fprintf(writer, "::notice::repo_token is not set: analyzing the public repository with the workflow's "+
+		"GITHUB_TOKEN.")
+	if len(skipped) > 0 {
+		fmt.Fprintf(writer, " Skipped checks: %s. Set repo_token to a read-only personal access token of an admin "+
+			"to run them.", strings.Join(skipped, ", "))
+	}
+	fmt.Fprintln(writer)
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// not setting t.Parallel() here because we are mutating the env variables and the inputs
+//nolint
+func Test_useWorkflowToken(t *testing.T) {
+	checks, token, private := scorecardChecks, scorecardGitHubToken, scorecardPrivateRepository
+	defer func() {
+		scorecardChecks, scorecardGitHubToken, scorecardPrivateRepository = checks, token, private
+		scorecardWorkflowToken = false
+	}()
+	tests := []struct {
+		name        string
+		checks      []string
+		want        []string
+		wantSkipped bool
+	}{
+		{
+			name:        "All checks",
+			want:        []string{"Binary-Artifacts", "CI-Tests"},
+			wantSkipped: true,
+		},
+		{
+			name:        "Selected checks",
+			checks:      []string{"Branch-Protection", "License"},
+			want:        []string{"License"},
+			wantSkipped: true,
+		},
+		{
+			name:   "No admin check",
+			checks: []string{"License"},
+			want:   []string{"License"},
+		},
+	}
+	for _, tt := range tests {
+		t.Setenv(githubAuthToken, "")
+		scorecardChecks, scorecardGitHubToken, scorecardPrivateRepository = tt.checks, "ghs_workflow", "false"
+		if !usesWorkflowToken() {
+			t.Fatalf("%s: usesWorkflowToken() = false, want true", tt.name)
+		}
+
+		var out bytes.Buffer
+		if err := useWorkflowToken(&out); err != nil {
+			t.Fatal(err)
+		}
+		if got := os.Getenv(githubAuthToken); got != "ghs_workflow" {
+			t.Errorf("%s: %s = %q, want the workflow token", tt.name, githubAuthToken, got)
+		}
+		got := scorecardChecks
+		if tt.checks == nil {
+			got = got[:2]
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("%s: useWorkflowToken() checks mismatch (-want +got):\n%s", tt.name, diff)
+		}
+		if skipped := strings.Contains(out.String(), "Skipped checks: Branch-Protection"); skipped != tt.wantSkipped {
+			t.Errorf("%s: useWorkflowToken() notice = %q", tt.name, out.String())
+		}
+	}
+
+	scorecardPrivateRepository = "true"
+	t.Setenv(githubAuthToken, "")
+	if usesWorkflowToken() {
+		t.Errorf("usesWorkflowToken() = true for a private repository")
+	}
+}
+
+// not setting t.Parallel() here because we are mutating the inputs
+//nolint
+func Test_repositoryInformationToken(t *testing.T) {
+	defer func(token string) { scorecardGitHubToken = token }(scorecardGitHubToken)
+	scorecardGitHubToken = "ghs_workflow"
+	if got := repositoryInformationToken(""); got != "ghs_workflow" {
+		t.Errorf("repositoryInformationToken() = %v, want the workflow token", got)
+	}
+	if got := repositoryInformationToken("ghp_repo"); got != "ghp_repo" {
+		t.Errorf("repositoryInformationToken() = %v, want repo_token", got)
+	}
+}